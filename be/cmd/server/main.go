@@ -16,8 +16,10 @@ import (
 	"signal-module/pkg/config"
 	"signal-module/pkg/database"
 	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
 	"signal-module/pkg/queue"
 	"signal-module/pkg/redis"
+	"signal-module/pkg/storage"
 	"signal-module/pkg/utils"
 
 	"github.com/gin-contrib/cors"
@@ -49,6 +51,12 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	blobStore, err := storage.New(&cfg.Storage)
+	if err != nil {
+		appLogger.Error("첨부파일 저장소 초기화 실패", err)
+		os.Exit(1)
+	}
+
 	jwtManager := utils.NewJWTManager(&cfg.JWT)
 	jobQueue := queue.New(redisClient)
 
@@ -57,20 +65,36 @@ func main() {
 	chatRepo := repositories.NewChatRepository(db.DB)
 	buddyRepo := repositories.NewBuddyRepository(db.DB)
 
-	userService := services.NewUserService(userRepo, jwtManager, appLogger)
+	userService := services.NewUserService(userRepo, jwtManager, jobQueue, appLogger)
+	presenceService := services.NewPresenceService(redisClient, userRepo, appLogger)
 	signalService := services.NewSignalService(signalRepo, userRepo, redisClient, jobQueue, appLogger)
-	chatService := services.NewChatService(chatRepo, signalRepo, redisClient, appLogger)
+	chatNotificationService := services.NewChatNotificationService(chatRepo, redisClient, jobQueue, presenceService, appLogger)
+	chatModerator := services.NewChatModerator(&cfg.Chat, redisClient, appLogger)
+	chatService := services.NewChatService(chatRepo, signalRepo, signalService, redisClient, chatNotificationService, chatModerator, appLogger)
+	chatAttachmentService := services.NewChatAttachmentService(chatRepo, blobStore, appLogger)
 	buddyService := services.NewBuddyService(buddyRepo, userRepo, appLogger)
-	websocketService := services.NewWebSocketService(appLogger, redisClient)
+	websocketService := services.NewWebSocketService(appLogger, redisClient, presenceService, cfg.Server.Origins())
+	chatWebSocketService := services.NewChatWebSocketService(db.DB, redisClient, chatService, presenceService, cfg.Server.Origins(), appLogger)
+	wsTicketService := services.NewWebSocketTicketService(redisClient, userRepo, appLogger)
+	queueAdminService := services.NewQueueAdminService(jobQueue, appLogger)
 
-	userHandler := handlers.NewUserHandler(userService, appLogger)
+	userHandler := handlers.NewUserHandler(userService, presenceService, appLogger)
 	authHandler := handlers.NewAuthHandler(userService, appLogger)
 	oauthHandler := handlers.NewOAuthHandler(cfg, userService, appLogger)
-	signalHandler := handlers.NewSignalHandler(signalService, appLogger)
-	chatHandler := handlers.NewChatHandler(chatService, websocketService, appLogger)
-	buddyHandler := handlers.NewBuddyHandler(buddyService, appLogger)
+	signalHandler := handlers.NewSignalHandler(signalService, presenceService, appLogger)
+	chatHandler := handlers.NewChatHandler(chatService, chatAttachmentService, chatWebSocketService, appLogger)
+	buddyHandler := handlers.NewBuddyHandler(buddyService, presenceService, appLogger)
+	websocketHandler := handlers.NewWebSocketHandler(wsTicketService, appLogger)
+	queueAdminHandler := handlers.NewQueueAdminHandler(queueAdminService, appLogger)
+
+	// 채팅 오프라인 알림 발송 루프
+	notifyCtx, stopNotify := context.WithCancel(context.Background())
+	go chatNotificationService.Run(notifyCtx)
 
-	router := setupRouter(cfg, userHandler, authHandler, oauthHandler, signalHandler, chatHandler, buddyHandler, websocketService, jwtManager, appLogger)
+	// 시그널 변경 이벤트 구독 (지도 WebSocket)
+	go websocketService.Run(notifyCtx)
+
+	router := setupRouter(cfg, userHandler, authHandler, oauthHandler, signalHandler, chatHandler, buddyHandler, websocketHandler, queueAdminHandler, websocketService, wsTicketService, jwtManager, appLogger)
 
 	server := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
@@ -91,9 +115,17 @@ func main() {
 
 	appLogger.Info("🛑 서버 종료 중...")
 
+	stopNotify()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// 하이재킹된 WebSocket 연결은 server.Shutdown이 정리하지 않으므로 먼저 종료
+	websocketService.Shutdown()
+	if err := chatWebSocketService.Shutdown(ctx); err != nil {
+		appLogger.Error("채팅 WebSocket 종료 실패", err)
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		appLogger.Error("서버 강제 종료", err)
 		os.Exit(1)
@@ -110,7 +142,10 @@ func setupRouter(
 	signalHandler *handlers.SignalHandler,
 	chatHandler *handlers.ChatHandler,
 	buddyHandler *handlers.BuddyHandler,
+	websocketHandler *handlers.WebSocketHandler,
+	queueAdminHandler *handlers.QueueAdminHandler,
 	websocketService *services.WebSocketService,
+	wsTicketService services.WebSocketTicketServiceInterface,
 	jwtManager *utils.JWTManager,
 	appLogger *logger.Logger,
 ) *gin.Engine {
@@ -123,7 +158,7 @@ func setupRouter(
 	router.Use(gin.Recovery())
 
 	corsConfig := cors.Config{
-		AllowOrigins:     []string{cfg.Server.FrontendURL, "http://localhost:3000"},
+		AllowOrigins:     cfg.Server.Origins(),
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
 		AllowCredentials: true,
@@ -148,10 +183,28 @@ func setupRouter(
 			auth.GET("/oauth/providers", oauthHandler.GetSupportedProviders)
 		}
 
+		// 첨부파일 업로드 (발급된 URL의 토큰으로 인증)
+		api.PUT("/chat/uploads/:token", chatHandler.UploadAttachment)
+
+		// 실시간 WebSocket/SSE (Authorization 헤더 또는 /ws/ticket으로 받은 ?ticket=으로 인증)
+		signalStreamAuth := authMiddleware.RequireWebSocketAuth(wsTicketService, func(c *gin.Context) string {
+			return models.WSTicketTarget(models.WSStreamSignals, "")
+		})
+		api.GET("/signals/ws", signalStreamAuth, websocketService.HandleSignalWebSocket)
+		api.GET("/signals/events", signalStreamAuth, websocketService.HandleSignalSSE)
+		api.GET("/chat/ws/:room_id",
+			authMiddleware.RequireWebSocketAuth(wsTicketService, func(c *gin.Context) string {
+				return models.WSTicketTarget(models.WSStreamChat, c.Param("room_id"))
+			}),
+			chatHandler.HandleWebSocket)
+
 		// 인증 필요
 		authenticated := api.Group("")
 		authenticated.Use(authMiddleware.RequireAuth())
 		{
+			// WebSocket 연결 티켓 발급
+			authenticated.POST("/ws/ticket", websocketHandler.IssueTicket)
+
 			// 사용자 관리
 			user := authenticated.Group("/user")
 			{
@@ -162,6 +215,9 @@ func setupRouter(
 				user.POST("/push-token", userHandler.RegisterPushToken)
 			}
 
+			// 사용자 접속 상태
+			authenticated.GET("/users/presence", userHandler.GetPresence)
+
 			// 시그널 관리
 			signals := authenticated.Group("/signals")
 			{
@@ -170,12 +226,12 @@ func setupRouter(
 				signals.GET("/nearby", signalHandler.GetNearbySignals)
 				signals.GET("/my", signalHandler.GetMySignals)
 				signals.GET("/:id", signalHandler.GetSignal)
+				signals.PUT("/:id", signalHandler.UpdateSignal)
 				signals.POST("/:id/join", signalHandler.JoinSignal)
 				signals.POST("/:id/leave", signalHandler.LeaveSignal)
+				signals.POST("/:id/cancel", signalHandler.CancelSignal)
 				signals.POST("/:id/approve/:user_id", signalHandler.ApproveParticipant)
 				signals.POST("/:id/reject/:user_id", signalHandler.RejectParticipant)
-				// 실시간 시그널 업데이트 WebSocket
-				signals.GET("/ws", websocketService.HandleSignalWebSocket)
 			}
 
 			// 채팅
@@ -183,8 +239,29 @@ func setupRouter(
 			{
 				chat.GET("/rooms", chatHandler.GetChatRooms)
 				chat.GET("/rooms/:id/messages", chatHandler.GetMessages)
+				chat.GET("/rooms/:id/messages/search", chatHandler.SearchMessages)
 				chat.POST("/rooms/:id/messages", chatHandler.SendMessage)
-				chat.GET("/ws/:room_id", chatHandler.HandleWebSocket)
+				chat.PUT("/rooms/:id/messages/:message_id", chatHandler.EditMessage)
+				chat.DELETE("/rooms/:id/messages/:message_id", chatHandler.DeleteMessage)
+				chat.POST("/rooms/:id/read", chatHandler.MarkRead)
+				chat.PUT("/rooms/:id/mute", chatHandler.MuteRoom)
+				chat.DELETE("/rooms/:id/mute", chatHandler.UnmuteRoom)
+				chat.POST("/rooms/:id/attachments", chatHandler.CreateAttachment)
+				chat.GET("/rooms/:id/live-locations", chatHandler.GetLiveLocations)
+				chat.GET("/rooms/:id/polls/:poll_id", chatHandler.GetPoll)
+				chat.PUT("/rooms/:id/polls/:poll_id/votes", chatHandler.VotePoll)
+				chat.POST("/rooms/:id/polls/:poll_id/close", chatHandler.ClosePoll)
+				chat.POST("/rooms/:id/polls/:poll_id/apply", chatHandler.ApplyPoll)
+				chat.PUT("/rooms/:id/slow-mode", chatHandler.SetSlowMode)
+				chat.GET("/rooms/:id/extend", chatHandler.GetExtendStatus)
+				chat.POST("/rooms/:id/extend", chatHandler.VoteExtend)
+				chat.DELETE("/rooms/:id/extend", chatHandler.WithdrawExtendVote)
+				chat.GET("/rooms/:id/export", chatHandler.ExportMessages)
+				chat.POST("/rooms/:id/participants/:user_id/mute", chatHandler.MuteParticipant)
+				chat.DELETE("/rooms/:id/participants/:user_id/mute", chatHandler.UnmuteParticipant)
+				chat.POST("/messages/:id/report", chatHandler.ReportMessage)
+				chat.GET("/attachments/:id", chatHandler.DownloadAttachment)
+				chat.GET("/attachments/:id/thumbnail", chatHandler.DownloadAttachmentThumbnail)
 			}
 
 			// 평가 및 신고
@@ -217,6 +294,19 @@ func setupRouter(
 				buddies.GET("/invitations", buddyHandler.GetBuddyInvitations)
 				buddies.POST("/invitations/:invitationId/respond", buddyHandler.RespondBuddyInvitation)
 			}
+
+			// 관리자: 작업 큐와 데드 레터 관리
+			admin := authenticated.Group("/admin")
+			admin.Use(authMiddleware.RequireAdmin(cfg.Server.AdminUserIDs))
+			{
+				admin.GET("/queues", queueAdminHandler.GetStats)
+				admin.GET("/queues/:type/dead", queueAdminHandler.ListDeadLetters)
+				admin.POST("/queues/:type/dead/replay", queueAdminHandler.ReplayDeadLetters)
+				admin.DELETE("/queues/:type/dead", queueAdminHandler.PurgeDeadLetters)
+				admin.GET("/queues/:type/dead/:job_id", queueAdminHandler.GetDeadLetter)
+				admin.POST("/queues/:type/dead/:job_id/replay", queueAdminHandler.ReplayDeadLetter)
+				admin.DELETE("/queues/:type/dead/:job_id", queueAdminHandler.PurgeDeadLetter)
+			}
 		}
 	}
 
@@ -11,14 +11,16 @@ import (
 )
 
 type BuddyHandler struct {
-	buddyService services.BuddyServiceInterface
-	logger       *logger.Logger
+	buddyService    services.BuddyServiceInterface
+	presenceService services.PresenceServiceInterface
+	logger          *logger.Logger
 }
 
-func NewBuddyHandler(buddyService services.BuddyServiceInterface, logger *logger.Logger) *BuddyHandler {
+func NewBuddyHandler(buddyService services.BuddyServiceInterface, presenceService services.PresenceServiceInterface, logger *logger.Logger) *BuddyHandler {
 	return &BuddyHandler{
-		buddyService: buddyService,
-		logger:       logger,
+		buddyService:    buddyService,
+		presenceService: presenceService,
+		logger:          logger,
 	}
 }
 
@@ -39,6 +41,19 @@ func (h *BuddyHandler) GetBuddies(c *gin.Context) {
 		return
 	}
 
+	// 상대방 접속 상태
+	buddyIDs := make([]uint, len(buddies))
+	for i, buddy := range buddies {
+		buddyIDs[i] = buddy.BuddyID
+		if buddy.BuddyID == userID {
+			buddyIDs[i] = buddy.UserID
+		}
+	}
+	presence := h.presenceService.GetPresence(userID, buddyIDs)
+	for i := range buddies {
+		buddies[i].Presence = presence[buddyIDs[i]]
+	}
+
 	pagination := utils.CalculatePagination(query.Page, query.Limit, total)
 	utils.PagedSuccessResponse(c, "단골 목록 조회 성공", buddies, pagination)
 }
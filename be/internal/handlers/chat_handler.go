@@ -1,38 +1,674 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"signal-be/internal/services"
 	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
 	"signal-module/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ChatHandler struct {
-	chatService      interface{}
-	websocketService interface{}
-	logger           *logger.Logger
+	chatService       services.ChatServiceInterface
+	attachmentService services.ChatAttachmentServiceInterface
+	websocketService  *services.ChatWebSocketService
+	logger            *logger.Logger
 }
 
-func NewChatHandler(chatService, websocketService interface{}, logger *logger.Logger) *ChatHandler {
+func NewChatHandler(chatService services.ChatServiceInterface, attachmentService services.ChatAttachmentServiceInterface, websocketService *services.ChatWebSocketService, logger *logger.Logger) *ChatHandler {
 	return &ChatHandler{
-		chatService:      chatService,
-		websocketService: websocketService,
-		logger:           logger,
+		chatService:       chatService,
+		attachmentService: attachmentService,
+		websocketService:  websocketService,
+		logger:            logger,
 	}
 }
 
 func (h *ChatHandler) GetChatRooms(c *gin.Context) {
-	utils.SuccessResponse(c, "채팅방 목록 조회 완료", nil)
+	userID := c.GetUint("user_id")
+
+	rooms, err := h.chatService.GetChatRooms(userID)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "채팅방 목록 조회에 실패했습니다", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "채팅방 목록 조회 완료", rooms)
 }
 
 func (h *ChatHandler) GetMessages(c *gin.Context) {
-	utils.SuccessResponse(c, "메시지 조회 완료", nil)
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	// around/before/after가 있으면 커서 기반 조회 (메시지로 이동)
+	var window models.MessageWindowQuery
+	if err := c.ShouldBindQuery(&window); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+	if window.Around != 0 || window.Before != 0 || window.After != 0 {
+		result, err := h.chatService.GetMessageWindow(userID, uint(chatRoomID), &window)
+		if err != nil {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+		utils.SuccessResponse(c, "메시지 조회 완료", result)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	messages, pagination, err := h.chatService.GetMessages(userID, uint(chatRoomID), page, limit)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.PagedSuccessResponse(c, "메시지 조회 완료", messages, *pagination)
+}
+
+// SearchMessages 채팅방 메시지 검색 (q, before: 다음 페이지 커서, limit, context: 앞뒤 메시지 수)
+func (h *ChatHandler) SearchMessages(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	before, _ := strconv.ParseInt(c.Query("before"), 10, 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	contextSize, _ := strconv.Atoi(c.DefaultQuery("context", "2"))
+
+	result, err := h.chatService.SearchMessages(userID, uint(chatRoomID), c.Query("q"), before, limit, contextSize)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "메시지 검색 완료", result)
 }
 
 func (h *ChatHandler) SendMessage(c *gin.Context) {
-	utils.SuccessResponse(c, "메시지 전송 완료", nil)
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	var req models.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	message, err := h.chatService.SendMessage(userID, uint(chatRoomID), &req)
+	if err != nil {
+		chatErrorResponse(c, err)
+		return
+	}
+
+	utils.CreatedResponse(c, "메시지 전송 완료", message)
+}
+
+func (h *ChatHandler) EditMessage(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, messageID, ok := parseChatMessagePath(c)
+	if !ok {
+		return
+	}
+
+	var req models.EditMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	message, err := h.chatService.EditMessage(userID, chatRoomID, messageID, &req)
+	if err != nil {
+		chatErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "메시지 수정 완료", message)
+}
+
+func (h *ChatHandler) DeleteMessage(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, messageID, ok := parseChatMessagePath(c)
+	if !ok {
+		return
+	}
+
+	message, err := h.chatService.DeleteMessage(userID, chatRoomID, messageID)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "메시지 삭제 완료", message)
+}
+
+func (h *ChatHandler) MarkRead(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	var req models.MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	receipt, err := h.chatService.MarkRead(userID, uint(chatRoomID), req.MessageID)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "읽음 처리 완료", receipt)
+}
+
+func (h *ChatHandler) MuteRoom(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	var req models.MuteChatRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	if err := h.chatService.MuteRoom(userID, uint(chatRoomID), &req); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "채팅방 알림을 껐습니다", nil)
+}
+
+func (h *ChatHandler) UnmuteRoom(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	if err := h.chatService.UnmuteRoom(userID, uint(chatRoomID)); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "채팅방 알림을 켰습니다", nil)
+}
+
+// GetLiveLocations 위치를 공유 중인 참여자와 만남 장소까지의 거리
+func (h *ChatHandler) GetLiveLocations(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	locations, err := h.chatService.GetLiveLocations(userID, uint(chatRoomID))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "실시간 위치 조회 완료", locations)
+}
+
+func (h *ChatHandler) GetPoll(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, pollID, ok := parseChatPollPath(c)
+	if !ok {
+		return
+	}
+
+	poll, err := h.chatService.GetPoll(userID, chatRoomID, pollID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "투표 조회 완료", poll)
+}
+
+func (h *ChatHandler) VotePoll(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, pollID, ok := parseChatPollPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	poll, err := h.chatService.VotePoll(userID, chatRoomID, pollID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "투표 완료", poll)
+}
+
+func (h *ChatHandler) ClosePoll(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, pollID, ok := parseChatPollPath(c)
+	if !ok {
+		return
+	}
+
+	poll, err := h.chatService.ClosePoll(userID, chatRoomID, pollID)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "투표가 마감되었습니다", poll)
+}
+
+// ApplyPoll 호스트가 시간/장소 투표 결과를 시그널에 반영
+func (h *ChatHandler) ApplyPoll(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, pollID, ok := parseChatPollPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.ApplyPollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	poll, err := h.chatService.ApplyPoll(userID, chatRoomID, pollID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "투표 결과를 시그널에 반영했습니다", poll)
+}
+
+// CreateAttachment 첨부파일 메타데이터를 등록하고 일회용 업로드 URL 발급
+// ReportMessage 메시지 작성자 신고 (메시지가 신고 근거로 첨부됨)
+func (h *ChatHandler) ReportMessage(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 메시지 ID입니다")
+		return
+	}
+
+	var req models.ReportMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	if err := h.chatService.ReportMessage(userID, uint(messageID), &req); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "메시지 신고가 접수되었습니다", nil)
+}
+
+// MuteParticipant 호스트가 참여자의 채팅을 일정 시간 제한
+func (h *ChatHandler) MuteParticipant(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, targetID, ok := parseChatParticipantPath(c)
+	if !ok {
+		return
+	}
+
+	var req models.MuteParticipantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	mute, err := h.chatService.MuteParticipant(userID, chatRoomID, targetID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "참여자의 채팅을 제한했습니다", mute)
+}
+
+func (h *ChatHandler) UnmuteParticipant(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, targetID, ok := parseChatParticipantPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.chatService.UnmuteParticipant(userID, chatRoomID, targetID); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "채팅 제한을 해제했습니다", nil)
+}
+
+// SetSlowMode 호스트가 슬로우 모드 간격 설정 (0이면 해제)
+func (h *ChatHandler) SetSlowMode(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	var req models.SlowModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	if err := h.chatService.SetSlowMode(userID, uint(chatRoomID), &req); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "슬로우 모드를 설정했습니다", req)
+}
+
+// GetExtendStatus 채팅방 연장 투표 현황
+func (h *ChatHandler) GetExtendStatus(c *gin.Context) {
+	h.handleExtend(c, h.chatService.GetExtendStatus, "연장 투표 현황 조회 완료")
+}
+
+// VoteExtend 채팅방 24시간 연장 찬성 (만료 6시간 전부터)
+func (h *ChatHandler) VoteExtend(c *gin.Context) {
+	h.handleExtend(c, h.chatService.VoteExtend, "연장에 찬성했습니다")
+}
+
+func (h *ChatHandler) WithdrawExtendVote(c *gin.Context) {
+	h.handleExtend(c, h.chatService.WithdrawExtendVote, "연장 찬성을 취소했습니다")
+}
+
+func (h *ChatHandler) handleExtend(c *gin.Context, action func(userID, chatRoomID uint) (*models.ChatExtendStatus, error), message string) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	status, err := action(userID, uint(chatRoomID))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, message, status)
+}
+
+// ExportMessages 내가 보낸 메시지 내려받기 (format=json 또는 txt)
+func (h *ChatHandler) ExportMessages(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "txt" {
+		utils.BadRequestResponse(c, "format은 json 또는 txt만 가능합니다")
+		return
+	}
+
+	transcript, err := h.chatService.ExportMessages(userID, uint(chatRoomID))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("chat-%d-my-messages.%s", chatRoomID, format)
+	c.Header("Content-Disposition", contentDisposition("attachment", fileName))
+
+	if format == "txt" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(transcriptText(transcript)))
+		return
+	}
+
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "내보내기에 실패했습니다", err)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+func (h *ChatHandler) CreateAttachment(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return
+	}
+
+	var req models.CreateAttachmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	upload, err := h.attachmentService.CreateUpload(userID, uint(chatRoomID), &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "업로드 URL 발급 완료", upload)
+}
+
+// UploadAttachment 업로드 URL로 파일 본문 수신 (URL의 토큰이 인증을 대신함)
+func (h *ChatHandler) UploadAttachment(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, models.MaxFileAttachmentSize+1)
+
+	info, err := h.attachmentService.Upload(c.Request.Context(), c.Param("token"), c.Request.Body)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "업로드 완료", info)
+}
+
+func (h *ChatHandler) DownloadAttachment(c *gin.Context) {
+	h.serveAttachment(c, false)
+}
+
+func (h *ChatHandler) DownloadAttachmentThumbnail(c *gin.Context) {
+	h.serveAttachment(c, true)
+}
+
+func (h *ChatHandler) serveAttachment(c *gin.Context, thumbnail bool) {
+	userID := c.GetUint("user_id")
+
+	attachmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 첨부파일 ID입니다")
+		return
+	}
+
+	download, err := h.attachmentService.Open(c.Request.Context(), userID, uint(attachmentID), thumbnail)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	if download.RedirectURL != "" {
+		c.Redirect(http.StatusFound, download.RedirectURL)
+		return
+	}
+	defer download.Body.Close()
+
+	disposition := "attachment"
+	if download.Inline || thumbnail {
+		disposition = "inline"
+	}
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.DataFromReader(http.StatusOK, -1, download.ContentType, download.Body, map[string]string{
+		"Content-Disposition": contentDisposition(disposition, download.FileName),
+	})
 }
 
 func (h *ChatHandler) HandleWebSocket(c *gin.Context) {
-	utils.SuccessResponse(c, "WebSocket 연결", nil)
-}
\ No newline at end of file
+	h.websocketService.HandleChatWebSocket(c)
+}
+
+// parseChatMessagePath /rooms/:id/messages/:message_id 경로 파라미터 파싱 (실패 시 응답 후 false)
+func parseChatMessagePath(c *gin.Context) (uint, uint, bool) {
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return 0, 0, false
+	}
+
+	messageID, err := strconv.ParseUint(c.Param("message_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 메시지 ID입니다")
+		return 0, 0, false
+	}
+
+	return uint(chatRoomID), uint(messageID), true
+}
+
+// parseChatPollPath /rooms/:id/polls/:poll_id 경로 파라미터 파싱 (실패 시 응답 후 false)
+func parseChatPollPath(c *gin.Context) (uint, uint, bool) {
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return 0, 0, false
+	}
+
+	pollID, err := strconv.ParseUint(c.Param("poll_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 투표 ID입니다")
+		return 0, 0, false
+	}
+
+	return uint(chatRoomID), uint(pollID), true
+}
+
+// parseChatParticipantPath /rooms/:id/participants/:user_id 경로 파라미터 파싱 (실패 시 응답 후 false)
+func parseChatParticipantPath(c *gin.Context) (uint, uint, bool) {
+	chatRoomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 채팅방 ID입니다")
+		return 0, 0, false
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 사용자 ID입니다")
+		return 0, 0, false
+	}
+
+	return uint(chatRoomID), uint(targetID), true
+}
+
+// chatErrorResponse 전송 제한은 429, 채팅 제한은 403, 그 외는 400
+func chatErrorResponse(c *gin.Context, err error) {
+	var moderationErr *services.ChatModerationError
+	if errors.As(err, &moderationErr) {
+		switch moderationErr.Code {
+		case "rate_limited", "slow_mode":
+			utils.ErrorResponse(c, http.StatusTooManyRequests, err.Error(), nil)
+			return
+		case "muted":
+			utils.ForbiddenResponse(c, err.Error())
+			return
+		}
+	}
+	utils.BadRequestResponse(c, err.Error())
+}
+
+// transcriptText 한 줄에 메시지 하나씩 ("[2006-01-02 15:04] 내용")
+func transcriptText(transcript *models.ChatTranscript) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n내보낸 시간: %s\n\n", transcript.Name, transcript.GeneratedAt.Format("2006-01-02 15:04"))
+
+	for _, message := range transcript.Messages {
+		content := message.Content
+		switch {
+		case message.FileName != "":
+			content = strings.TrimSpace(fmt.Sprintf("[파일: %s] %s", message.FileName, content))
+		case message.Location != nil:
+			content = fmt.Sprintf("[장소: %s %s] (%.6f, %.6f)", message.Location.PlaceName, message.Location.Address,
+				message.Location.Latitude, message.Location.Longitude)
+		case message.Type == models.MessagePoll:
+			content = "[투표] " + content
+		}
+		if message.EditedAt != nil {
+			content += " (수정됨)"
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", message.CreatedAt.Format("2006-01-02 15:04"), content)
+	}
+
+	return b.String()
+}
+
+func contentDisposition(disposition, fileName string) string {
+	if value := mime.FormatMediaType(disposition, map[string]string{"filename": fileName}); value != "" {
+		return value
+	}
+	return fmt.Sprintf("%s; filename=\"file\"", disposition)
+}
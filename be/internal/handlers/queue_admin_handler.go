@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"signal-be/internal/services"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueueAdminHandler struct {
+	queueAdminService services.QueueAdminServiceInterface
+	logger            *logger.Logger
+}
+
+func NewQueueAdminHandler(queueAdminService services.QueueAdminServiceInterface, logger *logger.Logger) *QueueAdminHandler {
+	return &QueueAdminHandler{
+		queueAdminService: queueAdminService,
+		logger:            logger,
+	}
+}
+
+// GetStats 작업 타입별 대기/처리 중/예약/데드 레터 개수
+func (h *QueueAdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.queueAdminService.GetStats()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "큐 상태 조회에 실패했습니다", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "큐 상태 조회 완료", stats)
+}
+
+// ListDeadLetters 데드 레터 작업 목록 (최근 실패 순)
+func (h *QueueAdminHandler) ListDeadLetters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	jobs, pagination, err := h.queueAdminService.ListDeadLetters(c.Param("type"), page, limit)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.PagedSuccessResponse(c, "데드 레터 조회 완료", jobs, *pagination)
+}
+
+// GetDeadLetter 데드 레터 작업의 페이로드와 실패 기록
+func (h *QueueAdminHandler) GetDeadLetter(c *gin.Context) {
+	job, err := h.queueAdminService.GetDeadLetter(c.Param("type"), c.Param("job_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "데드 레터 조회 완료", job)
+}
+
+// ReplayDeadLetter 데드 레터 작업을 시도 횟수를 초기화해 다시 실행
+func (h *QueueAdminHandler) ReplayDeadLetter(c *gin.Context) {
+	if err := h.queueAdminService.ReplayDeadLetter(c.Param("type"), c.Param("job_id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "작업을 다시 큐에 넣었습니다", nil)
+}
+
+// ReplayDeadLetters 데드 레터 큐의 작업을 모두 다시 실행
+func (h *QueueAdminHandler) ReplayDeadLetters(c *gin.Context) {
+	replayed, err := h.queueAdminService.ReplayDeadLetters(c.Param("type"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "작업을 다시 큐에 넣었습니다", gin.H{"replayed": replayed})
+}
+
+// PurgeDeadLetter 데드 레터 작업 삭제
+func (h *QueueAdminHandler) PurgeDeadLetter(c *gin.Context) {
+	if err := h.queueAdminService.PurgeDeadLetter(c.Param("type"), c.Param("job_id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "작업을 삭제했습니다", nil)
+}
+
+// PurgeDeadLetters 데드 레터 큐 비우기
+func (h *QueueAdminHandler) PurgeDeadLetters(c *gin.Context) {
+	purged, err := h.queueAdminService.PurgeDeadLetters(c.Param("type"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, "데드 레터 큐를 비웠습니다", gin.H{"purged": purged})
+}
+
+func (h *QueueAdminHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrQueueJobNotFound) {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+	utils.BadRequestResponse(c, err.Error())
+}
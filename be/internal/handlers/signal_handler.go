@@ -14,14 +14,16 @@ import (
 )
 
 type SignalHandler struct {
-	signalService services.SignalServiceInterface
-	logger        *logger.Logger
+	signalService   services.SignalServiceInterface
+	presenceService services.PresenceServiceInterface
+	logger          *logger.Logger
 }
 
-func NewSignalHandler(signalService services.SignalServiceInterface, logger *logger.Logger) *SignalHandler {
+func NewSignalHandler(signalService services.SignalServiceInterface, presenceService services.PresenceServiceInterface, logger *logger.Logger) *SignalHandler {
 	return &SignalHandler{
-		signalService: signalService,
-		logger:        logger,
+		signalService:   signalService,
+		presenceService: presenceService,
+		logger:          logger,
 	}
 }
 
@@ -56,9 +58,40 @@ func (h *SignalHandler) GetSignal(c *gin.Context) {
 		return
 	}
 
+	// 생성자와 참여자의 접속 상태
+	users := []*models.User{&signal.Creator}
+	for i := range signal.Participants {
+		users = append(users, &signal.Participants[i].User)
+	}
+	h.presenceService.AttachPresence(c.GetUint("user_id"), users...)
+
 	utils.SuccessResponse(c, "시그널 조회 완료", signal)
 }
 
+func (h *SignalHandler) UpdateSignal(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	signalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 시그널 ID입니다")
+		return
+	}
+
+	var req models.UpdateSignalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	signal, err := h.signalService.UpdateSignal(uint(signalID), userID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "시그널이 수정되었습니다", signal)
+}
+
 func (h *SignalHandler) SearchSignals(c *gin.Context) {
 	var req models.SearchSignalRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
@@ -130,6 +163,23 @@ func (h *SignalHandler) LeaveSignal(c *gin.Context) {
 	utils.SuccessResponse(c, "시그널에서 나갔습니다", nil)
 }
 
+func (h *SignalHandler) CancelSignal(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	signalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "유효하지 않은 시그널 ID입니다")
+		return
+	}
+
+	if err := h.signalService.CancelSignal(uint(signalID), userID); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, "시그널이 취소되었습니다", nil)
+}
+
 func (h *SignalHandler) ApproveParticipant(c *gin.Context) {
 	creatorID := c.GetUint("user_id")
 	
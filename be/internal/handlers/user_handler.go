@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"strconv"
+	"strings"
+
 	"signal-be/internal/services"
 	"signal-module/pkg/logger"
 	"signal-module/pkg/models"
@@ -10,14 +13,19 @@ import (
 )
 
 type UserHandler struct {
-	userService services.UserServiceInterface
-	logger      *logger.Logger
+	userService     services.UserServiceInterface
+	presenceService services.PresenceServiceInterface
+	logger          *logger.Logger
 }
 
-func NewUserHandler(userService services.UserServiceInterface, logger *logger.Logger) *UserHandler {
+// 접속 상태를 한 번에 조회할 수 있는 최대 사용자 수
+const maxPresenceQueryUsers = 100
+
+func NewUserHandler(userService services.UserServiceInterface, presenceService services.PresenceServiceInterface, logger *logger.Logger) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		logger:      logger,
+		userService:     userService,
+		presenceService: presenceService,
+		logger:          logger,
 	}
 }
 
@@ -140,4 +148,28 @@ func (h *UserHandler) ReportUser(c *gin.Context) {
 	}
 
 	utils.SuccessResponse(c, "사용자 신고가 접수되었습니다", nil)
-}
\ No newline at end of file
+}
+
+// GetPresence 사용자들의 접속 상태와 마지막 접속 시각 (?ids=1,2,3, 비공개 사용자는 결과에 없음)
+func (h *UserHandler) GetPresence(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var userIDs []uint
+	for _, idStr := range strings.Split(c.Query("ids"), ",") {
+		if idStr = strings.TrimSpace(idStr); idStr == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			utils.BadRequestResponse(c, "유효하지 않은 사용자 ID입니다")
+			return
+		}
+		userIDs = append(userIDs, uint(id))
+	}
+	if len(userIDs) == 0 || len(userIDs) > maxPresenceQueryUsers {
+		utils.BadRequestResponse(c, "사용자 ID는 1-100개까지 조회할 수 있습니다")
+		return
+	}
+
+	utils.SuccessResponse(c, "접속 상태 조회 완료", h.presenceService.GetPresence(userID, userIDs))
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"signal-be/internal/services"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebSocketHandler struct {
+	ticketService services.WebSocketTicketServiceInterface
+	logger        *logger.Logger
+}
+
+func NewWebSocketHandler(ticketService services.WebSocketTicketServiceInterface, logger *logger.Logger) *WebSocketHandler {
+	return &WebSocketHandler{
+		ticketService: ticketService,
+		logger:        logger,
+	}
+}
+
+// IssueTicket WebSocket 연결용 일회용 티켓 발급 (30초 안에 해당 스트림 연결에만 사용 가능)
+func (h *WebSocketHandler) IssueTicket(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req models.WSTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "잘못된 요청 데이터입니다")
+		return
+	}
+
+	ticket, err := h.ticketService.Issue(userID, c.GetString("user_email"), c.GetString("username"), &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.CreatedResponse(c, "WebSocket 티켓 발급 완료", ticket)
+}
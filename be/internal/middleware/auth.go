@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
 	"signal-module/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,12 @@ type AuthMiddleware struct {
 	logger     *logger.Logger
 }
 
+// WebSocketTicketValidator WebSocket 연결 티켓과 계정 상태 확인
+type WebSocketTicketValidator interface {
+	Redeem(ticket, target string) (*models.WSTicketClaims, error)
+	CheckUser(userID uint) error
+}
+
 func NewAuthMiddleware(jwtManager *utils.JWTManager, logger *logger.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtManager: jwtManager,
@@ -24,41 +31,107 @@ func NewAuthMiddleware(jwtManager *utils.JWTManager, logger *logger.Logger) *Aut
 
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Authorization 헤더가 필요합니다",
-			})
-			c.Abort()
+		if !m.authenticateBearer(c) {
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{
+		c.Next()
+	}
+}
+
+// RequireAdmin 관리자 사용자만 허용 (RequireAuth 뒤에 사용)
+func (m *AuthMiddleware) RequireAdmin(adminUserIDs []uint) gin.HandlerFunc {
+	admins := make(map[uint]bool, len(adminUserIDs))
+	for _, userID := range adminUserIDs {
+		admins[userID] = true
+	}
+
+	return func(c *gin.Context) {
+		if !admins[c.GetUint("user_id")] {
+			c.JSON(http.StatusForbidden, gin.H{
 				"success": false,
-				"message": "Bearer 토큰이 필요합니다",
+				"message": "관리자만 사용할 수 있습니다",
 			})
 			c.Abort()
 			return
 		}
 
-		claims, err := m.jwtManager.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
+		c.Next()
+	}
+}
+
+// RequireWebSocketAuth WebSocket 업그레이드와 SSE 연결 요청 인증
+//
+// ?ticket=으로 전달된 일회용 티켓이 있으면 target(c)와 같은 대상에 발급된 것인지 확인하고,
+// 없으면 Authorization 헤더를 확인한다. 연결 시점에 계정이 정지/차단되었으면 거부한다.
+func (m *AuthMiddleware) RequireWebSocketAuth(tickets WebSocketTicketValidator, target func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ticket := c.Query("ticket"); ticket != "" {
+			claims, err := tickets.Redeem(ticket, target(c))
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"message": err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			c.Set("user_id", claims.UserID)
+			c.Set("user_email", claims.Email)
+			c.Set("username", claims.Username)
+		} else if !m.authenticateBearer(c) {
+			return
+		}
+
+		if err := tickets.CheckUser(c.GetUint("user_id")); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
 				"success": false,
-				"message": "유효하지 않은 토큰입니다",
+				"message": err.Error(),
 			})
 			c.Abort()
 			return
 		}
 
-		// 컨텍스트에 사용자 정보 저장
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
-		c.Set("username", claims.Username)
-
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// authenticateBearer Authorization 헤더의 JWT 확인 (실패 시 응답 후 false)
+func (m *AuthMiddleware) authenticateBearer(c *gin.Context) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Authorization 헤더가 필요합니다",
+		})
+		c.Abort()
+		return false
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Bearer 토큰이 필요합니다",
+		})
+		c.Abort()
+		return false
+	}
+
+	claims, err := m.jwtManager.ValidateToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "유효하지 않은 토큰입니다",
+		})
+		c.Abort()
+		return false
+	}
+
+	// 컨텍스트에 사용자 정보 저장
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("username", claims.Username)
+	return true
+}
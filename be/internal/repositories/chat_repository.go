@@ -1,24 +1,72 @@
 package repositories
 
 import (
+	"strings"
 	"time"
 
 	"signal-module/pkg/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ChatRepositoryInterface interface {
 	CreateChatRoom(room *models.ChatRoom) error
+	GetChatRoomByID(chatRoomID uint) (*models.ChatRoom, error)
 	GetChatRoomBySignalID(signalID uint) (*models.ChatRoom, error)
 	GetChatRoomsByUserID(userID uint) ([]models.ChatRoomInfo, error)
+	IsParticipant(chatRoomID, userID uint) (bool, error)
 	SendMessage(message *models.ChatMessage) error
-	GetMessages(chatRoomID uint, page, limit int) ([]models.MessageWithUser, int64, error)
+	GetMessageByID(messageID uint) (*models.ChatMessage, error)
+	GetMessageByIDUnscoped(messageID uint) (*models.ChatMessage, error)
+	UpdateMessage(message *models.ChatMessage) error
+	DeleteMessage(messageID uint) error
+	GetMessageByClientID(chatRoomID, userID uint, clientMsgID string) (*models.ChatMessage, error)
+	GetMessages(chatRoomID uint, page, limit int) ([]models.ChatMessage, int64, error)
+	GetMessagesFromSeq(chatRoomID uint, seq int64, older bool, limit int) ([]models.ChatMessage, error)
+	GetMessagesBySeqs(chatRoomID uint, seqs []int64) ([]models.ChatMessage, error)
+	SearchMessages(chatRoomID uint, query string, beforeSeq int64, limit int) ([]MessageSearchHit, error)
+	MarkRead(chatRoomID, userID, messageID uint, seq int64) (int64, bool, error)
+	GetReadStates(chatRoomID uint) ([]models.ChatReadState, error)
+	GetUnreadCount(chatRoomID, userID uint) (int64, error)
+	SetMute(chatRoomID, userID uint, mutedUntil *time.Time) error
+	DeleteMute(chatRoomID, userID uint) error
+	IsMuted(chatRoomID, userID uint) (bool, error)
+	GetPushRecipients(chatRoomID, senderID uint) ([]uint, error)
+	CreateAttachment(attachment *models.ChatAttachment) error
+	UpdateAttachment(attachment *models.ChatAttachment) error
+	GetAttachmentByID(attachmentID uint) (*models.ChatAttachment, error)
+	GetAttachmentByUploadToken(token string) (*models.ChatAttachment, error)
+	GetMessageByAttachmentID(attachmentID uint) (*models.ChatMessage, error)
+	SetMeetingPoint(chatRoomID uint, messageID *uint) error
+	GetPollByID(pollID uint) (*models.ChatPoll, error)
+	ReplacePollVotes(pollID, userID uint, optionIDs []uint) error
+	UpdatePoll(poll *models.ChatPoll) error
+	SetSlowMode(chatRoomID uint, seconds int) error
+	SetParticipantMute(mute *models.ChatParticipantMute) error
+	DeleteParticipantMute(chatRoomID, userID uint) error
+	GetActiveParticipantMute(chatRoomID, userID uint) (*models.ChatParticipantMute, error)
+	CreateMessageReport(report *models.ReportUser) error
+	HasReportedMessage(reporterID, messageID uint) (bool, error)
+	CountParticipants(chatRoomID uint) (int64, error)
+	AddExtendVote(vote *models.ChatExtendVote) error
+	DeleteExtendVote(chatRoomID uint, round int, userID uint) error
+	CountExtendVotes(chatRoomID uint, round int) (int64, error)
+	HasExtendVote(chatRoomID uint, round int, userID uint) (bool, error)
+	ExtendChatRoom(chatRoomID uint, round int, expiresAt time.Time) (bool, error)
+	GetUserMessages(chatRoomID, userID uint) ([]models.ChatMessage, error)
 	UpdateChatRoomStatus(chatRoomID uint, status models.ChatRoomStatus) error
 	GetExpiredChatRooms() ([]models.ChatRoom, error)
 	DeleteChatRoom(chatRoomID uint) error
 }
 
+// MessageSearchHit 검색에 일치한 메시지와 유사도
+type MessageSearchHit struct {
+	ID    uint
+	Seq   int64
+	Score float64
+}
+
 type ChatRepository struct {
 	db *gorm.DB
 }
@@ -31,6 +79,14 @@ func (r *ChatRepository) CreateChatRoom(room *models.ChatRoom) error {
 	return r.db.Create(room).Error
 }
 
+func (r *ChatRepository) GetChatRoomByID(chatRoomID uint) (*models.ChatRoom, error) {
+	var room models.ChatRoom
+	if err := r.db.First(&room, chatRoomID).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
 func (r *ChatRepository) GetChatRoomBySignalID(signalID uint) (*models.ChatRoom, error) {
 	var room models.ChatRoom
 	err := r.db.Preload("Signal").
@@ -75,37 +131,116 @@ func (r *ChatRepository) GetChatRoomsByUserID(userID uint) ([]models.ChatRoomInf
 		var lastMessage models.ChatMessage
 		err := r.db.Preload("User.Profile").
 			Where("chat_room_id = ?", roomInfos[i].ID).
-			Order("created_at DESC").
+			Order("seq DESC, created_at DESC").
 			First(&lastMessage).Error
 		
 		if err == nil {
-			roomInfos[i].LastMessage = &lastMessage
+			roomInfos[i].LastMessage = lastMessage.ToEnvelope()
+		}
+
+		if unread, err := r.GetUnreadCount(roomInfos[i].ID, userID); err == nil {
+			roomInfos[i].UnreadCount = unread
+		}
+
+		if muted, err := r.IsMuted(roomInfos[i].ID, userID); err == nil {
+			roomInfos[i].IsMuted = muted
 		}
 	}
 
 	return roomInfos, nil
 }
 
+// IsParticipant 시그널 생성자이거나 승인된 참여자인지 확인
+func (r *ChatRepository) IsParticipant(chatRoomID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("chat_rooms cr").
+		Joins("JOIN signals s ON s.id = cr.signal_id").
+		Where("cr.id = ? AND cr.deleted_at IS NULL", chatRoomID).
+		Where(`s.creator_id = ? OR EXISTS (
+			SELECT 1 FROM signal_participants sp
+			WHERE sp.signal_id = s.id AND sp.user_id = ? AND sp.status = ?
+		)`, userID, userID, models.ParticipantApproved).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *ChatRepository) SendMessage(message *models.ChatMessage) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// 메시지 저장
-		if err := tx.Create(message).Error; err != nil {
+		// 채팅방 순번 증가 (행 잠금으로 동시 전송 시에도 순번이 겹치지 않음)
+		if err := tx.Model(&models.ChatRoom{}).
+			Where("id = ?", message.ChatRoomID).
+			Updates(map[string]interface{}{
+				"last_seq":   gorm.Expr("last_seq + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
 			return err
 		}
 
-		// 채팅방 업데이트 시간 갱신
-		return tx.Model(&models.ChatRoom{}).
-			Where("id = ?", message.ChatRoomID).
-			Update("updated_at", time.Now()).Error
+		var room models.ChatRoom
+		if err := tx.Select("last_seq").First(&room, message.ChatRoomID).Error; err != nil {
+			return err
+		}
+		message.Seq = room.LastSeq
+
+		// 메시지 저장
+		return tx.Create(message).Error
 	})
 }
 
-func (r *ChatRepository) GetMessages(chatRoomID uint, page, limit int) ([]models.MessageWithUser, int64, error) {
-	var messages []models.MessageWithUser
+// withMessageRelations 발신자 프로필과 답장 대상 메시지(삭제된 메시지 포함)를 함께 조회
+func (r *ChatRepository) withMessageRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("User.Profile").
+		Preload("Attachment").
+		Preload("Poll.Options", orderPollOptions).
+		Preload("Poll.Votes").
+		Preload("ReplyTo", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Preload("ReplyTo.User.Profile")
+}
+
+func (r *ChatRepository) GetMessageByID(messageID uint) (*models.ChatMessage, error) {
+	var message models.ChatMessage
+	if err := r.withMessageRelations(r.db).First(&message, messageID).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetMessageByIDUnscoped 삭제된 메시지도 조회 (삭제 표시 전파용)
+func (r *ChatRepository) GetMessageByIDUnscoped(messageID uint) (*models.ChatMessage, error) {
+	var message models.ChatMessage
+	if err := r.withMessageRelations(r.db.Unscoped()).First(&message, messageID).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *ChatRepository) UpdateMessage(message *models.ChatMessage) error {
+	return r.db.Model(message).
+		Select("content", "is_edited", "edited_at").
+		Updates(message).Error
+}
+
+func (r *ChatRepository) DeleteMessage(messageID uint) error {
+	return r.db.Delete(&models.ChatMessage{}, messageID).Error
+}
+
+func (r *ChatRepository) GetMessageByClientID(chatRoomID, userID uint, clientMsgID string) (*models.ChatMessage, error) {
+	var message models.ChatMessage
+	err := r.withMessageRelations(r.db).
+		Where("chat_room_id = ? AND user_id = ? AND client_msg_id = ?", chatRoomID, userID, clientMsgID).
+		First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *ChatRepository) GetMessages(chatRoomID uint, page, limit int) ([]models.ChatMessage, int64, error) {
+	var messages []models.ChatMessage
 	var total int64
 
-	// 총 메시지 수 계산
-	if err := r.db.Model(&models.ChatMessage{}).
+	// 총 메시지 수 계산 (삭제된 메시지는 삭제 표시로 히스토리에 남음)
+	if err := r.db.Unscoped().Model(&models.ChatMessage{}).
 		Where("chat_room_id = ?", chatRoomID).
 		Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -113,42 +248,367 @@ func (r *ChatRepository) GetMessages(chatRoomID uint, page, limit int) ([]models
 
 	// 메시지 조회 (최신순)
 	offset := (page - 1) * limit
-	
-	query := `
-		SELECT 
-			cm.id,
-			cm.chat_room_id,
-			cm.type,
-			cm.content,
-			cm.image_url,
-			cm.is_edited,
-			cm.edited_at,
-			cm.created_at,
-			CASE 
-				WHEN cm.user_id IS NOT NULL THEN JSON_BUILD_OBJECT(
-					'id', u.id,
-					'username', u.username,
-					'display_name', up.display_name,
-					'avatar', up.avatar
-				)
-				ELSE NULL
-			END as user
-		FROM chat_messages cm
-		LEFT JOIN users u ON cm.user_id = u.id
-		LEFT JOIN user_profiles up ON u.id = up.user_id
-		WHERE cm.chat_room_id = ?
-		AND cm.deleted_at IS NULL
-		ORDER BY cm.created_at DESC
-		LIMIT ? OFFSET ?
-	`
 
-	if err := r.db.Raw(query, chatRoomID, limit, offset).Find(&messages).Error; err != nil {
+	err := r.withMessageRelations(r.db.Unscoped()).
+		Where("chat_room_id = ?", chatRoomID).
+		Order("seq DESC, created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&messages).Error
+	if err != nil {
 		return nil, 0, err
 	}
 
 	return messages, total, nil
 }
 
+// GetMessagesFromSeq seq 기준으로 이전(older, 최신순) 또는 이후(오래된 순) 메시지 조회 (삭제 표시 포함)
+func (r *ChatRepository) GetMessagesFromSeq(chatRoomID uint, seq int64, older bool, limit int) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+
+	query := r.withMessageRelations(r.db.Unscoped()).Where("chat_room_id = ?", chatRoomID)
+	if older {
+		query = query.Where("seq < ?", seq).Order("seq DESC")
+	} else {
+		query = query.Where("seq > ?", seq).Order("seq ASC")
+	}
+
+	err := query.Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// GetMessagesBySeqs 지정한 순번의 메시지 (오래된 순, 삭제 표시 포함)
+func (r *ChatRepository) GetMessagesBySeqs(chatRoomID uint, seqs []int64) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	if len(seqs) == 0 {
+		return messages, nil
+	}
+
+	err := r.withMessageRelations(r.db.Unscoped()).
+		Where("chat_room_id = ? AND seq IN ?", chatRoomID, seqs).
+		Order("seq ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// SearchMessages 내용 부분 일치 또는 trigram 단어 유사도로 검색 (장소 이름/주소 포함, 최신순)
+func (r *ChatRepository) SearchMessages(chatRoomID uint, query string, beforeSeq int64, limit int) ([]MessageSearchHit, error) {
+	var hits []MessageSearchHit
+
+	pattern := "%" + escapeLike(query) + "%"
+	db := r.db.Model(&models.ChatMessage{}).
+		Select("id, seq, GREATEST(word_similarity(?, content), word_similarity(?, place_name), word_similarity(?, address)) AS score", query, query, query).
+		Where("chat_room_id = ?", chatRoomID).
+		Where("content ILIKE ? OR ? <% content OR place_name ILIKE ? OR address ILIKE ?", pattern, query, pattern, pattern)
+	if beforeSeq > 0 {
+		db = db.Where("seq < ?", beforeSeq)
+	}
+
+	err := db.Order("seq DESC").Limit(limit).Scan(&hits).Error
+	return hits, err
+}
+
+// escapeLike LIKE 패턴 특수 문자 이스케이프
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// MarkRead 마지막 읽은 메시지를 앞으로만 갱신하고 이전 순번과 갱신 여부를 반환
+func (r *ChatRepository) MarkRead(chatRoomID, userID, messageID uint, seq int64) (int64, bool, error) {
+	var previous int64
+	advanced := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 처음 읽는 경우 행을 만든 뒤 잠금을 걸어 동시 갱신 시에도 순번이 뒤로 가지 않게 함
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.ChatReadState{ChatRoomID: chatRoomID, UserID: userID}).Error; err != nil {
+			return err
+		}
+
+		var state models.ChatReadState
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("chat_room_id = ? AND user_id = ?", chatRoomID, userID).
+			First(&state).Error; err != nil {
+			return err
+		}
+
+		previous = state.LastReadSeq
+		if seq <= state.LastReadSeq {
+			return nil
+		}
+
+		advanced = true
+		return tx.Model(&state).Updates(map[string]interface{}{
+			"last_read_message_id": messageID,
+			"last_read_seq":        seq,
+		}).Error
+	})
+
+	return previous, advanced, err
+}
+
+func (r *ChatRepository) GetReadStates(chatRoomID uint) ([]models.ChatReadState, error) {
+	var states []models.ChatReadState
+	err := r.db.Where("chat_room_id = ? AND last_read_seq > 0", chatRoomID).Find(&states).Error
+	return states, err
+}
+
+// GetUnreadCount 마지막으로 읽은 이후 다른 사용자가 보낸 메시지 수
+func (r *ChatRepository) GetUnreadCount(chatRoomID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ChatMessage{}).
+		Where("chat_room_id = ? AND (user_id IS NULL OR user_id <> ?)", chatRoomID, userID).
+		Where(`seq > COALESCE((
+			SELECT last_read_seq FROM chat_read_states
+			WHERE chat_room_id = ? AND user_id = ?
+		), 0)`, chatRoomID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ChatRepository) SetMute(chatRoomID, userID uint, mutedUntil *time.Time) error {
+	mute := &models.ChatRoomMute{
+		ChatRoomID: chatRoomID,
+		UserID:     userID,
+		MutedUntil: mutedUntil,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_room_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"muted_until", "updated_at"}),
+	}).Create(mute).Error
+}
+
+func (r *ChatRepository) DeleteMute(chatRoomID, userID uint) error {
+	return r.db.Where("chat_room_id = ? AND user_id = ?", chatRoomID, userID).
+		Delete(&models.ChatRoomMute{}).Error
+}
+
+func (r *ChatRepository) IsMuted(chatRoomID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ChatRoomMute{}).
+		Where("chat_room_id = ? AND user_id = ?", chatRoomID, userID).
+		Where("muted_until IS NULL OR muted_until > ?", time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetPushRecipients 발신자를 제외하고 채팅방 알림을 받을 참여자 (알림 끔/푸시 거부 사용자 제외)
+func (r *ChatRepository) GetPushRecipients(chatRoomID, senderID uint) ([]uint, error) {
+	var userIDs []uint
+
+	query := `
+		SELECT DISTINCT m.user_id
+		FROM (
+			SELECT s.creator_id AS user_id
+			FROM chat_rooms cr
+			JOIN signals s ON s.id = cr.signal_id
+			WHERE cr.id = ?
+			UNION
+			SELECT sp.user_id
+			FROM chat_rooms cr
+			JOIN signal_participants sp ON sp.signal_id = cr.signal_id
+			WHERE cr.id = ? AND sp.status = 'approved'
+		) m
+		LEFT JOIN user_profiles up ON up.user_id = m.user_id
+		WHERE m.user_id <> ?
+		AND COALESCE(up.push_notifications, TRUE)
+		AND NOT EXISTS (
+			SELECT 1 FROM chat_room_mutes mu
+			WHERE mu.chat_room_id = ? AND mu.user_id = m.user_id
+			AND (mu.muted_until IS NULL OR mu.muted_until > ?)
+		)
+	`
+
+	err := r.db.Raw(query, chatRoomID, chatRoomID, senderID, chatRoomID, time.Now()).Scan(&userIDs).Error
+	return userIDs, err
+}
+
+func (r *ChatRepository) CreateAttachment(attachment *models.ChatAttachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *ChatRepository) UpdateAttachment(attachment *models.ChatAttachment) error {
+	return r.db.Save(attachment).Error
+}
+
+func (r *ChatRepository) GetAttachmentByID(attachmentID uint) (*models.ChatAttachment, error) {
+	var attachment models.ChatAttachment
+	if err := r.db.First(&attachment, attachmentID).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *ChatRepository) GetAttachmentByUploadToken(token string) (*models.ChatAttachment, error) {
+	var attachment models.ChatAttachment
+	if err := r.db.Where("upload_token = ?", token).First(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *ChatRepository) GetMessageByAttachmentID(attachmentID uint) (*models.ChatMessage, error) {
+	var message models.ChatMessage
+	// 삭제된 메시지도 첨부를 점유하므로 포함
+	if err := r.db.Unscoped().Where("attachment_id = ?", attachmentID).First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func orderPollOptions(tx *gorm.DB) *gorm.DB {
+	return tx.Order("position ASC")
+}
+
+func (r *ChatRepository) GetPollByID(pollID uint) (*models.ChatPoll, error) {
+	var poll models.ChatPoll
+	if err := r.db.Preload("Options", orderPollOptions).Preload("Votes").First(&poll, pollID).Error; err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// ReplacePollVotes 사용자의 기존 선택을 지우고 새 선택 저장
+func (r *ChatRepository) ReplacePollVotes(pollID, userID uint, optionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("poll_id = ? AND user_id = ?", pollID, userID).Delete(&models.ChatPollVote{}).Error; err != nil {
+			return err
+		}
+		if len(optionIDs) == 0 {
+			return nil
+		}
+
+		votes := make([]models.ChatPollVote, 0, len(optionIDs))
+		for _, optionID := range optionIDs {
+			votes = append(votes, models.ChatPollVote{PollID: pollID, OptionID: optionID, UserID: userID})
+		}
+		return tx.Create(&votes).Error
+	})
+}
+
+func (r *ChatRepository) UpdatePoll(poll *models.ChatPoll) error {
+	return r.db.Model(poll).
+		Select("closed_at", "applied_option_id").
+		Updates(poll).Error
+}
+
+// SetMeetingPoint 채팅방에 고정할 만남 장소 메시지 지정 (nil이면 해제)
+func (r *ChatRepository) SetMeetingPoint(chatRoomID uint, messageID *uint) error {
+	return r.db.Model(&models.ChatRoom{}).
+		Where("id = ?", chatRoomID).
+		Update("meeting_point_message_id", messageID).Error
+}
+
+func (r *ChatRepository) SetSlowMode(chatRoomID uint, seconds int) error {
+	return r.db.Model(&models.ChatRoom{}).
+		Where("id = ?", chatRoomID).
+		Update("slow_mode_seconds", seconds).Error
+}
+
+// SetParticipantMute 참여자 채팅 제한 (이미 제한 중이면 시간 갱신)
+func (r *ChatRepository) SetParticipantMute(mute *models.ChatParticipantMute) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_room_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"muted_by_id", "muted_until", "updated_at"}),
+	}).Create(mute).Error
+}
+
+func (r *ChatRepository) DeleteParticipantMute(chatRoomID, userID uint) error {
+	return r.db.Where("chat_room_id = ? AND user_id = ?", chatRoomID, userID).
+		Delete(&models.ChatParticipantMute{}).Error
+}
+
+// GetActiveParticipantMute 아직 끝나지 않은 채팅 제한 조회
+func (r *ChatRepository) GetActiveParticipantMute(chatRoomID, userID uint) (*models.ChatParticipantMute, error) {
+	var mute models.ChatParticipantMute
+	err := r.db.Where("chat_room_id = ? AND user_id = ? AND muted_until > ?", chatRoomID, userID, time.Now()).
+		First(&mute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mute, nil
+}
+
+func (r *ChatRepository) CreateMessageReport(report *models.ReportUser) error {
+	return r.db.Create(report).Error
+}
+
+func (r *ChatRepository) HasReportedMessage(reporterID, messageID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ReportUser{}).
+		Where("reporter_id = ? AND chat_message_id = ?", reporterID, messageID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountParticipants 시그널 생성자와 승인된 참여자 수
+func (r *ChatRepository) CountParticipants(chatRoomID uint) (int64, error) {
+	var count int64
+	err := r.db.Raw(`
+		SELECT COUNT(*) FROM (
+			SELECT s.creator_id AS user_id
+			FROM chat_rooms cr
+			JOIN signals s ON s.id = cr.signal_id
+			WHERE cr.id = ?
+			UNION
+			SELECT sp.user_id
+			FROM chat_rooms cr
+			JOIN signal_participants sp ON sp.signal_id = cr.signal_id
+			WHERE cr.id = ? AND sp.status = ?
+		) participants
+	`, chatRoomID, chatRoomID, models.ParticipantApproved).Scan(&count).Error
+	return count, err
+}
+
+// AddExtendVote 연장 찬성 (이미 찬성했으면 무시)
+func (r *ChatRepository) AddExtendVote(vote *models.ChatExtendVote) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(vote).Error
+}
+
+func (r *ChatRepository) DeleteExtendVote(chatRoomID uint, round int, userID uint) error {
+	return r.db.Where("chat_room_id = ? AND round = ? AND user_id = ?", chatRoomID, round, userID).
+		Delete(&models.ChatExtendVote{}).Error
+}
+
+func (r *ChatRepository) CountExtendVotes(chatRoomID uint, round int) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ChatExtendVote{}).
+		Where("chat_room_id = ? AND round = ?", chatRoomID, round).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ChatRepository) HasExtendVote(chatRoomID uint, round int, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ChatExtendVote{}).
+		Where("chat_room_id = ? AND round = ? AND user_id = ?", chatRoomID, round, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExtendChatRoom 연장 횟수가 round일 때만 만료 시간 연장 (동시에 과반이 되어도 한 번만 연장)
+func (r *ChatRepository) ExtendChatRoom(chatRoomID uint, round int, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.ChatRoom{}).
+		Where("id = ? AND extension_count = ?", chatRoomID, round).
+		Updates(map[string]interface{}{
+			"expires_at":      expiresAt,
+			"extension_count": gorm.Expr("extension_count + 1"),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetUserMessages 사용자가 보낸 삭제되지 않은 메시지 (오래된 순)
+func (r *ChatRepository) GetUserMessages(chatRoomID, userID uint) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	err := r.db.Preload("User").
+		Preload("Attachment").
+		Preload("Poll").
+		Where("chat_room_id = ? AND user_id = ?", chatRoomID, userID).
+		Order("seq ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
 func (r *ChatRepository) UpdateChatRoomStatus(chatRoomID uint, status models.ChatRoomStatus) error {
 	return r.db.Model(&models.ChatRoom{}).
 		Where("id = ?", chatRoomID).
@@ -169,6 +629,32 @@ func (r *ChatRepository) DeleteChatRoom(chatRoomID uint) error {
 			return err
 		}
 
+		// 읽음 상태, 알림 설정 및 채팅 제한 삭제
+		if err := tx.Where("chat_room_id = ?", chatRoomID).Delete(&models.ChatReadState{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("chat_room_id = ?", chatRoomID).Delete(&models.ChatRoomMute{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("chat_room_id = ?", chatRoomID).Delete(&models.ChatParticipantMute{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("chat_room_id = ?", chatRoomID).Delete(&models.ChatExtendVote{}).Error; err != nil {
+			return err
+		}
+
+		// 투표 삭제
+		polls := tx.Model(&models.ChatPoll{}).Select("id").Where("chat_room_id = ?", chatRoomID)
+		if err := tx.Where("poll_id IN (?)", polls).Delete(&models.ChatPollVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("poll_id IN (?)", polls).Delete(&models.ChatPollOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("chat_room_id = ?", chatRoomID).Delete(&models.ChatPoll{}).Error; err != nil {
+			return err
+		}
+
 		// 채팅방 소프트 삭제
 		return tx.Delete(&models.ChatRoom{}, chatRoomID).Error
 	})
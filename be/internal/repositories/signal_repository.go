@@ -8,12 +8,14 @@ import (
 	"signal-module/pkg/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SignalRepositoryInterface interface {
 	Create(signal *models.Signal) error
 	GetByID(id uint) (*models.Signal, error)
 	Update(signal *models.Signal) error
+	UpdateChatRoomExpiry(signalID uint, expiresAt time.Time) error
 	Delete(id uint) error
 	Search(req *models.SearchSignalRequest) ([]models.SignalWithDistance, int64, error)
 	GetByUserID(userID uint, status []models.SignalStatus, page, limit int) ([]models.Signal, int64, error)
@@ -57,8 +59,16 @@ func (r *SignalRepository) GetByID(id uint) (*models.Signal, error) {
 	return &signal, nil
 }
 
+// Update 시그널 컬럼만 저장 (preload된 참여자/채팅방은 저장하지 않음)
 func (r *SignalRepository) Update(signal *models.Signal) error {
-	return r.db.Save(signal).Error
+	return r.db.Omit(clause.Associations).Save(signal).Error
+}
+
+// UpdateChatRoomExpiry 예정 시간이 바뀐 시그널의 활성 채팅방 만료 시간 변경
+func (r *SignalRepository) UpdateChatRoomExpiry(signalID uint, expiresAt time.Time) error {
+	return r.db.Model(&models.ChatRoom{}).
+		Where("signal_id = ? AND status = ?", signalID, models.ChatRoomActive).
+		Update("expires_at", expiresAt).Error
 }
 
 func (r *SignalRepository) Delete(id uint) error {
@@ -196,6 +206,8 @@ func (r *SignalRepository) LeaveSignal(signalID, userID uint) error {
 			return err
 		}
 
+		wasApproved := participant.Status == models.ParticipantApproved
+
 		now := time.Now()
 		participant.Status = models.ParticipantLeft
 		participant.LeftAt = &now
@@ -205,7 +217,7 @@ func (r *SignalRepository) LeaveSignal(signalID, userID uint) error {
 		}
 
 		// 승인된 상태였다면 참여자 수 감소
-		if participant.Status == models.ParticipantApproved {
+		if wasApproved {
 			if err := tx.Model(&models.Signal{}).
 				Where("id = ?", signalID).
 				Update("current_participants", gorm.Expr("current_participants - 1")).Error; err != nil {
@@ -12,6 +12,7 @@ type UserRepositoryInterface interface {
 	GetByEmail(email string) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByGoogleID(googleID string) (*models.User, error)
+	GetPresenceHiddenUserIDs(userIDs []uint) (map[uint]bool, error)
 	Update(user *models.User) error
 	UpdateLocation(userID uint, location *models.UserLocation) error
 	UpdateInterests(userID uint, interests []models.UserInterest) error
@@ -74,6 +75,26 @@ func (r *UserRepository) GetByGoogleID(googleID string) (*models.User, error) {
 	return &user, nil
 }
 
+// GetPresenceHiddenUserIDs 접속 상태를 공개하지 않는 사용자
+func (r *UserRepository) GetPresenceHiddenUserIDs(userIDs []uint) (map[uint]bool, error) {
+	hidden := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return hidden, nil
+	}
+
+	var ids []uint
+	if err := r.db.Model(&models.UserProfile{}).
+		Where("user_id IN ? AND presence_visible = ?", userIDs, false).
+		Pluck("user_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		hidden[id] = true
+	}
+	return hidden, nil
+}
+
 func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
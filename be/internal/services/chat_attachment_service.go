@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"signal-be/internal/repositories"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/storage"
+	"signal-module/pkg/utils"
+)
+
+// 서명된 다운로드 URL 유효 시간 (S3 등 지원 저장소만)
+const attachmentDownloadURLTTL = 5 * time.Minute
+
+type ChatAttachmentServiceInterface interface {
+	CreateUpload(userID, chatRoomID uint, req *models.CreateAttachmentRequest) (*models.AttachmentUploadResponse, error)
+	Upload(ctx context.Context, token string, body io.Reader) (*models.ChatAttachmentInfo, error)
+	Open(ctx context.Context, userID, attachmentID uint, thumbnail bool) (*AttachmentDownload, error)
+}
+
+// AttachmentDownload RedirectURL이 있으면 저장소로 리다이렉트, 없으면 Body를 직접 전달
+type AttachmentDownload struct {
+	RedirectURL string
+	Body        io.ReadCloser
+	ContentType string
+	FileName    string
+	Inline      bool
+}
+
+// ChatAttachmentService 첨부파일 업로드/다운로드
+//
+// 업로드는 두 단계로 진행된다. 먼저 메타데이터(이름, 형식, 크기)로 일회용 업로드 URL을 받고,
+// 그 URL로 본문을 PUT 하면 서버가 형식을 검사하고 이미지는 메타데이터 제거와 썸네일 생성 후 저장한다.
+type ChatAttachmentService struct {
+	chatRepo  repositories.ChatRepositoryInterface
+	blobStore storage.BlobStore
+	logger    *logger.Logger
+}
+
+func NewChatAttachmentService(
+	chatRepo repositories.ChatRepositoryInterface,
+	blobStore storage.BlobStore,
+	logger *logger.Logger,
+) ChatAttachmentServiceInterface {
+	return &ChatAttachmentService{
+		chatRepo:  chatRepo,
+		blobStore: blobStore,
+		logger:    logger,
+	}
+}
+
+func (s *ChatAttachmentService) CreateUpload(userID, chatRoomID uint, req *models.CreateAttachmentRequest) (*models.AttachmentUploadResponse, error) {
+	room, err := s.chatRepo.GetChatRoomByID(chatRoomID)
+	if err != nil {
+		return nil, fmt.Errorf("채팅방을 찾을 수 없습니다")
+	}
+	if room.Status != models.ChatRoomActive || (room.ExpiresAt != nil && !time.Now().Before(*room.ExpiresAt)) {
+		return nil, fmt.Errorf("종료된 채팅방입니다")
+	}
+
+	if ok, err := s.chatRepo.IsParticipant(chatRoomID, userID); err != nil || !ok {
+		return nil, fmt.Errorf("채팅방 참여자만 이용할 수 있습니다")
+	}
+
+	contentType := normalizeContentType(req.ContentType)
+	msgType, ok := models.AttachmentContentTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("지원하지 않는 파일 형식입니다")
+	}
+	if req.Size > maxAttachmentSize(msgType) {
+		return nil, fmt.Errorf("파일은 최대 %dMB까지 올릴 수 있습니다", maxAttachmentSize(msgType)>>20)
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("업로드 토큰 생성 실패: %w", err)
+	}
+
+	expiresAt := time.Now().Add(models.AttachmentUploadWindow)
+	attachment := &models.ChatAttachment{
+		ChatRoomID:      chatRoomID,
+		UploaderID:      userID,
+		Status:          models.AttachmentPending,
+		FileName:        sanitizeFileName(req.FileName),
+		ContentType:     contentType,
+		Size:            req.Size,
+		UploadToken:     token,
+		UploadExpiresAt: &expiresAt,
+	}
+
+	if err := s.chatRepo.CreateAttachment(attachment); err != nil {
+		s.logger.Error("첨부파일 생성 실패", err)
+		return nil, fmt.Errorf("업로드 준비에 실패했습니다")
+	}
+
+	return &models.AttachmentUploadResponse{
+		AttachmentID: attachment.ID,
+		UploadURL:    "/api/v1/chat/uploads/" + token,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// Upload 업로드 토큰으로 본문을 받아 검사/가공 후 저장 (토큰은 한 번만 사용 가능)
+func (s *ChatAttachmentService) Upload(ctx context.Context, token string, body io.Reader) (*models.ChatAttachmentInfo, error) {
+	attachment, err := s.chatRepo.GetAttachmentByUploadToken(token)
+	if err != nil || attachment.Status != models.AttachmentPending {
+		return nil, fmt.Errorf("유효하지 않은 업로드 URL입니다")
+	}
+	if attachment.UploadExpiresAt == nil || time.Now().After(*attachment.UploadExpiresAt) {
+		return nil, fmt.Errorf("업로드 URL이 만료되었습니다")
+	}
+
+	// 선언한 크기보다 큰 본문은 거부
+	data, err := io.ReadAll(io.LimitReader(body, attachment.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("파일을 읽을 수 없습니다")
+	}
+	if int64(len(data)) != attachment.Size {
+		return nil, fmt.Errorf("파일 크기가 요청한 크기와 다릅니다")
+	}
+
+	// 클라이언트가 선언한 형식을 믿지 않고 내용으로 확인
+	if sniffed := normalizeContentType(http.DetectContentType(data)); sniffed != attachment.ContentType {
+		return nil, fmt.Errorf("파일 내용이 형식(%s)과 일치하지 않습니다", attachment.ContentType)
+	}
+	if attachment.ContentType == "text/plain" && !utf8.Valid(data) {
+		return nil, fmt.Errorf("UTF-8 텍스트 파일만 올릴 수 있습니다")
+	}
+
+	prefix := fmt.Sprintf("chat/%d/%d-%s", attachment.ChatRoomID, attachment.ID, token[:16])
+	attachment.ObjectKey = prefix + attachmentExtension(attachment.ContentType)
+
+	if models.AttachmentContentTypes[attachment.ContentType] == models.MessageImage {
+		processed, err := utils.ProcessImage(data, attachment.ContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		data = processed.Data
+		attachment.Width = processed.Width
+		attachment.Height = processed.Height
+		attachment.ThumbnailKey = prefix + "-thumb" + attachmentExtension(processed.ThumbnailContentType)
+		attachment.ThumbnailContentType = processed.ThumbnailContentType
+
+		if err := s.blobStore.Put(ctx, attachment.ThumbnailKey, processed.ThumbnailContentType,
+			bytes.NewReader(processed.Thumbnail), int64(len(processed.Thumbnail))); err != nil {
+			s.logger.Error("썸네일 저장 실패", err)
+			return nil, fmt.Errorf("파일 저장에 실패했습니다")
+		}
+	}
+
+	if err := s.blobStore.Put(ctx, attachment.ObjectKey, attachment.ContentType, bytes.NewReader(data), int64(len(data))); err != nil {
+		s.logger.Error("첨부파일 저장 실패", err)
+		return nil, fmt.Errorf("파일 저장에 실패했습니다")
+	}
+
+	attachment.Size = int64(len(data))
+	attachment.Status = models.AttachmentReady
+	attachment.UploadToken = ""
+	attachment.UploadExpiresAt = nil
+
+	if err := s.chatRepo.UpdateAttachment(attachment); err != nil {
+		s.logger.Error("첨부파일 상태 갱신 실패", err)
+		return nil, fmt.Errorf("파일 저장에 실패했습니다")
+	}
+
+	return attachment.ToInfo(), nil
+}
+
+// Open 참여자에게 첨부파일 전달 (전송 전에는 올린 사람만, 삭제된 메시지의 첨부는 불가)
+func (s *ChatAttachmentService) Open(ctx context.Context, userID, attachmentID uint, thumbnail bool) (*AttachmentDownload, error) {
+	attachment, err := s.chatRepo.GetAttachmentByID(attachmentID)
+	if err != nil || attachment.Status != models.AttachmentReady {
+		return nil, fmt.Errorf("첨부파일을 찾을 수 없습니다")
+	}
+
+	if ok, err := s.chatRepo.IsParticipant(attachment.ChatRoomID, userID); err != nil || !ok {
+		return nil, fmt.Errorf("첨부파일을 찾을 수 없습니다")
+	}
+
+	message, err := s.chatRepo.GetMessageByAttachmentID(attachmentID)
+	if err != nil && attachment.UploaderID != userID {
+		return nil, fmt.Errorf("첨부파일을 찾을 수 없습니다")
+	}
+	if err == nil && message.DeletedAt.Valid {
+		return nil, fmt.Errorf("삭제된 메시지의 첨부파일입니다")
+	}
+
+	key, contentType := attachment.ObjectKey, attachment.ContentType
+	if thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, fmt.Errorf("썸네일이 없습니다")
+		}
+		key, contentType = attachment.ThumbnailKey, attachment.ThumbnailContentType
+	}
+
+	download := &AttachmentDownload{
+		ContentType: contentType,
+		FileName:    attachment.FileName,
+		Inline:      models.AttachmentContentTypes[attachment.ContentType] == models.MessageImage,
+	}
+
+	if url, err := s.blobStore.PresignGet(ctx, key, attachmentDownloadURLTTL); err == nil {
+		download.RedirectURL = url
+		return download, nil
+	}
+
+	body, err := s.blobStore.Open(ctx, key)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("첨부파일 %d 열기 실패", attachmentID), err)
+		return nil, fmt.Errorf("첨부파일을 찾을 수 없습니다")
+	}
+	download.Body = body
+
+	return download, nil
+}
+
+func maxAttachmentSize(msgType models.MessageType) int64 {
+	if msgType == models.MessageImage {
+		return models.MaxImageAttachmentSize
+	}
+	return models.MaxFileAttachmentSize
+}
+
+// normalizeContentType "text/plain; charset=utf-8" -> "text/plain"
+func normalizeContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
+
+func attachmentExtension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "application/pdf":
+		return ".pdf"
+	case "text/plain":
+		return ".txt"
+	}
+	return ""
+}
+
+// sanitizeFileName 경로와 제어 문자를 제거한 표시용 파일 이름
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
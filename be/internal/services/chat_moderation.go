@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"signal-module/pkg/config"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/redis"
+)
+
+// 모더레이션 오류 코드 (WebSocket error 이벤트의 code로 전달)
+const (
+	moderationBlocked     = "blocked_content"
+	moderationRateLimited = "rate_limited"
+	moderationSlowMode    = "slow_mode"
+	moderationMuted       = "muted"
+)
+
+// 필터 동작
+const (
+	filterOff    = "off"
+	filterAllow  = "allow"
+	filterMask   = "mask"
+	filterReject = "reject"
+)
+
+// ChatModerationError 모더레이션에 걸린 메시지 (REST는 코드에 따라 상태 코드 결정)
+type ChatModerationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ChatModerationError) Error() string { return e.Message }
+
+// 기본 금칙어 (공백/기호/숫자를 끼워 넣은 변형도 검사)
+//
+// 한글은 단어 중간에서도 찾고, 영어는 다른 단어의 일부(class, assume 등)를 막지 않도록 단어 단위로만 찾는다.
+var defaultBannedWords = []string{
+	"시발", "씨발", "씨빨", "씨팔", "시팔", "십팔놈", "ㅅㅂ", "ㅆㅂ",
+	"병신", "븅신", "ㅂㅅ", "좆", "존나", "졸라", "ㅈㄴ",
+	"개새끼", "개새기", "개색기", "개색히", "미친놈", "미친년", "지랄", "ㅈㄹ",
+	"니미", "느금마", "엠창", "염병", "썅",
+	"fuck", "fucking", "fucker", "fucked", "motherfucker", "shit", "shitty", "bullshit",
+	"bitch", "bastard", "asshole", "dick", "cunt", "wtf", "stfu",
+}
+
+// 금칙어를 포함하지만 정상적인 단어 (이 범위는 가리지 않음)
+var allowedWords = []string{"시발점", "시발역", "시발택시"}
+
+// chatLinkPattern 스킴이 있는 URL, www.로 시작하는 주소, 자주 쓰이는 최상위 도메인으로 끝나는 주소
+var chatLinkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"]+|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|kr|io|me|co|ly|gg|xyz|app|dev|link|site|shop|info|biz)\b(?:/[^\s<>"]*)?`)
+
+// ChatModerator 채팅 메시지 금칙어/링크 필터와 사용자별 전송 속도 제한
+type ChatModerator struct {
+	redisClient    *redis.Client
+	logger         *logger.Logger
+	profanity      string
+	links          string
+	allowedDomains []string
+	bannedWords    [][]rune
+	allowedWords   [][]rune
+	rateLimit      int
+	rateWindow     time.Duration
+}
+
+func NewChatModerator(cfg *config.ChatConfig, redisClient *redis.Client, logger *logger.Logger) *ChatModerator {
+	m := &ChatModerator{
+		redisClient: redisClient,
+		logger:      logger,
+		profanity:   filterMode(cfg.ProfanityFilter, filterMask, filterMask, filterReject, filterOff),
+		links:       filterMode(cfg.LinkFilter, filterAllow, filterAllow, filterMask, filterReject),
+		rateLimit:   cfg.RateLimitCount,
+		rateWindow:  cfg.RateLimitWindow,
+	}
+
+	for _, domain := range cfg.AllowedLinkDomains {
+		m.allowedDomains = append(m.allowedDomains, strings.ToLower(strings.TrimPrefix(domain, ".")))
+	}
+	for _, word := range append(append([]string{}, defaultBannedWords...), cfg.BannedWords...) {
+		if normalized := normalizeWord(word); len(normalized) > 0 {
+			m.bannedWords = append(m.bannedWords, normalized)
+		}
+	}
+	for _, word := range allowedWords {
+		m.allowedWords = append(m.allowedWords, normalizeWord(word))
+	}
+
+	return m
+}
+
+// filterMode 설정값이 허용 목록에 없으면 기본값 사용
+func filterMode(value, fallback string, allowed ...string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, mode := range allowed {
+		if value == mode {
+			return value
+		}
+	}
+	return fallback
+}
+
+// Filter 금칙어와 링크를 설정에 따라 가리거나 거부
+func (m *ChatModerator) Filter(content string) (string, error) {
+	if content == "" {
+		return content, nil
+	}
+
+	if m.links != filterAllow {
+		var blocked bool
+		content = chatLinkPattern.ReplaceAllStringFunc(content, func(link string) string {
+			if m.isAllowedLink(link) {
+				return link
+			}
+			blocked = true
+			return "[링크]"
+		})
+		if blocked && m.links == filterReject {
+			return "", &ChatModerationError{Code: moderationBlocked, Message: "링크는 보낼 수 없습니다"}
+		}
+	}
+
+	if m.profanity != filterOff {
+		masked, found := m.maskProfanity(content)
+		if found && m.profanity == filterReject {
+			return "", &ChatModerationError{Code: moderationBlocked, Message: "부적절한 표현이 포함되어 있습니다"}
+		}
+		content = masked
+	}
+
+	return content, nil
+}
+
+// maskProfanity 글자 사이의 공백/기호/숫자를 무시하고 금칙어를 찾아 해당 글자를 *로 가림
+func (m *ChatModerator) maskProfanity(content string) (string, bool) {
+	runes := []rune(content)
+
+	// 글자만 모은 소문자 문자열과 원문 위치
+	letters := make([]rune, 0, len(runes))
+	positions := make([]int, 0, len(runes))
+	for i, r := range runes {
+		if isWordRune(r) {
+			letters = append(letters, unicode.ToLower(r))
+			positions = append(positions, i)
+		}
+	}
+
+	protected := make([]bool, len(letters))
+	for _, word := range m.allowedWords {
+		for start := indexRunes(letters, word, 0); start >= 0; start = indexRunes(letters, word, start+1) {
+			for i := start; i < start+len(word); i++ {
+				protected[i] = true
+			}
+		}
+	}
+
+	found := false
+	for _, word := range m.bannedWords {
+		latin := word[0] < unicode.MaxASCII
+		for start := indexRunes(letters, word, 0); start >= 0; start = indexRunes(letters, word, start+1) {
+			end := start + len(word)
+			if protected[start] {
+				continue
+			}
+			first, last := positions[start], positions[end-1]
+			// 영어는 앞뒤가 다른 영문자와 이어지지 않을 때만 (원문 기준)
+			if latin && (isLatinAt(runes, first-1) || isLatinAt(runes, last+1)) {
+				continue
+			}
+			// 띄어쓰기를 넘는 일치는 "시 발"처럼 어절 전체일 때만 ("꽃병 신기"는 제외)
+			if containsSpace(runes[first:last+1]) && !(isWordBoundary(runes, first-1) && isWordBoundary(runes, last+1)) {
+				continue
+			}
+			for i := start; i < end; i++ {
+				runes[positions[i]] = '*'
+			}
+			found = true
+		}
+	}
+
+	return string(runes), found
+}
+
+func (m *ChatModerator) isAllowedLink(link string) bool {
+	if len(m.allowedDomains) == 0 {
+		return false
+	}
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, domain := range m.allowedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRate 사용자별 전송 속도 제한 (채팅방마다 따로 계산, Redis 장애 시 허용)
+func (m *ChatModerator) CheckRate(roomID string, userID uint) error {
+	if m.rateLimit <= 0 || m.rateWindow <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("chat:rate:%s:%d", roomID, userID)
+	count, err := m.redisClient.IncrWithExpire(context.Background(), key, m.rateWindow)
+	if err != nil {
+		m.logger.Warn(fmt.Sprintf("채팅 전송 속도 확인 실패 (%s): %v", key, err))
+		return nil
+	}
+	if count > int64(m.rateLimit) {
+		return &ChatModerationError{Code: moderationRateLimited, Message: "메시지를 너무 빠르게 보내고 있습니다. 잠시 후 다시 시도해주세요"}
+	}
+	return nil
+}
+
+// CheckSlowMode 슬로우 모드 간격 안에 이미 메시지를 보냈으면 남은 시간과 함께 거부
+func (m *ChatModerator) CheckSlowMode(roomID string, userID uint, seconds int) error {
+	if seconds <= 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	key := fmt.Sprintf("chat:slow:%s:%d", roomID, userID)
+	acquired, err := m.redisClient.SetNX(ctx, key, strconv.FormatInt(time.Now().Unix(), 10), time.Duration(seconds)*time.Second)
+	if err != nil {
+		m.logger.Warn(fmt.Sprintf("슬로우 모드 확인 실패 (%s): %v", key, err))
+		return nil
+	}
+	if acquired {
+		return nil
+	}
+
+	remaining := seconds
+	if ttl, err := m.redisClient.TTL(ctx, key); err == nil && ttl > 0 {
+		remaining = int(math.Ceil(ttl.Seconds()))
+	}
+	return &ChatModerationError{Code: moderationSlowMode, Message: fmt.Sprintf("슬로우 모드입니다. %d초 후에 보낼 수 있습니다", remaining)}
+}
+
+// normalizeWord 금칙어를 검사용 형태(글자만, 소문자)로 변환
+func normalizeWord(word string) []rune {
+	var normalized []rune
+	for _, r := range word {
+		if isWordRune(r) {
+			normalized = append(normalized, unicode.ToLower(r))
+		}
+	}
+	return normalized
+}
+
+// isWordRune 금칙어 검사에 사용하는 글자 (공백, 기호, 숫자는 건너뜀)
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r)
+}
+
+func isLatinAt(runes []rune, i int) bool {
+	if i < 0 || i >= len(runes) {
+		return false
+	}
+	r := unicode.ToLower(runes[i])
+	return r >= 'a' && r <= 'z'
+}
+
+func containsSpace(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordBoundary 문자열 밖이거나 공백인 위치
+func isWordBoundary(runes []rune, i int) bool {
+	return i < 0 || i >= len(runes) || unicode.IsSpace(runes[i])
+}
+
+func indexRunes(s, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"signal-be/internal/repositories"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/queue"
+	"signal-module/pkg/redis"
+)
+
+const (
+	chatPushDelay         = 10 * time.Second // 연속 메시지를 모으는 시간
+	chatPushCooldown      = time.Minute      // 같은 채팅방 알림 최소 간격 (사용자별)
+	chatPushFlushInterval = 5 * time.Second
+	chatPushBatchSize     = 100
+	chatPushPreviewLength = 50
+
+	// 발송 예정 알림 (member: "<chatRoomID>:<userID>", score: 발송 시각 unix ms)
+	chatPushDueKey = "chat:push:due"
+)
+
+// Redis 키 이름
+func chatPushPendingKey(member string) string { return "chat:push:pending:" + member }
+func chatPushLastKey(member string) string    { return "chat:push:last:" + member }
+
+// ChatNotificationService 채팅방에 접속하지 않은 참여자에게 새 메시지 푸시 알림 발송
+//
+// 메시지마다 알림을 보내지 않고 사용자별로 chatPushDelay 동안 모은 뒤, 발송 시점의
+// 읽지 않은 메시지 수로 "새 메시지 N개" 형태의 알림 하나를 큐에 넣는다.
+// 발송 예정 목록은 Redis에 있으므로 어느 인스턴스든 먼저 꺼낸 쪽이 한 번만 발송한다.
+type ChatNotificationService struct {
+	chatRepo    repositories.ChatRepositoryInterface
+	redisClient *redis.Client
+	queue       *queue.Queue
+	presence    PresenceServiceInterface
+	logger      *logger.Logger
+}
+
+func NewChatNotificationService(
+	chatRepo repositories.ChatRepositoryInterface,
+	redisClient *redis.Client,
+	queue *queue.Queue,
+	presence PresenceServiceInterface,
+	logger *logger.Logger,
+) *ChatNotificationService {
+	return &ChatNotificationService{
+		chatRepo:    chatRepo,
+		redisClient: redisClient,
+		queue:       queue,
+		presence:    presence,
+		logger:      logger,
+	}
+}
+
+// NotifyMessage 새로 저장된 메시지를 오프라인 참여자의 발송 예정 알림에 반영
+func (s *ChatNotificationService) NotifyMessage(room *models.ChatRoom, message *models.ChatMessage) {
+	if message.UserID == nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	recipients, err := s.chatRepo.GetPushRecipients(room.ID, *message.UserID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("채팅방 %d 알림 대상 조회 실패", room.ID), err)
+		return
+	}
+
+	sender := "알 수 없음"
+	if senderProfile := models.NewChatSender(message.User); senderProfile != nil {
+		sender = senderProfile.Username
+		if senderProfile.DisplayName != "" {
+			sender = senderProfile.DisplayName
+		}
+	}
+
+	for _, userID := range recipients {
+		if s.isReachable(ctx, room, userID) {
+			continue
+		}
+
+		member := fmt.Sprintf("%d:%d", room.ID, userID)
+
+		// 가장 최근 메시지를 미리보기로 사용
+		pendingKey := chatPushPendingKey(member)
+		if err := s.redisClient.HSet(ctx, pendingKey,
+			"room_name", room.Name,
+			"signal_id", room.SignalID,
+			"sender", sender,
+			"preview", chatPushPreview(message),
+		); err != nil {
+			s.logger.Error("채팅 알림 미리보기 저장 실패", err)
+			continue
+		}
+		s.redisClient.Expire(ctx, pendingKey, time.Hour)
+
+		// 이미 발송 예정이면 시각을 바꾸지 않음 (NX)
+		dueAt := time.Now().Add(chatPushDelay)
+		if lastStr, err := s.redisClient.Get(ctx, chatPushLastKey(member)); err == nil {
+			if lastMs, err := strconv.ParseInt(lastStr, 10, 64); err == nil {
+				if next := time.UnixMilli(lastMs).Add(chatPushCooldown); next.After(dueAt) {
+					dueAt = next
+				}
+			}
+		}
+
+		if err := s.redisClient.ZAddNX(ctx, chatPushDueKey, float64(dueAt.UnixMilli()), member); err != nil {
+			s.logger.Error("채팅 알림 예약 실패", err)
+		}
+	}
+}
+
+// Run 발송 시각이 된 알림을 주기적으로 큐에 넣음 (ctx 취소 시 종료)
+func (s *ChatNotificationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(chatPushFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.flushDue(ctx); err != nil {
+				s.logger.Error("채팅 알림 발송 처리 실패", err)
+			}
+		}
+	}
+}
+
+func (s *ChatNotificationService) flushDue(ctx context.Context) error {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	members, err := s.redisClient.ZRangeByScore(ctx, chatPushDueKey, "-inf", now, chatPushBatchSize)
+	if err != nil {
+		return fmt.Errorf("발송 예정 알림 조회 실패: %w", err)
+	}
+
+	for _, member := range members {
+		// 제거에 성공한 인스턴스만 발송
+		removed, err := s.redisClient.ZRem(ctx, chatPushDueKey, member)
+		if err != nil || removed == 0 {
+			continue
+		}
+
+		if err := s.send(ctx, member); err != nil {
+			s.logger.Error(fmt.Sprintf("채팅 알림 발송 실패: %s", member), err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ChatNotificationService) send(ctx context.Context, member string) error {
+	var chatRoomID, userID uint
+	if n, err := fmt.Sscanf(member, "%d:%d", &chatRoomID, &userID); n != 2 || err != nil {
+		return fmt.Errorf("잘못된 알림 키: %s", member)
+	}
+
+	// 미리보기 조회와 삭제를 한 번에 처리 (그 사이 들어온 메시지는 다음 알림으로)
+	pending, err := s.redisClient.HGetAllAndDelete(ctx, chatPushPendingKey(member))
+	if err != nil {
+		return fmt.Errorf("알림 미리보기 조회 실패: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// 대기 중에 알림을 껐으면 발송하지 않음
+	if muted, err := s.chatRepo.IsMuted(chatRoomID, userID); err == nil && muted {
+		return nil
+	}
+
+	// 대기 중에 채팅방에 들어와 읽었으면 발송하지 않음
+	unread, err := s.chatRepo.GetUnreadCount(chatRoomID, userID)
+	if err != nil {
+		return fmt.Errorf("읽지 않은 메시지 수 조회 실패: %w", err)
+	}
+	if unread == 0 {
+		return nil
+	}
+
+	roomName := pending["room_name"]
+	if roomName == "" {
+		roomName = "채팅방"
+	}
+
+	body := fmt.Sprintf("%s: %s", pending["sender"], pending["preview"])
+	if unread > 1 {
+		body = fmt.Sprintf("%s에 새 메시지 %d개", roomName, unread)
+	}
+
+	data := map[string]string{
+		"type":         "chat_message",
+		"chat_room_id": strconv.FormatUint(uint64(chatRoomID), 10),
+		"signal_id":    pending["signal_id"],
+		"unread_count": strconv.FormatInt(unread, 10),
+	}
+
+	if err := s.queue.PushNotification(ctx, []uint{userID}, roomName, body, data); err != nil {
+		return fmt.Errorf("푸시 알림 작업 추가 실패: %w", err)
+	}
+
+	lastKey := chatPushLastKey(member)
+	if err := s.redisClient.Set(ctx, lastKey, strconv.FormatInt(time.Now().UnixMilli(), 10), chatPushCooldown); err != nil {
+		s.logger.Warn(fmt.Sprintf("채팅 알림 발송 시각 저장 실패: %v", err))
+	}
+
+	return nil
+}
+
+// isReachable 채팅방에 접속해 있거나 다른 실시간 연결로 접속 중인 기기가 있으면 푸시를 보내지 않음
+func (s *ChatNotificationService) isReachable(ctx context.Context, room *models.ChatRoom, userID uint) bool {
+	field := strconv.FormatUint(uint64(userID), 10)
+	if countStr, err := s.redisClient.HGet(ctx, chatPresenceKey(chatRoomKey(room.SignalID)), field); err == nil {
+		if count, _ := strconv.Atoi(countStr); count > 0 {
+			return true
+		}
+	}
+
+	return s.presence.IsOnline(ctx, userID)
+}
+
+func chatPushPreview(message *models.ChatMessage) string {
+	switch message.Type {
+	case models.MessageImage:
+		return "사진을 보냈습니다"
+	case models.MessageFile:
+		return "파일을 보냈습니다"
+	case models.MessageLocation:
+		if message.PlaceName != "" {
+			return fmt.Sprintf("만남 장소: %s", message.PlaceName)
+		}
+		return "만남 장소를 공유했습니다"
+	case models.MessagePoll:
+		if message.Poll != nil {
+			return "투표: " + message.Poll.Question
+		}
+		return "투표를 만들었습니다"
+	}
+
+	content := strings.Join(strings.Fields(message.Content), " ")
+	if utf8.RuneCountInString(content) <= chatPushPreviewLength {
+		return content
+	}
+	return string([]rune(content)[:chatPushPreviewLength]) + "…"
+}
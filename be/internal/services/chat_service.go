@@ -1,28 +1,1507 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"signal-be/internal/repositories"
 	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
 	"signal-module/pkg/redis"
+	"signal-module/pkg/utils"
+)
+
+const (
+	chatMaxContentLength = 1000
+
+	// 이 시간 동안 갱신되지 않은 실시간 위치는 목록에서 제외
+	liveLocationStaleAfter = 5 * time.Minute
 )
 
+// chatLiveLocationKey 채팅방 실시간 위치 (field: 사용자 ID, 값: ChatLiveLocation JSON, 공유 가능 시간 종료 시 만료)
+func chatLiveLocationKey(roomID string) string { return "chat:live_location:" + roomID }
+
+type ChatServiceInterface interface {
+	GetChatRooms(userID uint) ([]models.ChatRoomInfo, error)
+	GetMessages(userID, chatRoomID uint, page, limit int) ([]*models.ChatEnvelope, *utils.Pagination, error)
+	GetMessageWindow(userID, chatRoomID uint, query *models.MessageWindowQuery) (*models.ChatMessageWindow, error)
+	SearchMessages(userID, chatRoomID uint, query string, before int64, limit, contextSize int) (*models.ChatSearchResponse, error)
+	SendMessage(userID, chatRoomID uint, req *models.SendMessageRequest) (*models.ChatEnvelope, error)
+	EditMessage(userID, chatRoomID, messageID uint, req *models.EditMessageRequest) (*models.ChatEnvelope, error)
+	DeleteMessage(userID, chatRoomID, messageID uint) (*models.ChatEnvelope, error)
+	MarkRead(userID, chatRoomID, messageID uint) (*models.ChatEnvelope, error)
+	MuteRoom(userID, chatRoomID uint, req *models.MuteChatRoomRequest) error
+	UnmuteRoom(userID, chatRoomID uint) error
+	ShareLiveLocation(userID uint, username string, chatRoomID uint, payload *models.ChatLiveLocationPayload) (*models.ChatEnvelope, error)
+	StopLiveLocation(userID uint, username string, chatRoomID uint) error
+	GetLiveLocations(userID, chatRoomID uint) (*models.ChatLiveLocationsResponse, error)
+	GetPoll(userID, chatRoomID, pollID uint) (*models.ChatPollInfo, error)
+	VotePoll(userID, chatRoomID, pollID uint, req *models.VotePollRequest) (*models.ChatPollInfo, error)
+	ClosePoll(userID, chatRoomID, pollID uint) (*models.ChatPollInfo, error)
+	ApplyPoll(userID, chatRoomID, pollID uint, req *models.ApplyPollRequest) (*models.ChatPollInfo, error)
+	ReportMessage(userID, messageID uint, req *models.ReportMessageRequest) error
+	MuteParticipant(userID, chatRoomID, targetID uint, req *models.MuteParticipantRequest) (*models.ChatParticipantMute, error)
+	UnmuteParticipant(userID, chatRoomID, targetID uint) error
+	SetSlowMode(userID, chatRoomID uint, req *models.SlowModeRequest) error
+	GetExtendStatus(userID, chatRoomID uint) (*models.ChatExtendStatus, error)
+	VoteExtend(userID, chatRoomID uint) (*models.ChatExtendStatus, error)
+	WithdrawExtendVote(userID, chatRoomID uint) (*models.ChatExtendStatus, error)
+	ExportMessages(userID, chatRoomID uint) (*models.ChatTranscript, error)
+	GetOrCreateSignalChatRoom(signalID uint) (*models.ChatRoom, error)
+}
+
 type ChatService struct {
-	chatRepo    repositories.ChatRepositoryInterface
-	signalRepo  repositories.SignalRepositoryInterface
-	redisClient *redis.Client
-	logger      *logger.Logger
+	chatRepo      repositories.ChatRepositoryInterface
+	signalRepo    repositories.SignalRepositoryInterface
+	signalService SignalServiceInterface
+	redisClient   *redis.Client
+	notifier      *ChatNotificationService
+	moderator     *ChatModerator
+	logger        *logger.Logger
 }
 
 func NewChatService(
 	chatRepo repositories.ChatRepositoryInterface,
 	signalRepo repositories.SignalRepositoryInterface,
+	signalService SignalServiceInterface,
 	redisClient *redis.Client,
+	notifier *ChatNotificationService,
+	moderator *ChatModerator,
 	logger *logger.Logger,
-) *ChatService {
+) ChatServiceInterface {
 	return &ChatService{
-		chatRepo:    chatRepo,
-		signalRepo:  signalRepo,
-		redisClient: redisClient,
-		logger:      logger,
+		chatRepo:      chatRepo,
+		signalRepo:    signalRepo,
+		signalService: signalService,
+		redisClient:   redisClient,
+		notifier:      notifier,
+		moderator:     moderator,
+		logger:        logger,
+	}
+}
+
+func (s *ChatService) GetChatRooms(userID uint) ([]models.ChatRoomInfo, error) {
+	rooms, err := s.chatRepo.GetChatRoomsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("채팅방 목록 조회 실패: %w", err)
+	}
+	return rooms, nil
+}
+
+// GetMessages 채팅방 메시지 히스토리를 WebSocket과 동일한 봉투 형식으로 반환 (최신순)
+func (s *ChatService) GetMessages(userID, chatRoomID uint, page, limit int) ([]*models.ChatEnvelope, *utils.Pagination, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, nil, err
+	}
+
+	messages, total, err := s.chatRepo.GetMessages(chatRoomID, page, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("메시지 조회 실패: %w", err)
+	}
+
+	// 읽음 상태는 채팅방 참여자 수만큼만 존재하므로 한 번에 조회하여 read_by 계산
+	states, err := s.chatRepo.GetReadStates(chatRoomID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("채팅방 %d 읽음 상태 조회 실패: %v", chatRoomID, err))
+	}
+
+	envelopes := make([]*models.ChatEnvelope, 0, len(messages))
+	for i := range messages {
+		envelopes = append(envelopes, messages[i].ToEnvelopeWithReads(states))
+	}
+
+	pagination := utils.CalculatePagination(page, limit, total)
+	return envelopes, &pagination, nil
+}
+
+// GetMessageWindow 커서 기반 히스토리 조회 ("메시지로 이동"은 around, 이어서 스크롤은 before/after)
+func (s *ChatService) GetMessageWindow(userID, chatRoomID uint, query *models.MessageWindowQuery) (*models.ChatMessageWindow, error) {
+	limit := query.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	var older, newer []models.ChatMessage
+	var hasOlder, hasNewer bool
+	window := &models.ChatMessageWindow{}
+
+	switch {
+	case query.Around != 0:
+		anchor, err := s.chatRepo.GetMessageByIDUnscoped(query.Around)
+		if err != nil || anchor.ChatRoomID != chatRoomID {
+			return nil, fmt.Errorf("메시지를 찾을 수 없습니다")
+		}
+		window.AnchorID = anchor.ID
+
+		// 기준 메시지 앞쪽에 절반, 기준 메시지부터 뒤쪽에 나머지
+		if older, hasOlder, err = s.messagesFromSeq(chatRoomID, anchor.Seq, true, limit/2); err != nil {
+			return nil, err
+		}
+		if newer, hasNewer, err = s.messagesFromSeq(chatRoomID, anchor.Seq-1, false, limit-len(older)); err != nil {
+			return nil, err
+		}
+
+	case query.After != 0:
+		var err error
+		if newer, hasNewer, err = s.messagesFromSeq(chatRoomID, query.After, false, limit); err != nil {
+			return nil, err
+		}
+		hasOlder = true
+
+	default:
+		// before가 없으면 가장 최근 메시지부터
+		before := query.Before
+		if before <= 0 {
+			before = math.MaxInt64
+		}
+		var err error
+		if older, hasOlder, err = s.messagesFromSeq(chatRoomID, before, true, limit); err != nil {
+			return nil, err
+		}
+		hasNewer = query.Before > 0
+	}
+
+	messages := make([]models.ChatMessage, 0, len(older)+len(newer))
+	for i := len(older) - 1; i >= 0; i-- {
+		messages = append(messages, older[i])
+	}
+	messages = append(messages, newer...)
+
+	states, err := s.chatRepo.GetReadStates(chatRoomID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("채팅방 %d 읽음 상태 조회 실패: %v", chatRoomID, err))
+	}
+
+	window.Messages = make([]*models.ChatEnvelope, 0, len(messages))
+	for i := range messages {
+		window.Messages = append(window.Messages, messages[i].ToEnvelopeWithReads(states))
+	}
+	if len(messages) > 0 {
+		first, last := messages[0].Seq, messages[len(messages)-1].Seq
+		if hasOlder {
+			window.Before = &first
+		}
+		if hasNewer {
+			window.After = &last
+		}
+	}
+
+	return window, nil
+}
+
+// messagesFromSeq limit개를 조회하고 그 너머에 메시지가 더 있는지 함께 반환
+func (s *ChatService) messagesFromSeq(chatRoomID uint, seq int64, older bool, limit int) ([]models.ChatMessage, bool, error) {
+	if limit <= 0 {
+		return nil, true, nil
+	}
+
+	messages, err := s.chatRepo.GetMessagesFromSeq(chatRoomID, seq, older, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("메시지 조회 실패: %w", err)
+	}
+	if len(messages) > limit {
+		return messages[:limit], true, nil
+	}
+	return messages, false, nil
+}
+
+// SearchMessages 채팅방 메시지 검색 (최신순, 결과마다 앞뒤 contextSize개의 메시지 포함)
+func (s *ChatService) SearchMessages(userID, chatRoomID uint, query string, before int64, limit, contextSize int) (*models.ChatSearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < 2 {
+		return nil, fmt.Errorf("검색어는 2자 이상 입력해주세요")
+	}
+	if utf8.RuneCountInString(query) > 100 {
+		return nil, fmt.Errorf("검색어는 최대 100자까지 입력할 수 있습니다")
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	if contextSize < 0 || contextSize > 5 {
+		contextSize = 2
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	hits, err := s.chatRepo.SearchMessages(chatRoomID, query, before, limit+1)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("채팅방 %d 메시지 검색 실패", chatRoomID), err)
+		return nil, fmt.Errorf("메시지 검색에 실패했습니다")
+	}
+
+	response := &models.ChatSearchResponse{Query: query, Results: []models.ChatSearchResult{}}
+	if len(hits) > limit {
+		hits = hits[:limit]
+		next := hits[limit-1].Seq
+		response.NextBefore = &next
+	}
+	if len(hits) == 0 {
+		return response, nil
+	}
+
+	// 일치한 메시지와 앞뒤 문맥을 한 번에 조회
+	seqs := make([]int64, 0, len(hits)*(2*contextSize+1))
+	for _, hit := range hits {
+		for seq := hit.Seq - int64(contextSize); seq <= hit.Seq+int64(contextSize); seq++ {
+			seqs = append(seqs, seq)
+		}
+	}
+	messages, err := s.chatRepo.GetMessagesBySeqs(chatRoomID, seqs)
+	if err != nil {
+		return nil, fmt.Errorf("메시지 조회 실패: %w", err)
+	}
+
+	bySeq := make(map[int64]*models.ChatEnvelope, len(messages))
+	for i := range messages {
+		bySeq[messages[i].Seq] = messages[i].ToEnvelope()
+	}
+
+	for _, hit := range hits {
+		result := models.ChatSearchResult{Message: bySeq[hit.Seq], Score: hit.Score}
+		if result.Message == nil {
+			continue
+		}
+		for seq := hit.Seq - int64(contextSize); seq <= hit.Seq+int64(contextSize); seq++ {
+			if envelope, ok := bySeq[seq]; ok {
+				result.Context = append(result.Context, envelope)
+			}
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// SendMessage 메시지를 저장하고 채팅방의 모든 인스턴스로 전파
+//
+// 같은 client_msg_id로 재전송된 메시지는 새로 저장하지 않고 기존 메시지를 다시 전파하므로,
+// 클라이언트는 server_id/seq 기준으로 중복을 제거하면 된다.
+func (s *ChatService) SendMessage(userID, chatRoomID uint, req *models.SendMessageRequest) (*models.ChatEnvelope, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateChatMessage(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkMuted(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+	if err := s.filterMessage(req); err != nil {
+		return nil, err
+	}
+
+	// 답장 대상은 같은 채팅방의 삭제되지 않은 메시지만 가능
+	if req.ReplyToID != nil {
+		target, err := s.chatRepo.GetMessageByID(*req.ReplyToID)
+		if err != nil || target.ChatRoomID != chatRoomID {
+			return nil, fmt.Errorf("답장할 메시지를 찾을 수 없습니다")
+		}
+	}
+
+	// 만남 장소는 호스트만 지정
+	if req.Type == models.MessageLocation && !s.isHost(userID, room) {
+		return nil, fmt.Errorf("만남 장소는 호스트만 공유할 수 있습니다")
+	}
+
+	var attachment *models.ChatAttachment
+	if req.AttachmentID != nil {
+		if attachment, err = s.getSendableAttachment(userID, chatRoomID, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.throttle(userID, room); err != nil {
+		return nil, err
+	}
+
+	message, created, err := s.persistMessage(userID, chatRoomID, req, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := message.ToEnvelope()
+	s.publish(room, envelope)
+
+	// 재전송된 메시지는 이미 알림 대상에 반영됨
+	if created {
+		if message.Type == models.MessageLocation {
+			if err := s.chatRepo.SetMeetingPoint(room.ID, &message.ID); err != nil {
+				s.logger.Error(fmt.Sprintf("채팅방 %d 만남 장소 고정 실패", room.ID), err)
+			}
+		}
+		go s.notifier.NotifyMessage(room, message)
+	}
+
+	return envelope, nil
+}
+
+// EditMessage 작성자가 수정 가능 시간 내에 텍스트 메시지 내용을 수정
+func (s *ChatService) EditMessage(userID, chatRoomID, messageID uint, req *models.EditMessageRequest) (*models.ChatEnvelope, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.chatRepo.GetMessageByID(messageID)
+	if err != nil || message.ChatRoomID != chatRoomID {
+		return nil, fmt.Errorf("메시지를 찾을 수 없습니다")
+	}
+
+	if message.UserID == nil || *message.UserID != userID {
+		return nil, fmt.Errorf("본인이 보낸 메시지만 수정할 수 있습니다")
+	}
+
+	if message.Type != models.MessageText {
+		return nil, fmt.Errorf("텍스트 메시지만 수정할 수 있습니다")
+	}
+
+	if time.Since(message.CreatedAt) > models.ChatMessageEditWindow {
+		return nil, fmt.Errorf("메시지는 전송 후 %d분 이내에만 수정할 수 있습니다", int(models.ChatMessageEditWindow.Minutes()))
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, fmt.Errorf("메시지 내용을 입력해주세요")
+	}
+	if utf8.RuneCountInString(content) > chatMaxContentLength {
+		return nil, fmt.Errorf("메시지는 최대 %d자까지 입력할 수 있습니다", chatMaxContentLength)
+	}
+
+	if err := s.checkMuted(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+	if content, err = s.moderator.Filter(content); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	message.Content = content
+	message.IsEdited = true
+	message.EditedAt = &now
+
+	if err := s.chatRepo.UpdateMessage(message); err != nil {
+		s.logger.Error("채팅 메시지 수정 실패", err)
+		return nil, fmt.Errorf("메시지 수정에 실패했습니다")
+	}
+
+	envelope := message.ToEventEnvelope(models.ChatEventEdit)
+	s.publish(room, envelope)
+
+	return envelope, nil
+}
+
+// DeleteMessage 작성자 또는 시그널 호스트가 모두에게서 메시지 삭제
+func (s *ChatService) DeleteMessage(userID, chatRoomID, messageID uint) (*models.ChatEnvelope, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.chatRepo.GetMessageByID(messageID)
+	if err != nil || message.ChatRoomID != chatRoomID {
+		return nil, fmt.Errorf("메시지를 찾을 수 없습니다")
+	}
+
+	isAuthor := message.UserID != nil && *message.UserID == userID
+	if !isAuthor && !s.isHost(userID, room) {
+		return nil, fmt.Errorf("메시지를 삭제할 권한이 없습니다")
+	}
+
+	if err := s.chatRepo.DeleteMessage(messageID); err != nil {
+		s.logger.Error("채팅 메시지 삭제 실패", err)
+		return nil, fmt.Errorf("메시지 삭제에 실패했습니다")
+	}
+
+	// 고정된 만남 장소가 삭제되면 고정 해제 (거리 계산은 시그널 장소 기준으로 돌아감)
+	if room.MeetingPointMessageID != nil && *room.MeetingPointMessageID == messageID {
+		if err := s.chatRepo.SetMeetingPoint(room.ID, nil); err != nil {
+			s.logger.Error(fmt.Sprintf("채팅방 %d 만남 장소 고정 해제 실패", room.ID), err)
+		}
+	}
+
+	deleted, err := s.chatRepo.GetMessageByIDUnscoped(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("삭제된 메시지 조회 실패: %w", err)
+	}
+
+	envelope := deleted.ToEventEnvelope(models.ChatEventDelete)
+	s.publish(room, envelope)
+
+	return envelope, nil
+}
+
+// MarkRead 지정한 메시지까지 읽음 처리하고, 읽은 위치가 앞으로 이동했을 때만 read 이벤트 전파
+func (s *ChatService) MarkRead(userID, chatRoomID, messageID uint) (*models.ChatEnvelope, error) {
+	room, err := s.chatRepo.GetChatRoomByID(chatRoomID)
+	if err != nil {
+		return nil, fmt.Errorf("채팅방을 찾을 수 없습니다")
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	message, err := s.chatRepo.GetMessageByIDUnscoped(messageID)
+	if err != nil || message.ChatRoomID != chatRoomID {
+		return nil, fmt.Errorf("메시지를 찾을 수 없습니다")
+	}
+
+	previous, advanced, err := s.chatRepo.MarkRead(chatRoomID, userID, messageID, message.Seq)
+	if err != nil {
+		s.logger.Error("읽음 상태 갱신 실패", err)
+		return nil, fmt.Errorf("읽음 처리에 실패했습니다")
+	}
+
+	envelope := models.NewChatEnvelope(models.ChatEventRead, chatRoomID, &models.ChatReadPayload{
+		LastReadMessageID: messageID,
+		LastReadSeq:       message.Seq,
+		PreviousSeq:       previous,
+	})
+	envelope.ServerID = messageID
+	envelope.Seq = message.Seq
+	envelope.Sender = &models.ChatSender{ID: userID}
+
+	if advanced {
+		s.publish(room, envelope)
+	}
+
+	return envelope, nil
+}
+
+// MuteRoom 채팅방 알림 끄기 (duration_minutes가 0이면 직접 해제할 때까지)
+func (s *ChatService) MuteRoom(userID, chatRoomID uint, req *models.MuteChatRoomRequest) error {
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return err
+	}
+
+	var mutedUntil *time.Time
+	if req.DurationMinutes > 0 {
+		until := time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+		mutedUntil = &until
+	}
+
+	if err := s.chatRepo.SetMute(chatRoomID, userID, mutedUntil); err != nil {
+		s.logger.Error("채팅방 알림 끄기 실패", err)
+		return fmt.Errorf("채팅방 알림 설정에 실패했습니다")
+	}
+	return nil
+}
+
+func (s *ChatService) UnmuteRoom(userID, chatRoomID uint) error {
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return err
+	}
+
+	if err := s.chatRepo.DeleteMute(chatRoomID, userID); err != nil {
+		s.logger.Error("채팅방 알림 켜기 실패", err)
+		return fmt.Errorf("채팅방 알림 설정에 실패했습니다")
+	}
+	return nil
+}
+
+// ShareLiveLocation 약속 시간 전후에 참여자의 현재 위치를 만남 장소까지의 거리와 함께 전파
+//
+// 위치는 DB에 저장하지 않고, 뒤늦게 접속한 참여자를 위해 공유 가능 시간이 끝나면 만료되는 Redis 해시에만 보관한다.
+func (s *ChatService) ShareLiveLocation(userID uint, username string, chatRoomID uint, payload *models.ChatLiveLocationPayload) (*models.ChatEnvelope, error) {
+	if !payload.Sharing {
+		return nil, s.StopLiveLocation(userID, username, chatRoomID)
+	}
+
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	signal, err := s.signalRepo.GetByID(room.SignalID)
+	if err != nil {
+		return nil, fmt.Errorf("시그널을 찾을 수 없습니다")
+	}
+
+	startsAt, endsAt := models.LiveLocationWindow(signal.ScheduledAt)
+	now := time.Now()
+	if now.Before(startsAt) || !now.Before(endsAt) {
+		return nil, fmt.Errorf("위치 공유는 약속 시간 %d분 전부터 %d분 후까지만 가능합니다",
+			int(models.LiveLocationLeadTime.Minutes()), int(models.LiveLocationTrailTime.Minutes()))
+	}
+
+	if !utils.IsValidCoordinate(payload.Latitude, payload.Longitude) {
+		return nil, fmt.Errorf("위치 좌표가 올바르지 않습니다")
+	}
+
+	venue := s.meetingPoint(room, signal)
+	distance := utils.CalculateDistance(payload.Latitude, payload.Longitude, venue.Latitude, venue.Longitude)
+
+	location := models.ChatLiveLocation{
+		Sender: &models.ChatSender{ID: userID, Username: username},
+		ChatLiveLocationPayload: models.ChatLiveLocationPayload{
+			Sharing:        true,
+			Latitude:       payload.Latitude,
+			Longitude:      payload.Longitude,
+			Accuracy:       payload.Accuracy,
+			DistanceMeters: &distance,
+			UpdatedAt:      now,
+			ExpiresAt:      &endsAt,
+		},
+	}
+
+	ctx := context.Background()
+	key := chatLiveLocationKey(chatRoomKey(room.SignalID))
+	if data, err := json.Marshal(&location); err == nil {
+		if err := s.redisClient.HSet(ctx, key, strconv.FormatUint(uint64(userID), 10), data); err != nil {
+			s.logger.Error(fmt.Sprintf("채팅방 %d 실시간 위치 저장 실패", room.ID), err)
+		}
+		s.redisClient.Expire(ctx, key, time.Until(endsAt))
+	}
+
+	envelope := models.NewChatEnvelope(models.ChatEventLiveLocation, room.ID, &location.ChatLiveLocationPayload)
+	envelope.Sender = location.Sender
+	s.publish(room, envelope)
+
+	return envelope, nil
+}
+
+// StopLiveLocation 위치 공유 종료 (연결이 끊길 때도 호출되므로 종료된 채팅방에서도 동작)
+func (s *ChatService) StopLiveLocation(userID uint, username string, chatRoomID uint) error {
+	room, err := s.chatRepo.GetChatRoomByID(chatRoomID)
+	if err != nil {
+		return fmt.Errorf("채팅방을 찾을 수 없습니다")
+	}
+
+	key := chatLiveLocationKey(chatRoomKey(room.SignalID))
+	if err := s.redisClient.HDel(context.Background(), key, strconv.FormatUint(uint64(userID), 10)); err != nil {
+		s.logger.Error(fmt.Sprintf("채팅방 %d 실시간 위치 삭제 실패", room.ID), err)
 	}
-}
\ No newline at end of file
+
+	envelope := models.NewChatEnvelope(models.ChatEventLiveLocation, room.ID, &models.ChatLiveLocationPayload{
+		Sharing:   false,
+		UpdatedAt: time.Now(),
+	})
+	envelope.Sender = &models.ChatSender{ID: userID, Username: username}
+	s.publish(room, envelope)
+
+	return nil
+}
+
+// GetLiveLocations 위치를 공유 중인 참여자를 만남 장소에서 가까운 순으로 반환
+func (s *ChatService) GetLiveLocations(userID, chatRoomID uint) (*models.ChatLiveLocationsResponse, error) {
+	room, err := s.chatRepo.GetChatRoomByID(chatRoomID)
+	if err != nil {
+		return nil, fmt.Errorf("채팅방을 찾을 수 없습니다")
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	signal, err := s.signalRepo.GetByID(room.SignalID)
+	if err != nil {
+		return nil, fmt.Errorf("시그널을 찾을 수 없습니다")
+	}
+
+	startsAt, endsAt := models.LiveLocationWindow(signal.ScheduledAt)
+	response := &models.ChatLiveLocationsResponse{
+		MeetingPoint: s.meetingPoint(room, signal),
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+		Locations:    []models.ChatLiveLocation{},
+	}
+
+	values, err := s.redisClient.HGetAll(context.Background(), chatLiveLocationKey(chatRoomKey(room.SignalID)))
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("채팅방 %d 실시간 위치 조회 실패", room.ID), err)
+		return response, nil
+	}
+
+	for _, value := range values {
+		var location models.ChatLiveLocation
+		if err := json.Unmarshal([]byte(value), &location); err != nil {
+			continue
+		}
+		if time.Since(location.UpdatedAt) > liveLocationStaleAfter {
+			continue
+		}
+		response.Locations = append(response.Locations, location)
+	}
+
+	sort.Slice(response.Locations, func(i, j int) bool {
+		return *response.Locations[i].DistanceMeters < *response.Locations[j].DistanceMeters
+	})
+
+	return response, nil
+}
+
+// meetingPoint 고정된 만남 장소, 없으면 시그널 장소
+func (s *ChatService) meetingPoint(room *models.ChatRoom, signal *models.Signal) *models.ChatLocation {
+	if room.MeetingPointMessageID != nil {
+		if message, err := s.chatRepo.GetMessageByID(*room.MeetingPointMessageID); err == nil {
+			if location := message.Location(); location != nil {
+				return location
+			}
+		}
+	}
+
+	return &models.ChatLocation{
+		Latitude:  signal.Latitude,
+		Longitude: signal.Longitude,
+		PlaceName: signal.PlaceName,
+		Address:   signal.Address,
+	}
+}
+
+// GetPoll 투표 정보와 요청자 본인의 선택 (익명 투표에서도 본인 선택은 확인 가능)
+func (s *ChatService) GetPoll(userID, chatRoomID, pollID uint) (*models.ChatPollInfo, error) {
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	poll, err := s.getPoll(chatRoomID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pollInfoFor(poll, userID), nil
+}
+
+// VotePoll 내 선택을 교체하고 새 집계를 채팅방에 전파
+func (s *ChatService) VotePoll(userID, chatRoomID, pollID uint, req *models.VotePollRequest) (*models.ChatPollInfo, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := s.getPoll(chatRoomID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.IsClosed(time.Now()) {
+		return nil, fmt.Errorf("마감된 투표입니다")
+	}
+
+	valid := make(map[uint]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.ID] = true
+	}
+
+	optionIDs := make([]uint, 0, len(req.OptionIDs))
+	seen := make(map[uint]bool, len(req.OptionIDs))
+	for _, optionID := range req.OptionIDs {
+		if !valid[optionID] {
+			return nil, fmt.Errorf("선택지를 찾을 수 없습니다")
+		}
+		if !seen[optionID] {
+			seen[optionID] = true
+			optionIDs = append(optionIDs, optionID)
+		}
+	}
+
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return nil, fmt.Errorf("하나만 선택할 수 있는 투표입니다")
+	}
+
+	if err := s.chatRepo.ReplacePollVotes(poll.ID, userID, optionIDs); err != nil {
+		s.logger.Error("투표 저장 실패", err)
+		return nil, fmt.Errorf("투표에 실패했습니다")
+	}
+
+	return s.publishPoll(room, poll.ID, userID)
+}
+
+// ClosePoll 투표를 만든 사람 또는 호스트가 투표 마감
+func (s *ChatService) ClosePoll(userID, chatRoomID, pollID uint) (*models.ChatPollInfo, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := s.getPoll(chatRoomID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.CreatorID != userID && !s.isHost(userID, room) {
+		return nil, fmt.Errorf("투표를 만든 사람이나 호스트만 마감할 수 있습니다")
+	}
+
+	if poll.ClosedAt == nil {
+		now := time.Now()
+		poll.ClosedAt = &now
+		if err := s.chatRepo.UpdatePoll(poll); err != nil {
+			s.logger.Error("투표 마감 실패", err)
+			return nil, fmt.Errorf("투표 마감에 실패했습니다")
+		}
+	}
+
+	return s.publishPoll(room, poll.ID, userID)
+}
+
+// ApplyPoll 호스트가 시간/장소 투표의 선택지를 시그널에 반영 (시그널 수정과 같은 검증을 거침)
+func (s *ChatService) ApplyPoll(userID, chatRoomID, pollID uint, req *models.ApplyPollRequest) (*models.ChatPollInfo, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isHost(userID, room) {
+		return nil, fmt.Errorf("호스트만 투표 결과를 반영할 수 있습니다")
+	}
+
+	poll, err := s.getPoll(chatRoomID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	var option *models.ChatPollOption
+	for i := range poll.Options {
+		if poll.Options[i].ID == req.OptionID {
+			option = &poll.Options[i]
+			break
+		}
+	}
+	if option == nil {
+		return nil, fmt.Errorf("선택지를 찾을 수 없습니다")
+	}
+
+	update := &models.UpdateSignalRequest{}
+	switch poll.Kind {
+	case models.PollTime:
+		update.ScheduledAt = option.ScheduledAt
+	case models.PollPlace:
+		update.Latitude = option.Latitude
+		update.Longitude = option.Longitude
+		update.PlaceName = &option.PlaceName
+		update.Address = &option.Address
+	default:
+		return nil, fmt.Errorf("시간 또는 장소 투표만 시그널에 반영할 수 있습니다")
+	}
+
+	if _, err := s.signalService.UpdateSignal(room.SignalID, userID, update); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	poll.AppliedOptionID = &option.ID
+	if poll.ClosedAt == nil {
+		poll.ClosedAt = &now
+	}
+	if err := s.chatRepo.UpdatePoll(poll); err != nil {
+		s.logger.Error("투표 반영 결과 저장 실패", err)
+	}
+
+	return s.publishPoll(room, poll.ID, userID)
+}
+
+// getPoll 채팅방의 투표 조회 (poll 메시지가 삭제되었으면 없는 것으로 처리)
+func (s *ChatService) getPoll(chatRoomID, pollID uint) (*models.ChatPoll, error) {
+	poll, err := s.chatRepo.GetPollByID(pollID)
+	if err != nil || poll.ChatRoomID != chatRoomID {
+		return nil, fmt.Errorf("투표를 찾을 수 없습니다")
+	}
+
+	if _, err := s.chatRepo.GetMessageByID(poll.MessageID); err != nil {
+		return nil, fmt.Errorf("투표를 찾을 수 없습니다")
+	}
+
+	return poll, nil
+}
+
+// publishPoll 최신 집계를 poll 이벤트로 전파하고 요청자 기준 정보 반환
+func (s *ChatService) publishPoll(room *models.ChatRoom, pollID, userID uint) (*models.ChatPollInfo, error) {
+	poll, err := s.chatRepo.GetPollByID(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("투표 조회 실패: %w", err)
+	}
+
+	envelope := models.NewChatEnvelope(models.ChatEventPoll, room.ID, poll.ToInfo())
+	envelope.ServerID = poll.MessageID
+	s.publish(room, envelope)
+
+	return pollInfoFor(poll, userID), nil
+}
+
+// pollInfoFor 집계에 요청자 본인의 선택을 추가
+func pollInfoFor(poll *models.ChatPoll, userID uint) *models.ChatPollInfo {
+	info := poll.ToInfo()
+	info.MyOptionIDs = []uint{}
+	for _, vote := range poll.Votes {
+		if vote.UserID == userID {
+			info.MyOptionIDs = append(info.MyOptionIDs, vote.OptionID)
+		}
+	}
+	return info
+}
+
+// ReportMessage 메시지 작성자를 신고하고 메시지를 근거로 남김 (삭제된 메시지도 신고 가능, 같은 메시지는 한 번만)
+func (s *ChatService) ReportMessage(userID, messageID uint, req *models.ReportMessageRequest) error {
+	message, err := s.chatRepo.GetMessageByIDUnscoped(messageID)
+	if err != nil || message.UserID == nil {
+		return fmt.Errorf("메시지를 찾을 수 없습니다")
+	}
+
+	room, err := s.chatRepo.GetChatRoomByID(message.ChatRoomID)
+	if err != nil {
+		return fmt.Errorf("메시지를 찾을 수 없습니다")
+	}
+
+	if err := s.checkParticipant(userID, room.ID); err != nil {
+		return err
+	}
+
+	if *message.UserID == userID {
+		return fmt.Errorf("자신의 메시지는 신고할 수 없습니다")
+	}
+
+	if reported, err := s.chatRepo.HasReportedMessage(userID, messageID); err == nil && reported {
+		return fmt.Errorf("이미 신고한 메시지입니다")
+	}
+
+	signalID := room.SignalID
+	report := &models.ReportUser{
+		ReporterID:     userID,
+		ReportedID:     *message.UserID,
+		SignalID:       &signalID,
+		ChatMessageID:  &message.ID,
+		MessageContent: message.Content,
+		Reason:         req.Reason,
+		Comment:        strings.TrimSpace(req.Comment),
+		Status:         "pending",
+	}
+
+	if err := s.chatRepo.CreateMessageReport(report); err != nil {
+		s.logger.Error("채팅 메시지 신고 실패", err)
+		return fmt.Errorf("메시지 신고에 실패했습니다")
+	}
+
+	s.logger.Info(fmt.Sprintf("채팅 메시지 신고: %d -> %d, 메시지 %d, 사유 %s", userID, *message.UserID, messageID, req.Reason))
+	return nil
+}
+
+// MuteParticipant 호스트가 참여자의 채팅을 지정한 시간 동안 제한 (이미 제한 중이면 다시 설정)
+func (s *ChatService) MuteParticipant(userID, chatRoomID, targetID uint, req *models.MuteParticipantRequest) (*models.ChatParticipantMute, error) {
+	room, signal, err := s.getHostedRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID == signal.CreatorID {
+		return nil, fmt.Errorf("호스트는 채팅을 제한할 수 없습니다")
+	}
+
+	target := approvedParticipant(signal, targetID)
+	if target == nil {
+		return nil, fmt.Errorf("채팅방 참여자가 아닙니다")
+	}
+
+	mute := &models.ChatParticipantMute{
+		ChatRoomID: chatRoomID,
+		UserID:     targetID,
+		MutedByID:  userID,
+		MutedUntil: time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute),
+	}
+
+	if err := s.chatRepo.SetParticipantMute(mute); err != nil {
+		s.logger.Error("채팅 제한 설정 실패", err)
+		return nil, fmt.Errorf("채팅 제한에 실패했습니다")
+	}
+
+	s.publish(room, systemEnvelope(chatRoomID, fmt.Sprintf("호스트가 %s님의 채팅을 %d분 동안 제한했습니다", target.User.Username, req.DurationMinutes)))
+	s.logger.Info(fmt.Sprintf("채팅 제한: 채팅방 %d, 사용자 %d, %d분", chatRoomID, targetID, req.DurationMinutes))
+
+	return mute, nil
+}
+
+// UnmuteParticipant 호스트가 참여자의 채팅 제한 해제
+func (s *ChatService) UnmuteParticipant(userID, chatRoomID, targetID uint) error {
+	room, signal, err := s.getHostedRoom(userID, chatRoomID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.chatRepo.GetActiveParticipantMute(chatRoomID, targetID); err != nil {
+		return fmt.Errorf("채팅이 제한된 참여자가 아닙니다")
+	}
+
+	if err := s.chatRepo.DeleteParticipantMute(chatRoomID, targetID); err != nil {
+		s.logger.Error("채팅 제한 해제 실패", err)
+		return fmt.Errorf("채팅 제한 해제에 실패했습니다")
+	}
+
+	if target := approvedParticipant(signal, targetID); target != nil {
+		s.publish(room, systemEnvelope(chatRoomID, fmt.Sprintf("%s님의 채팅 제한이 해제되었습니다", target.User.Username)))
+	}
+
+	return nil
+}
+
+// SetSlowMode 호스트가 슬로우 모드 간격 설정 (0이면 해제)
+func (s *ChatService) SetSlowMode(userID, chatRoomID uint, req *models.SlowModeRequest) error {
+	room, _, err := s.getHostedRoom(userID, chatRoomID)
+	if err != nil {
+		return err
+	}
+
+	if room.SlowModeSeconds == req.Seconds {
+		return nil
+	}
+
+	if err := s.chatRepo.SetSlowMode(chatRoomID, req.Seconds); err != nil {
+		s.logger.Error("슬로우 모드 설정 실패", err)
+		return fmt.Errorf("슬로우 모드 설정에 실패했습니다")
+	}
+
+	content := "슬로우 모드가 해제되었습니다"
+	if req.Seconds > 0 {
+		content = fmt.Sprintf("슬로우 모드가 켜졌습니다. 메시지는 %d초마다 보낼 수 있습니다", req.Seconds)
+	}
+	s.publish(room, systemEnvelope(chatRoomID, content))
+
+	return nil
+}
+
+// GetExtendStatus 채팅방 연장 투표 현황
+func (s *ChatService) GetExtendStatus(userID, chatRoomID uint) (*models.ChatExtendStatus, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+	return s.extendStatus(room, userID)
+}
+
+// VoteExtend 만료 전 투표 기간에 연장 찬성, 참여자 과반이 찬성하면 24시간 연장
+func (s *ChatService) VoteExtend(userID, chatRoomID uint) (*models.ChatExtendStatus, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExtendVoteOpen(room); err != nil {
+		return nil, err
+	}
+
+	round := room.ExtensionCount
+	if err := s.chatRepo.AddExtendVote(&models.ChatExtendVote{ChatRoomID: chatRoomID, Round: round, UserID: userID}); err != nil {
+		s.logger.Error("채팅방 연장 투표 실패", err)
+		return nil, fmt.Errorf("연장 투표에 실패했습니다")
+	}
+
+	status, err := s.extendStatus(room, userID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Votes < status.Required {
+		s.publish(room, systemEnvelope(chatRoomID, fmt.Sprintf("채팅방 연장 투표: %d/%d명 찬성 (%d명 필요)", status.Votes, status.Participants, status.Required)))
+		return status, nil
+	}
+
+	expiresAt := room.ExpiresAt.Add(models.ChatRoomExtension)
+	extended, err := s.chatRepo.ExtendChatRoom(chatRoomID, round, expiresAt)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("채팅방 %d 연장 실패", chatRoomID), err)
+		return nil, fmt.Errorf("채팅방 연장에 실패했습니다")
+	}
+	if !extended {
+		// 다른 요청이 먼저 연장함
+		return status, nil
+	}
+
+	room.ExpiresAt = &expiresAt
+	room.ExtensionCount++
+	s.publish(room, systemEnvelope(chatRoomID, fmt.Sprintf("참여자 과반이 찬성해 채팅방이 %s까지 연장되었습니다", expiresAt.Format("1월 2일 15:04"))))
+	s.logger.Info(fmt.Sprintf("채팅방 %d 연장: %s까지 (%d회)", chatRoomID, expiresAt.Format(time.RFC3339), room.ExtensionCount))
+
+	status, err = s.extendStatus(room, userID)
+	if err != nil {
+		return nil, err
+	}
+	status.Extended = true
+	return status, nil
+}
+
+// WithdrawExtendVote 연장 찬성 취소
+func (s *ChatService) WithdrawExtendVote(userID, chatRoomID uint) (*models.ChatExtendStatus, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.DeleteExtendVote(chatRoomID, room.ExtensionCount, userID); err != nil {
+		s.logger.Error("채팅방 연장 투표 취소 실패", err)
+		return nil, fmt.Errorf("연장 투표 취소에 실패했습니다")
+	}
+
+	return s.extendStatus(room, userID)
+}
+
+// ExportMessages 채팅방이 파기되기 전에 내가 보낸 메시지를 내려받기
+func (s *ChatService) ExportMessages(userID, chatRoomID uint) (*models.ChatTranscript, error) {
+	room, err := s.chatRepo.GetChatRoomByID(chatRoomID)
+	if err != nil {
+		return nil, fmt.Errorf("채팅방을 찾을 수 없습니다")
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.chatRepo.GetUserMessages(chatRoomID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("메시지 조회 실패: %w", err)
+	}
+
+	transcript := &models.ChatTranscript{
+		ChatRoomID:  room.ID,
+		SignalID:    room.SignalID,
+		Name:        room.Name,
+		GeneratedAt: time.Now(),
+		Messages:    make([]models.ChatTranscriptMessage, 0, len(messages)),
+	}
+	for i := range messages {
+		transcript.Messages = append(transcript.Messages, messages[i].ToTranscript())
+	}
+
+	return transcript, nil
+}
+
+// extendStatus 현재 연장 회차의 투표 현황
+func (s *ChatService) extendStatus(room *models.ChatRoom, userID uint) (*models.ChatExtendStatus, error) {
+	participants, err := s.chatRepo.CountParticipants(room.ID)
+	if err != nil {
+		return nil, fmt.Errorf("참여자 수 조회 실패: %w", err)
+	}
+	votes, err := s.chatRepo.CountExtendVotes(room.ID, room.ExtensionCount)
+	if err != nil {
+		return nil, fmt.Errorf("연장 투표 조회 실패: %w", err)
+	}
+	voted, err := s.chatRepo.HasExtendVote(room.ID, room.ExtensionCount, userID)
+	if err != nil {
+		return nil, fmt.Errorf("연장 투표 조회 실패: %w", err)
+	}
+
+	status := &models.ChatExtendStatus{
+		ExpiresAt:      room.ExpiresAt,
+		ExtensionCount: room.ExtensionCount,
+		MaxExtensions:  models.ChatRoomMaxExtensions,
+		Votes:          int(votes),
+		Required:       int(participants)/2 + 1,
+		Participants:   int(participants),
+		Voted:          voted,
+	}
+	if room.ExpiresAt != nil {
+		opensAt := room.ExpiresAt.Add(-models.ChatExtendVoteWindow)
+		status.VotingOpensAt = &opensAt
+	}
+	return status, nil
+}
+
+// checkExtendVoteOpen 연장 가능 횟수와 투표 기간 확인
+func checkExtendVoteOpen(room *models.ChatRoom) error {
+	if room.ExpiresAt == nil {
+		return fmt.Errorf("만료 시간이 없는 채팅방입니다")
+	}
+	if room.ExtensionCount >= models.ChatRoomMaxExtensions {
+		return fmt.Errorf("채팅방은 최대 %d번까지 연장할 수 있습니다", models.ChatRoomMaxExtensions)
+	}
+	if opensAt := room.ExpiresAt.Add(-models.ChatExtendVoteWindow); time.Now().Before(opensAt) {
+		return fmt.Errorf("연장 투표는 만료 %d시간 전부터 할 수 있습니다", int(models.ChatExtendVoteWindow.Hours()))
+	}
+	return nil
+}
+
+// GetOrCreateSignalChatRoom 시그널 채팅방 조회, 없으면 생성 (만료: 시그널 예정 시간 24시간 후)
+func (s *ChatService) GetOrCreateSignalChatRoom(signalID uint) (*models.ChatRoom, error) {
+	if room, err := s.chatRepo.GetChatRoomBySignalID(signalID); err == nil {
+		return room, nil
+	}
+
+	signal, err := s.signalRepo.GetByID(signalID)
+	if err != nil {
+		return nil, fmt.Errorf("시그널을 찾을 수 없습니다")
+	}
+
+	expiresAt := signal.ScheduledAt.Add(models.ChatRoomLifetime)
+	room := &models.ChatRoom{
+		SignalID:  signalID,
+		Name:      signal.Title,
+		Status:    models.ChatRoomActive,
+		ExpiresAt: &expiresAt,
+	}
+
+	if err := s.chatRepo.CreateChatRoom(room); err != nil {
+		// 다른 요청이 먼저 생성했을 수 있음 (signal_id 유니크)
+		if existing, getErr := s.chatRepo.GetChatRoomBySignalID(signalID); getErr == nil {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("채팅방 생성 실패: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("시그널 %d 채팅방 생성: %d", signalID, room.ID))
+	return room, nil
+}
+
+// persistMessage client_msg_id 중복을 확인한 뒤 순번을 발급받아 저장하고 발신자 프로필과 함께 다시 조회
+func (s *ChatService) persistMessage(userID, chatRoomID uint, req *models.SendMessageRequest, attachment *models.ChatAttachment) (*models.ChatMessage, bool, error) {
+	if req.ClientMsgID != "" {
+		if existing, err := s.chatRepo.GetMessageByClientID(chatRoomID, userID, req.ClientMsgID); err == nil {
+			return existing, false, nil
+		}
+	}
+
+	message := &models.ChatMessage{
+		ChatRoomID:  chatRoomID,
+		UserID:      &userID,
+		ClientMsgID: req.ClientMsgID,
+		Type:        req.Type,
+		Content:     req.Content,
+		ReplyToID:   req.ReplyToID,
+	}
+	if req.Type == models.MessageLocation {
+		message.Latitude = &req.Location.Latitude
+		message.Longitude = &req.Location.Longitude
+		message.PlaceName = req.Location.PlaceName
+		message.Address = req.Location.Address
+	}
+	if req.Type == models.MessagePoll {
+		message.Poll = newChatPoll(userID, chatRoomID, req.Poll)
+	}
+	if attachment != nil {
+		message.AttachmentID = &attachment.ID
+		if req.Type == models.MessageImage {
+			message.ImageURL = attachment.DownloadPath()
+		}
+	}
+
+	if err := s.chatRepo.SendMessage(message); err != nil {
+		// 동시에 재전송된 경우 유니크 인덱스에 걸리므로 먼저 저장된 메시지를 사용
+		if req.ClientMsgID != "" {
+			if existing, getErr := s.chatRepo.GetMessageByClientID(chatRoomID, userID, req.ClientMsgID); getErr == nil {
+				return existing, false, nil
+			}
+		}
+		s.logger.Error("채팅 메시지 저장 실패", err)
+		return nil, false, fmt.Errorf("메시지 전송에 실패했습니다")
+	}
+
+	saved, err := s.chatRepo.GetMessageByID(message.ID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("저장된 메시지 %d 조회 실패: %v", message.ID, err))
+		return message, true, nil
+	}
+	return saved, true, nil
+}
+
+// getSendableAttachment 본인이 이 채팅방에 올린, 처리 완료되고 아직 전송되지 않은 첨부인지 확인
+func (s *ChatService) getSendableAttachment(userID, chatRoomID uint, req *models.SendMessageRequest) (*models.ChatAttachment, error) {
+	attachment, err := s.chatRepo.GetAttachmentByID(*req.AttachmentID)
+	if err != nil || attachment.ChatRoomID != chatRoomID || attachment.UploaderID != userID {
+		return nil, fmt.Errorf("첨부파일을 찾을 수 없습니다")
+	}
+	if attachment.Status != models.AttachmentReady {
+		return nil, fmt.Errorf("업로드가 완료되지 않은 첨부파일입니다")
+	}
+	if models.AttachmentContentTypes[attachment.ContentType] != req.Type {
+		return nil, fmt.Errorf("첨부파일 형식이 메시지 유형과 맞지 않습니다")
+	}
+
+	// 같은 client_msg_id의 재전송은 persistMessage에서 기존 메시지로 처리
+	if used, err := s.chatRepo.GetMessageByAttachmentID(attachment.ID); err == nil {
+		if req.ClientMsgID == "" || used.ClientMsgID != req.ClientMsgID || used.UserID == nil || *used.UserID != userID {
+			return nil, fmt.Errorf("이미 전송된 첨부파일입니다")
+		}
+	}
+
+	return attachment, nil
+}
+
+// getWritableRoom 활성 채팅방이고 요청자가 참여자인지 확인
+func (s *ChatService) getWritableRoom(userID, chatRoomID uint) (*models.ChatRoom, error) {
+	room, err := s.chatRepo.GetChatRoomByID(chatRoomID)
+	if err != nil {
+		return nil, fmt.Errorf("채팅방을 찾을 수 없습니다")
+	}
+
+	if room.Status != models.ChatRoomActive || (room.ExpiresAt != nil && !time.Now().Before(*room.ExpiresAt)) {
+		return nil, fmt.Errorf("종료된 채팅방입니다")
+	}
+
+	if err := s.checkParticipant(userID, chatRoomID); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// getHostedRoom 활성 채팅방이고 요청자가 시그널 호스트인지 확인
+func (s *ChatService) getHostedRoom(userID, chatRoomID uint) (*models.ChatRoom, *models.Signal, error) {
+	room, err := s.getWritableRoom(userID, chatRoomID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signal, err := s.signalRepo.GetByID(room.SignalID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("시그널을 찾을 수 없습니다")
+	}
+	if signal.CreatorID != userID {
+		return nil, nil, fmt.Errorf("호스트만 이용할 수 있습니다")
+	}
+
+	return room, signal, nil
+}
+
+// approvedParticipant 시그널의 승인된 참여자 (없으면 nil)
+func approvedParticipant(signal *models.Signal, userID uint) *models.SignalParticipant {
+	for i := range signal.Participants {
+		if signal.Participants[i].UserID == userID && signal.Participants[i].Status == models.ParticipantApproved {
+			return &signal.Participants[i]
+		}
+	}
+	return nil
+}
+
+// checkMuted 호스트가 채팅을 제한한 참여자인지 확인
+func (s *ChatService) checkMuted(userID, chatRoomID uint) error {
+	mute, err := s.chatRepo.GetActiveParticipantMute(chatRoomID, userID)
+	if err != nil {
+		return nil
+	}
+
+	minutes := int(math.Ceil(time.Until(mute.MutedUntil).Minutes()))
+	return &ChatModerationError{Code: moderationMuted, Message: fmt.Sprintf("호스트가 채팅을 제한했습니다. %d분 후에 보낼 수 있습니다", minutes)}
+}
+
+// filterMessage 메시지 내용과 투표 문구에 금칙어/링크 필터 적용
+func (s *ChatService) filterMessage(req *models.SendMessageRequest) error {
+	var err error
+	if req.Content, err = s.moderator.Filter(req.Content); err != nil {
+		return err
+	}
+
+	if req.Poll != nil {
+		if req.Poll.Question, err = s.moderator.Filter(req.Poll.Question); err != nil {
+			return err
+		}
+		for i := range req.Poll.Options {
+			if req.Poll.Options[i].Text, err = s.moderator.Filter(req.Poll.Options[i].Text); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// throttle 사용자별 전송 속도 제한과 채팅방 슬로우 모드 (호스트는 슬로우 모드 제외)
+func (s *ChatService) throttle(userID uint, room *models.ChatRoom) error {
+	roomKey := chatRoomKey(room.SignalID)
+	if err := s.moderator.CheckRate(roomKey, userID); err != nil {
+		return err
+	}
+
+	if room.SlowModeSeconds > 0 && !s.isHost(userID, room) {
+		return s.moderator.CheckSlowMode(roomKey, userID, room.SlowModeSeconds)
+	}
+	return nil
+}
+
+// isHost 시그널 생성자인지 확인
+func (s *ChatService) isHost(userID uint, room *models.ChatRoom) bool {
+	signal, err := s.signalRepo.GetByID(room.SignalID)
+	if err != nil {
+		return false
+	}
+	return signal.CreatorID == userID
+}
+
+// systemEnvelope 저장하지 않는 시스템 알림 (발신자 없음)
+func systemEnvelope(chatRoomID uint, content string) *models.ChatEnvelope {
+	return models.NewChatEnvelope(models.ChatEventMessage, chatRoomID, &models.ChatMessagePayload{
+		Type:    models.MessageSystem,
+		Content: content,
+	})
+}
+
+// publish 채팅방의 모든 인스턴스로 봉투 전파 (실패해도 저장된 내용은 히스토리로 조회 가능)
+func (s *ChatService) publish(room *models.ChatRoom, envelope *models.ChatEnvelope) {
+	event := &chatClusterEvent{Kind: chatEventMessage, Envelope: envelope}
+	if err := publishChatEvent(s.redisClient, chatRoomKey(room.SignalID), event); err != nil {
+		s.logger.Error(fmt.Sprintf("채팅방 %d %s 이벤트 발행 실패", room.ID, envelope.Event), err)
+	}
+}
+
+func (s *ChatService) checkParticipant(userID, chatRoomID uint) error {
+	ok, err := s.chatRepo.IsParticipant(chatRoomID, userID)
+	if err != nil {
+		return fmt.Errorf("채팅방 참여 여부 확인 실패: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("채팅방 참여자만 이용할 수 있습니다")
+	}
+	return nil
+}
+
+// validateChatMessage REST와 WebSocket에서 공통으로 사용하는 메시지 검증
+func validateChatMessage(req *models.SendMessageRequest) error {
+	req.Content = strings.TrimSpace(req.Content)
+
+	switch req.Type {
+	case models.MessageText:
+		if req.Content == "" {
+			return fmt.Errorf("메시지 내용을 입력해주세요")
+		}
+	case models.MessageLocation:
+		if req.Location == nil || !utils.IsValidCoordinate(req.Location.Latitude, req.Location.Longitude) {
+			return fmt.Errorf("만남 장소 좌표가 올바르지 않습니다")
+		}
+		req.Location.PlaceName = strings.TrimSpace(req.Location.PlaceName)
+		req.Location.Address = strings.TrimSpace(req.Location.Address)
+		if utf8.RuneCountInString(req.Location.PlaceName) > 100 || utf8.RuneCountInString(req.Location.Address) > 200 {
+			return fmt.Errorf("장소 이름은 100자, 주소는 200자까지 입력할 수 있습니다")
+		}
+	case models.MessagePoll:
+		if err := validatePoll(req.Poll); err != nil {
+			return err
+		}
+	case models.MessageImage, models.MessageFile:
+		// 내용은 선택 사항 (캡션)
+		if req.AttachmentID == nil {
+			return fmt.Errorf("첨부파일이 필요합니다")
+		}
+	default:
+		return fmt.Errorf("지원하지 않는 메시지 유형입니다")
+	}
+
+	if utf8.RuneCountInString(req.Content) > chatMaxContentLength {
+		return fmt.Errorf("메시지는 최대 %d자까지 입력할 수 있습니다", chatMaxContentLength)
+	}
+
+	if len(req.ClientMsgID) > 64 {
+		return fmt.Errorf("client_msg_id가 너무 깁니다")
+	}
+
+	return nil
+}
+
+// validatePoll 투표 질문과 선택지 검증 (시간/장소 투표는 선택지마다 값 필요)
+func validatePoll(req *models.CreatePollRequest) error {
+	if req == nil {
+		return fmt.Errorf("투표 내용이 필요합니다")
+	}
+
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" || utf8.RuneCountInString(req.Question) > 200 {
+		return fmt.Errorf("투표 질문은 1자 이상 200자 이하로 입력해주세요")
+	}
+
+	if req.Kind == "" {
+		req.Kind = models.PollGeneral
+	}
+	if req.Kind != models.PollGeneral && req.Kind != models.PollTime && req.Kind != models.PollPlace {
+		return fmt.Errorf("지원하지 않는 투표 유형입니다")
+	}
+
+	if len(req.Options) < models.ChatPollMinOptions || len(req.Options) > models.ChatPollMaxOptions {
+		return fmt.Errorf("선택지는 %d개 이상 %d개 이하로 입력해주세요", models.ChatPollMinOptions, models.ChatPollMaxOptions)
+	}
+
+	if req.Deadline != nil {
+		if !req.Deadline.After(time.Now()) {
+			return fmt.Errorf("마감 시간은 현재 이후여야 합니다")
+		}
+		if req.Deadline.After(time.Now().Add(7 * 24 * time.Hour)) {
+			return fmt.Errorf("마감 시간은 1주일 이내로 설정해주세요")
+		}
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	for i := range req.Options {
+		option := &req.Options[i]
+		option.Text = strings.TrimSpace(option.Text)
+
+		switch req.Kind {
+		case models.PollTime:
+			if option.ScheduledAt == nil {
+				return fmt.Errorf("시간 투표의 선택지에는 시간이 필요합니다")
+			}
+			if option.Text == "" {
+				option.Text = option.ScheduledAt.Format("01/02 15:04")
+			}
+		case models.PollPlace:
+			if option.Location == nil || !utils.IsValidCoordinate(option.Location.Latitude, option.Location.Longitude) {
+				return fmt.Errorf("장소 투표의 선택지에는 올바른 좌표가 필요합니다")
+			}
+			option.Location.PlaceName = strings.TrimSpace(option.Location.PlaceName)
+			option.Location.Address = strings.TrimSpace(option.Location.Address)
+			if utf8.RuneCountInString(option.Location.PlaceName) > 100 || utf8.RuneCountInString(option.Location.Address) > 200 {
+				return fmt.Errorf("장소 이름은 100자, 주소는 200자까지 입력할 수 있습니다")
+			}
+			if option.Text == "" {
+				option.Text = option.Location.PlaceName
+			}
+		}
+
+		if option.Text == "" || utf8.RuneCountInString(option.Text) > 100 {
+			return fmt.Errorf("선택지는 1자 이상 100자 이하로 입력해주세요")
+		}
+		if seen[option.Text] {
+			return fmt.Errorf("중복된 선택지가 있습니다: %s", option.Text)
+		}
+		seen[option.Text] = true
+	}
+
+	return nil
+}
+
+// newChatPoll 검증된 요청으로 poll 메시지와 함께 저장할 투표 생성
+func newChatPoll(userID, chatRoomID uint, req *models.CreatePollRequest) *models.ChatPoll {
+	poll := &models.ChatPoll{
+		ChatRoomID:     chatRoomID,
+		CreatorID:      userID,
+		Question:       req.Question,
+		Kind:           req.Kind,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		Deadline:       req.Deadline,
+		Options:        make([]models.ChatPollOption, 0, len(req.Options)),
+	}
+
+	for i, option := range req.Options {
+		pollOption := models.ChatPollOption{
+			Position: i,
+			Text:     option.Text,
+		}
+		if req.Kind == models.PollTime {
+			pollOption.ScheduledAt = option.ScheduledAt
+		}
+		if req.Kind == models.PollPlace {
+			pollOption.Latitude = &option.Location.Latitude
+			pollOption.Longitude = &option.Location.Longitude
+			pollOption.PlaceName = option.Location.PlaceName
+			pollOption.Address = option.Location.Address
+		}
+		poll.Options = append(poll.Options, pollOption)
+	}
+
+	return poll
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"signal-module/pkg/config"
+	"signal-module/pkg/models"
+	"signal-module/pkg/redis"
+
+	"github.com/gorilla/websocket"
+)
+
+// localRedis 로컬 Redis (REDIS_HOST/REDIS_PORT, 연결할 수 없으면 테스트 건너뜀)
+func localRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	cfg := &config.RedisConfig{Host: "localhost", Port: "6379"}
+	if host := os.Getenv("REDIS_HOST"); host != "" {
+		cfg.Host = host
+	}
+	if port := os.Getenv("REDIS_PORT"); port != "" {
+		cfg.Port = port
+	}
+
+	redisClient, err := redis.New(cfg)
+	if err != nil {
+		t.Skipf("Redis를 사용할 수 없어 건너뜁니다: %v", err)
+	}
+	t.Cleanup(func() { redisClient.Close() })
+	return redisClient
+}
+
+// readChatMessage 다음 message 이벤트의 페이로드
+func readChatMessage(t *testing.T, conn *websocket.Conn) (*models.ChatEnvelope, *models.ChatMessagePayload) {
+	t.Helper()
+
+	envelope := readChatEnvelope(t, conn, "")
+	var payload models.ChatMessagePayload
+	if envelope.Event == models.ChatEventMessage {
+		if err := envelope.DecodePayload(&payload); err != nil {
+			t.Fatalf("페이로드 읽기 실패: %v", err)
+		}
+	}
+	return envelope, &payload
+}
+
+// 인스턴스 두 개가 같은 Redis로 채팅방 이벤트와 presence를 공유하는지 확인
+func TestChatWebSocketMultiInstance(t *testing.T) {
+	redisClient := localRedis(t)
+
+	// 다른 테스트 실행과 겹치지 않는 채팅방과 사용자
+	base := uint(time.Now().UnixNano()%1_000_000) + 1_000_000
+	roomID := chatRoomKey(base)
+	alice, bob := base+1, base+2
+	t.Cleanup(func() {
+		ctx := context.Background()
+		redisClient.Delete(ctx, chatPresenceKey(roomID))
+		redisClient.DeleteStream(ctx, chatRoomChannel(roomID))
+	})
+
+	expiresAt := time.Now().Add(time.Hour)
+	first := newTestChatWebSocketService(t, redisClient, expiresAt)
+	second := newTestChatWebSocketService(t, redisClient, expiresAt)
+	firstServer := newChatTestServer(first)
+	defer firstServer.Close()
+	secondServer := newChatTestServer(second)
+	defer secondServer.Close()
+	defer shutdownChatService(t, second)
+	defer shutdownChatService(t, first)
+
+	aliceConn, _, err := dialChat(firstServer, roomID, alice)
+	if err != nil {
+		t.Fatalf("첫 번째 인스턴스 연결 실패: %v", err)
+	}
+	defer aliceConn.Close()
+	readChatEnvelope(t, aliceConn, models.ChatEventSession)
+
+	// 채팅방은 구독이 확인된 뒤에 입장을 받으므로 자신의 입장 메시지도 받음
+	if _, payload := readChatMessage(t, aliceConn); payload.Type != models.MessageJoin || payload.Content != fmt.Sprintf("user%d님이 입장했습니다", alice) {
+		t.Fatalf("입장 메시지 대신: %+v", payload)
+	}
+
+	// 다른 인스턴스에 접속한 사용자의 입장이 전달됨
+	bobConn, _, err := dialChat(secondServer, roomID, bob)
+	if err != nil {
+		t.Fatalf("두 번째 인스턴스 연결 실패: %v", err)
+	}
+	defer bobConn.Close()
+	readChatEnvelope(t, bobConn, models.ChatEventSession)
+
+	if _, payload := readChatMessage(t, aliceConn); payload.Type != models.MessageJoin || payload.Content != fmt.Sprintf("user%d님이 입장했습니다", bob) {
+		t.Fatalf("다른 인스턴스 입장 메시지 대신: %+v", payload)
+	}
+
+	// presence는 어느 인스턴스에서 조회해도 클러스터 전체 기준
+	for _, cws := range []*ChatWebSocketService{first, second} {
+		participants := cws.GetRoomParticipants(roomID)
+		sort.Slice(participants, func(i, j int) bool { return participants[i] < participants[j] })
+		if len(participants) != 2 || participants[0] != alice || participants[1] != bob {
+			t.Fatalf("참여자: %v", participants)
+		}
+	}
+
+	// 이미 접속한 사용자가 다른 인스턴스로 한 번 더 접속하고 끊어도 입장/퇴장 메시지 없음
+	aliceSecondConn, _, err := dialChat(secondServer, roomID, alice)
+	if err != nil {
+		t.Fatalf("두 번째 기기 연결 실패: %v", err)
+	}
+	readChatEnvelope(t, aliceSecondConn, models.ChatEventSession)
+	aliceSecondConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	aliceSecondConn.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(second.getLocalRoomParticipants(roomID)) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("두 번째 기기 연결이 정리되지 않았습니다")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 저장하지 않는 이벤트도 인스턴스 사이로 전달됨 (입장/퇴장 메시지보다 먼저 오면 안 됨)
+	typing := models.NewChatEnvelope(models.ChatEventTyping, 0, &models.ChatTypingPayload{IsTyping: true})
+	if err := bobConn.WriteJSON(typing); err != nil {
+		t.Fatalf("입력 중 표시 전송 실패: %v", err)
+	}
+	if envelope, payload := readChatMessage(t, aliceConn); envelope.Event != models.ChatEventTyping || envelope.Sender == nil || envelope.Sender.ID != bob {
+		t.Fatalf("입력 중 표시 대신: %s %+v", envelope.Event, payload)
+	}
+
+	// 다른 인스턴스 사용자의 퇴장이 전달되고 presence에서 제거됨
+	bobConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	bobConn.Close()
+	if _, payload := readChatMessage(t, aliceConn); payload.Type != models.MessageLeave || payload.Content != fmt.Sprintf("user%d님이 나갔습니다", bob) {
+		t.Fatalf("퇴장 메시지 대신: %+v", payload)
+	}
+	if participants := first.GetRoomParticipants(roomID); len(participants) != 1 || participants[0] != alice {
+		t.Fatalf("퇴장 후 참여자: %v", participants)
+	}
+}
+
+// 채팅방이 첫 입장을 받은 직후 발행된 이벤트도 전달되는지 확인 (구독이 확인된 뒤에 입장을 받음)
+func TestChatRoomSubscribedBeforeFirstJoin(t *testing.T) {
+	redisClient := localRedis(t)
+	cws := newTestChatWebSocketService(t, redisClient, time.Now().Add(time.Hour))
+	defer shutdownChatService(t, cws)
+
+	base := uint(time.Now().UnixNano()%1_000_000) + 2_000_000
+	for i := uint(0); i < 20; i++ {
+		roomID := chatRoomKey(base + i)
+		t.Cleanup(func() {
+			ctx := context.Background()
+			redisClient.Delete(ctx, chatPresenceKey(roomID))
+			redisClient.DeleteStream(ctx, chatRoomChannel(roomID))
+		})
+
+		client := &ChatClient{
+			UserID:     1,
+			Username:   "user1",
+			Send:       make(chan *models.ChatEnvelope, chatSendBufferSize),
+			resumeFrom: -1,
+		}
+		room := cws.GetOrCreateChatRoom(roomID)
+		if room == nil || !room.register(client) {
+			t.Fatalf("채팅방 %s 입장 실패", roomID)
+		}
+		client.Room = room
+
+		typing := models.NewChatEnvelope(models.ChatEventTyping, room.ChatRoomID, &models.ChatTypingPayload{IsTyping: true})
+		if err := publishChatEvent(redisClient, roomID, &chatClusterEvent{Kind: chatEventMessage, Envelope: typing}); err != nil {
+			t.Fatalf("발행 실패: %v", err)
+		}
+
+		timeout := time.After(5 * time.Second)
+		for received := false; !received; {
+			select {
+			case envelope := <-client.Send:
+				received = envelope.Event == models.ChatEventTyping
+			case <-timeout:
+				t.Fatalf("채팅방 %s 입장 직후 발행한 이벤트를 받지 못했습니다", roomID)
+			}
+		}
+		room.unregister(client)
+	}
+}
@@ -1,186 +1,435 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"sync"
 	"time"
 
+	"signal-module/pkg/logger"
 	"signal-module/pkg/models"
+	"signal-module/pkg/redis"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"gorm.io/gorm"
 )
 
-var chatUpgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins in development
-	},
+const (
+	chatWriteWait      = 10 * time.Second
+	chatPongWait       = 60 * time.Second
+	chatPingPeriod     = 54 * time.Second
+	chatCloseGrace     = time.Second // close 프레임 전송 후 상대방 응답 대기 시간
+	chatMaxMessageSize = 4096        // 봉투 메타데이터 + 최대 1000자 메시지
+	chatSendBufferSize = 256
+
+	// 연결당 수신 한도: 이 간격 동안 더 많은 프레임을 보내면 연결 종료 (사용자별 전송 제한과 별개)
+	chatInboundWindow    = time.Second
+	chatMaxInboundFrames = 20
+
+	// 재연결한 클라이언트에게 다시 보낼 수 있는 채팅방 이벤트 범위 (재전송이 전송 버퍼에 모두 들어가도록 더 작게)
+	chatReplayBufferSize = 200
+	chatReplayBufferTTL  = 10 * time.Minute
+
+	// 채팅방 채널 구독 확인 대기 시간 (지나면 확인 없이 입장을 받음)
+	chatSubscribeTimeout = 5 * time.Second
+)
+
+// chatClusterEvent 인스턴스 간 Redis pub/sub으로 전달되는 채팅 이벤트
+type chatClusterEvent struct {
+	Kind     string               `json:"kind"` // message, destroy
+	Envelope *models.ChatEnvelope `json:"envelope,omitempty"`
+}
+
+const (
+	chatEventMessage = "message"
+	chatEventDestroy = "destroy"
+)
+
+// Redis 키/채널 이름
+func chatRoomKey(signalID uint) string        { return fmt.Sprintf("signal_%d", signalID) }
+func chatRoomChannel(roomID string) string    { return "chat:room:" + roomID }
+func chatPresenceKey(roomID string) string    { return "chat:presence:" + roomID }
+func chatDestroyLockKey(roomID string) string { return "chat:destroy:" + roomID }
+
+// publishChatEvent 채팅방 채널에 스트림 순번을 붙여 발행 (REST 전송과 WebSocket 허브가 함께 사용)
+func publishChatEvent(redisClient *redis.Client, roomID string, event *chatClusterEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("채팅 이벤트 직렬화 실패: %w", err)
+	}
+
+	_, err = redisClient.PublishSequenced(context.Background(), chatRoomChannel(roomID), data, chatReplayBufferSize, chatReplayBufferTTL)
+	return err
+}
+
+// decodeChatEvent 순번이 붙은 채팅방 채널 메시지를 이벤트로 변환 (봉투에 스트림 순번 기록)
+func decodeChatEvent(message *redis.SequencedMessage) (*chatClusterEvent, error) {
+	var event chatClusterEvent
+	if err := json.Unmarshal(message.Event, &event); err != nil {
+		return nil, err
+	}
+	if event.Envelope != nil {
+		event.Envelope.StreamSeq = message.Seq
+	}
+	return &event, nil
 }
 
-type ChatMessage struct {
-	ID        uint      `json:"id"`
-	RoomID    string    `json:"room_id"`
-	UserID    uint      `json:"user_id"`
-	Username  string    `json:"username"`
-	Content   string    `json:"content"`
-	Type      string    `json:"type"` // text, image, location, system
-	Timestamp time.Time `json:"timestamp"`
+// chatInbound 클라이언트가 보낸 메시지 봉투
+type chatInbound struct {
+	client   *ChatClient
+	envelope *models.ChatEnvelope
 }
 
+// chatReply 메시지를 보낸 클라이언트에게만 전달할 봉투 (error 이벤트)
+type chatReply struct {
+	client   *ChatClient
+	envelope *models.ChatEnvelope
+}
+
+// chatResume 새로 입장한 클라이언트의 재전송 조회 결과
+type chatResume struct {
+	client   *ChatClient
+	messages []*models.ChatEnvelope
+	latest   int64
+	session  *models.ChatSessionPayload
+}
+
+// chatPresenceUpdate 클러스터 presence 갱신 요청 (입장 1, 퇴장 -1)
+type chatPresenceUpdate struct {
+	userID   uint
+	username string
+	delta    int64
+}
+
+// ChatClient 하나의 WebSocket 연결
+//
+// Send 채널은 채팅방 goroutine만 닫을 수 있으며, 닫기 전에 closeCode/closeText를
+// 설정해 writePump가 close 프레임으로 사유를 전달하도록 한다.
 type ChatClient struct {
 	UserID   uint
 	Username string
 	Conn     *websocket.Conn
-	Send     chan *ChatMessage
+	Send     chan *models.ChatEnvelope
 	Room     *ChatRoom
+
+	closeCode int
+	closeText string
+
+	// 수신 프레임 수 (readPump 전용)
+	inboundWindowStart time.Time
+	inboundFrames      int
+
+	// 실시간 위치 공유 상태 (readPump 전용)
+	sharingLocation bool
+	lastLocationAt  time.Time
+
+	// 재연결 시 이어받을 스트림 순번 (-1이면 새 연결)과 마지막으로 보낸 순번 (채팅방 goroutine 전용)
+	resumeFrom int64
+	streamSeq  int64
+
+	// 재전송 조회가 끝나기 전에 도착한 이벤트 (채팅방 goroutine 전용)
+	resuming bool
+	pending  []*models.ChatEnvelope
 }
 
+// 실시간 위치 갱신 최소 간격 (더 자주 보낸 갱신은 버림)
+const chatLiveLocationInterval = 3 * time.Second
+
+// ChatRoom 채팅방 허브
+//
+// join/leave/deliver/replies/resumed/extended 채널은 run goroutine 하나만 읽고 절대 닫지 않는다.
+// 보내는 쪽은 항상 done과 함께 select 하므로 채팅방이 종료된 뒤에도 막히지 않는다.
+// run goroutine은 DB와 Redis에 접근하지 않는다. 메시지 저장은 보낸 연결의 readPump가,
+// presence 갱신과 입장/퇴장 메시지는 runPresence가, 재전송 조회와 만료 확인은 별도 goroutine이 맡고
+// 결과만 채널로 돌려받는다.
 type ChatRoom struct {
-	ID           string                 `json:"id"`
-	SignalID     uint                   `json:"signal_id"`
-	Participants map[uint]*ChatClient   `json:"-"`
-	Messages     chan *ChatMessage      `json:"-"`
-	Join         chan *ChatClient       `json:"-"`
-	Leave        chan *ChatClient       `json:"-"`
-	Created      time.Time              `json:"created"`
-	ExpiresAt    time.Time              `json:"expires_at"`
-	mutex        sync.RWMutex
+	ID         string    `json:"id"`
+	SignalID   uint      `json:"signal_id"`
+	ChatRoomID uint      `json:"chat_room_id"` // chat_rooms.id
+	Created    time.Time `json:"created"`
+	ExpiresAt  time.Time `json:"expires_at"` // mutex로 보호
+
+	clients   map[*ChatClient]struct{} // run goroutine 전용
+	streamSeq int64                    // 구독으로 받은 마지막 스트림 순번 (구독 확인 직후 초기화, run goroutine 전용)
+	join      chan *ChatClient
+	leave     chan *ChatClient
+	deliver   chan *models.ChatEnvelope // Redis에서 수신한 메시지 (로컬 브로드캐스트용)
+	replies   chan chatReply            // 보낸 클라이언트에게만 전달할 처리 결과
+	resumed   chan *chatResume          // 재전송 조회 결과
+	extended  chan time.Time            // DB에서 확인한 늦춰진 만료 시간
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	subscribed chan struct{} // Redis 채널 구독이 확인되면 닫힘 (그 전에는 입장을 받지 않음)
+	done       chan struct{}
+	closeOnce  sync.Once
+	closeCode  int
+	closeText  string
+
+	// runPresence가 순서대로 처리할 presence 갱신
+	presenceMutex   sync.Mutex
+	presenceUpdates []chatPresenceUpdate
+	presenceWake    chan struct{}
+
+	// 이 인스턴스에 접속한 사용자별 연결 수 (조회용 스냅샷)
+	mutex         sync.RWMutex
+	localPresence map[uint]int
 }
 
 type ChatWebSocketService struct {
-	db        *gorm.DB
+	db          *gorm.DB
+	redisClient *redis.Client
+	chatService ChatServiceInterface
+	presence    PresenceServiceInterface
+	instanceID  string
+	upgrader    websocket.Upgrader
+	logger      *logger.Logger
+
+	// 채팅방 입장 권한 확인 (기본: 시그널 생성자 또는 승인된 참여자)
+	canJoin func(userID uint, roomID string) bool
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closed    bool
 	rooms     map[string]*ChatRoom
-	roomMutex sync.RWMutex
-	logger    *log.Logger
+	roomMutex sync.Mutex
 }
 
-func NewChatWebSocketService(db *gorm.DB, logger *log.Logger) *ChatWebSocketService {
-	return &ChatWebSocketService{
-		db:     db,
-		rooms:  make(map[string]*ChatRoom),
+func NewChatWebSocketService(db *gorm.DB, redisClient *redis.Client, chatService ChatServiceInterface, presence PresenceServiceInterface, allowedOrigins []string, logger *logger.Logger) *ChatWebSocketService {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cws := &ChatWebSocketService{
+		db:          db,
+		redisClient: redisClient,
+		chatService: chatService,
+		presence:    presence,
+		instanceID:  fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     newOriginChecker(allowedOrigins),
+		},
 		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+		rooms:  make(map[string]*ChatRoom),
 	}
+	cws.canJoin = cws.canUserJoinRoom
+	return cws
 }
 
-// HandleChatWebSocket upgrades HTTP connection to WebSocket and manages chat
+// HandleChatWebSocket HTTP 연결을 WebSocket으로 업그레이드하고 연결이 끊길 때까지 처리
 func (cws *ChatWebSocketService) HandleChatWebSocket(c *gin.Context) {
-	roomID := c.Param("roomId")
-	userIDStr := c.GetString("user_id")
+	roomID := c.Param("room_id")
+	userID := c.GetUint("user_id")
 	username := c.GetString("username")
 
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil || userID == 0 || username == "" {
+	if userID == 0 || username == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
 	// Check if user has permission to join this chat room
-	if !cws.canUserJoinRoom(uint(userID), roomID) {
+	if !cws.canJoin(userID, roomID) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to join this chat room"})
 		return
 	}
 
+	if !cws.track() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+		return
+	}
+	defer cws.wg.Done()
+
 	// Upgrade connection
-	conn, err := chatUpgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := cws.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		cws.logger.Printf("Failed to upgrade connection: %v", err)
+		cws.logger.Error("채팅 WebSocket 업그레이드 실패", err)
 		return
 	}
 	defer conn.Close()
 
-	// Get or create room
-	room := cws.GetOrCreateChatRoom(roomID)
-	if room == nil {
-		cws.logger.Printf("Failed to get room: %s", roomID)
-		return
+	client := &ChatClient{
+		UserID:     userID,
+		Username:   username,
+		Conn:       conn,
+		Send:       make(chan *models.ChatEnvelope, chatSendBufferSize),
+		resumeFrom: -1,
+	}
+	if resumeFrom, err := strconv.ParseInt(c.Query("resume_from"), 10, 64); err == nil && resumeFrom >= 0 {
+		client.resumeFrom = resumeFrom
 	}
 
-	// Create client
-	client := &ChatClient{
-		UserID:   uint(userID),
-		Username: username,
-		Conn:     conn,
-		Send:     make(chan *ChatMessage, 256),
-		Room:     room,
+	// 방금 종료된 채팅방을 잡았다면 한 번 더 시도
+	for attempt := 0; attempt < 2 && client.Room == nil; attempt++ {
+		room := cws.GetOrCreateChatRoom(roomID)
+		if room == nil {
+			break
+		}
+		client.Room = room
+		if !room.register(client) {
+			client.Room = nil
+		}
+	}
+
+	if client.Room == nil {
+		cws.logger.Warn(fmt.Sprintf("채팅방을 가져올 수 없습니다: %s", roomID))
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "chat room unavailable"),
+			time.Now().Add(chatWriteWait))
+		return
 	}
 
-	// Register client
-	room.Join <- client
+	presence := cws.presence.Connect(userID, c.Query("device_id"))
+	defer presence.Close()
 
-	// Start goroutines for reading and writing
-	go client.writePump()
-	go client.readPump()
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		client.writePump()
+	}()
 
-	// Keep connection alive until client disconnects
-	select {}
+	// 핸들러 goroutine이 읽기를 담당하고, 연결이 끝나면 쓰기 goroutine 종료까지 기다림
+	client.readPump(cws)
+	<-writerDone
 }
 
-// GetOrCreateChatRoom retrieves existing room or creates new one
-func (cws *ChatWebSocketService) GetOrCreateChatRoom(roomID string) *ChatRoom {
-	cws.roomMutex.RLock()
-	room, exists := cws.rooms[roomID]
-	cws.roomMutex.RUnlock()
+// track 진행 중인 작업을 등록 (종료가 시작되었으면 false)
+func (cws *ChatWebSocketService) track() bool {
+	cws.roomMutex.Lock()
+	defer cws.roomMutex.Unlock()
 
-	if exists {
-		return room
+	if cws.closed {
+		return false
 	}
+	cws.wg.Add(1)
+	return true
+}
 
-	// Create new room
+// Shutdown 모든 채팅방을 going-away close 프레임과 함께 종료하고 연결이 정리될 때까지 대기
+func (cws *ChatWebSocketService) Shutdown(ctx context.Context) error {
 	cws.roomMutex.Lock()
-	defer cws.roomMutex.Unlock()
+	cws.closed = true
+	for _, room := range cws.rooms {
+		room.shutdown(websocket.CloseGoingAway, "server shutting down")
+	}
+	cws.roomMutex.Unlock()
 
-	// Double-check after acquiring write lock
-	if room, exists := cws.rooms[roomID]; exists {
+	cws.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		cws.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		cws.logger.Info("채팅 WebSocket 연결 정리 완료")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("채팅 WebSocket 종료 대기 시간 초과: %w", ctx.Err())
+	}
+}
+
+// GetOrCreateChatRoom 이 인스턴스의 채팅방을 가져오거나 새로 생성 (종료 중이거나 만료된 채팅방이면 nil)
+func (cws *ChatWebSocketService) GetOrCreateChatRoom(roomID string) *ChatRoom {
+	cws.roomMutex.Lock()
+	room, exists := cws.rooms[roomID]
+	closed := cws.closed
+	cws.roomMutex.Unlock()
+
+	if closed {
+		return nil
+	}
+	if exists {
 		return room
 	}
 
 	// Parse signal ID from room ID (format: signal_123)
 	var signalID uint
 	if n, err := fmt.Sscanf(roomID, "signal_%d", &signalID); n != 1 || err != nil {
-		cws.logger.Printf("Invalid room ID format: %s", roomID)
+		cws.logger.Warn(fmt.Sprintf("잘못된 채팅방 ID 형식: %s", roomID))
 		return nil
 	}
 
-	// Get signal to determine expiry
-	var signal models.Signal
-	if err := cws.db.First(&signal, signalID).Error; err != nil {
-		cws.logger.Printf("Signal not found: %d", signalID)
+	// 메시지 저장에 사용할 DB 채팅방 (만료: 시그널 예정 시간 24시간 후)
+	dbRoom, err := cws.chatService.GetOrCreateSignalChatRoom(signalID)
+	if err != nil {
+		cws.logger.Warn(fmt.Sprintf("시그널 %d 채팅방을 가져올 수 없습니다: %v", signalID, err))
 		return nil
 	}
 
-	// Create room with expiry 24 hours after signal end time
-	expiresAt := signal.ScheduledAt.Add(24 * time.Hour)
+	if dbRoom.Status != models.ChatRoomActive || dbRoom.ExpiresAt == nil || !time.Now().Before(*dbRoom.ExpiresAt) {
+		return nil
+	}
+	expiresAt := *dbRoom.ExpiresAt
+
+	// DB 조회는 잠금 밖에서 하므로 그 사이 다른 요청이 채팅방을 만들었거나 종료가 시작되었을 수 있음
+	cws.roomMutex.Lock()
+	defer cws.roomMutex.Unlock()
+
+	if cws.closed {
+		return nil
+	}
+	if room, exists := cws.rooms[roomID]; exists {
+		return room
+	}
+
+	ctx, cancel := context.WithCancel(cws.ctx)
 
 	room = &ChatRoom{
-		ID:           roomID,
-		SignalID:     signalID,
-		Participants: make(map[uint]*ChatClient),
-		Messages:     make(chan *ChatMessage, 256),
-		Join:         make(chan *ChatClient),
-		Leave:        make(chan *ChatClient),
-		Created:      time.Now(),
-		ExpiresAt:    expiresAt,
+		ID:            roomID,
+		SignalID:      signalID,
+		ChatRoomID:    dbRoom.ID,
+		Created:       time.Now(),
+		ExpiresAt:     expiresAt,
+		clients:       make(map[*ChatClient]struct{}),
+		join:          make(chan *ChatClient),
+		leave:         make(chan *ChatClient),
+		deliver:       make(chan *models.ChatEnvelope, chatSendBufferSize),
+		replies:       make(chan chatReply, chatSendBufferSize),
+		resumed:       make(chan *chatResume),
+		extended:      make(chan time.Time),
+		ctx:           ctx,
+		cancel:        cancel,
+		subscribed:    make(chan struct{}),
+		done:          make(chan struct{}),
+		presenceWake:  make(chan struct{}, 1),
+		localPresence: make(map[uint]int),
 	}
 
 	cws.rooms[roomID] = room
 
-	// Start room in background
-	go room.Run(cws)
-
-	// Schedule room destruction
-	go cws.scheduleRoomDestruction(roomID, expiresAt)
+	// 채팅방, presence, Redis 구독 goroutine은 서비스 종료 시 함께 대기
+	cws.wg.Add(3)
+	go room.run(cws)
+	go room.runPresence(cws)
+	go cws.subscribeRoom(room)
 
-	cws.logger.Printf("Created chat room: %s, expires: %v", roomID, expiresAt)
+	cws.logger.Info(fmt.Sprintf("채팅방 생성: %s, 만료: %v", roomID, expiresAt))
 
 	return room
 }
 
+// removeRoom 종료된 채팅방을 목록에서 제거
+func (cws *ChatWebSocketService) removeRoom(room *ChatRoom) {
+	cws.roomMutex.Lock()
+	defer cws.roomMutex.Unlock()
+
+	if current, exists := cws.rooms[room.ID]; exists && current == room {
+		delete(cws.rooms, room.ID)
+	}
+}
+
 // canUserJoinRoom checks if user has permission to join chat room
 func (cws *ChatWebSocketService) canUserJoinRoom(userID uint, roomID string) bool {
 	// Parse signal ID from room ID
@@ -201,279 +450,754 @@ func (cws *ChatWebSocketService) canUserJoinRoom(userID uint, roomID string) boo
 
 	// Check if user is approved participant
 	var participant models.SignalParticipant
-	err := cws.db.Where("signal_id = ? AND user_id = ? AND status = ?", 
+	err := cws.db.Where("signal_id = ? AND user_id = ? AND status = ?",
 		signalID, userID, models.ParticipantApproved).First(&participant).Error
-	
+
 	return err == nil
 }
 
-// Run manages the chat room lifecycle
-func (room *ChatRoom) Run(cws *ChatWebSocketService) {
-	defer func() {
-		close(room.Messages)
-		close(room.Join)
-		close(room.Leave)
-		
-		// Close all client connections
-		room.mutex.Lock()
-		for _, client := range room.Participants {
-			close(client.Send)
-		}
-		room.mutex.Unlock()
-	}()
+// subscribeRoom 채팅방 Redis 채널을 구독하여 수신한 이벤트를 로컬 참여자에게 전달
+func (cws *ChatWebSocketService) subscribeRoom(room *ChatRoom) {
+	defer cws.wg.Done()
+
+	// 구독이 확인된 뒤에 입장을 받아야 입장 직후의 이벤트를 놓치지 않음 (그 전 이벤트는 재전송 조회로 받음)
+	pubsub, err := cws.redisClient.SubscribeConfirmed(room.ctx, chatRoomChannel(room.ID), chatSubscribeTimeout)
+	if err != nil && room.ctx.Err() == nil {
+		cws.logger.Warn(fmt.Sprintf("채팅방 %s 구독 확인 실패 (재연결되면 다시 구독): %v", room.ID, err))
+	}
+	defer pubsub.Close()
+
+	// 구독 뒤의 이벤트는 모두 채널로 받으므로 지금 순번이 이 채팅방의 시작 위치
+	if latest, err := cws.redisClient.StreamLatest(room.ctx, chatRoomChannel(room.ID)); err != nil {
+		cws.logger.Warn(fmt.Sprintf("채팅방 %s 스트림 순번 조회 실패: %v", room.ID, err))
+	} else {
+		room.streamSeq = latest
+	}
+	close(room.subscribed)
 
+	ch := pubsub.Channel()
 	for {
 		select {
-		case client := <-room.Join:
-			room.mutex.Lock()
-			room.Participants[client.UserID] = client
-			room.mutex.Unlock()
+		case <-room.ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
 
-			// Send system message: user joined
-			systemMsg := &ChatMessage{
-				RoomID:    room.ID,
-				UserID:    0,
-				Username:  "시스템",
-				Content:   fmt.Sprintf("%s님이 입장했습니다", client.Username),
-				Type:      "system",
-				Timestamp: time.Now(),
+			var sequenced redis.SequencedMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &sequenced); err != nil {
+				cws.logger.Error("채팅 클러스터 이벤트 역직렬화 실패", err)
+				continue
 			}
-			room.broadcastMessage(systemMsg, cws)
+			event, err := decodeChatEvent(&sequenced)
+			if err != nil {
+				cws.logger.Error("채팅 클러스터 이벤트 역직렬화 실패", err)
+				continue
+			}
+
+			switch event.Kind {
+			case chatEventMessage:
+				if event.Envelope == nil {
+					continue
+				}
+				select {
+				case room.deliver <- event.Envelope:
+				case <-room.ctx.Done():
+					return
+				}
+			case chatEventDestroy:
+				// 다른 인스턴스가 채팅방을 파기함
+				room.shutdown(websocket.CloseNormalClosure, "chat room expired")
+			}
+		}
+	}
+}
+
+// publishEvent 채팅방 채널에 이벤트 발행 (발행한 인스턴스도 구독을 통해 수신)
+func (cws *ChatWebSocketService) publishEvent(roomID string, event *chatClusterEvent) error {
+	return publishChatEvent(cws.redisClient, roomID, event)
+}
+
+// register 클라이언트를 채팅방에 등록 (채팅방이 이미 종료되었으면 false)
+func (room *ChatRoom) register(client *ChatClient) bool {
+	select {
+	case room.join <- client:
+		return true
+	case <-room.done:
+		return false
+	}
+}
+
+// unregister 클라이언트를 채팅방에서 제거 요청
+func (room *ChatRoom) unregister(client *ChatClient) {
+	select {
+	case room.leave <- client:
+	case <-room.done:
+	}
+}
+
+// shutdown 채팅방 종료 요청 (처음 요청한 사유가 클라이언트에게 전달됨)
+func (room *ChatRoom) shutdown(code int, text string) {
+	room.closeOnce.Do(func() {
+		room.closeCode = code
+		room.closeText = text
+		room.cancel()
+	})
+}
 
-			cws.logger.Printf("User %s joined room %s", client.Username, room.ID)
+// run 채팅방의 클라이언트 목록과 채널을 소유하는 유일한 goroutine
+func (room *ChatRoom) run(cws *ChatWebSocketService) {
+	defer cws.wg.Done()
 
-		case client := <-room.Leave:
+	// Redis 구독이 확인되기 전에는 입장을 받지 않음
+	select {
+	case <-room.subscribed:
+	case <-room.ctx.Done():
+		room.closeAll(cws)
+		return
+	}
+
+	expiry := time.NewTimer(time.Until(room.expiry()))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-room.ctx.Done():
+			room.closeAll(cws)
+			return
+
+		case <-expiry.C:
+			// DB 확인과 파기는 별도 goroutine에서 (늦춰졌으면 extended로 새 만료 시간 수신)
+			cws.wg.Add(1)
+			go room.checkExpiry(cws)
+
+		case expiresAt := <-room.extended:
+			// 시그널 예정 시간이 바뀌어 만료 시간이 늦춰졌으면 타이머만 다시 설정
 			room.mutex.Lock()
-			if _, ok := room.Participants[client.UserID]; ok {
-				delete(room.Participants, client.UserID)
-				close(client.Send)
-
-				// Send system message: user left
-				systemMsg := &ChatMessage{
-					RoomID:    room.ID,
-					UserID:    0,
-					Username:  "시스템",
-					Content:   fmt.Sprintf("%s님이 나갔습니다", client.Username),
-					Type:      "system",
-					Timestamp: time.Now(),
-				}
-				room.mutex.Unlock()
+			room.ExpiresAt = expiresAt
+			room.mutex.Unlock()
+			expiry.Reset(time.Until(expiresAt))
 
-				room.broadcastMessage(systemMsg, cws)
-				cws.logger.Printf("User %s left room %s", client.Username, room.ID)
+		case client := <-room.join:
+			room.clients[client] = struct{}{}
+			room.mutex.Lock()
+			room.localPresence[client.UserID]++
+			room.mutex.Unlock()
+
+			// 입장 시점에 채팅방이 받은 순번 이후의 이벤트를 모두 전달
+			client.streamSeq = room.streamSeq
+			if client.resumeFrom < 0 {
+				room.sendSession(client, &models.ChatSessionPayload{})
 			} else {
-				room.mutex.Unlock()
+				// 재전송 조회가 끝날 때까지 실시간 이벤트는 클라이언트별로 모아 둠
+				client.resuming = true
+				cws.wg.Add(1)
+				go room.loadResume(client, cws)
+			}
+
+			// 클러스터 전체에서 첫 연결일 때만 입장 메시지 발송
+			room.queuePresence(client, 1)
+
+			cws.logger.Info(fmt.Sprintf("사용자 %s 채팅방 %s 입장", client.Username, room.ID))
+
+		case resume := <-room.resumed:
+			room.finishResume(resume, cws)
+
+		case client := <-room.leave:
+			if _, ok := room.clients[client]; !ok {
+				continue
 			}
+			room.removeClient(client, websocket.CloseNormalClosure, "")
+
+			// 클러스터 전체에서 마지막 연결이 끊겼을 때만 퇴장 메시지 발송
+			room.queuePresence(client, -1)
 
-		case message := <-room.Messages:
-			// Save message to database
-			room.saveMessage(message, cws)
+			cws.logger.Info(fmt.Sprintf("사용자 %s 채팅방 %s 퇴장", client.Username, room.ID))
 
-			// Broadcast to all participants
+		case reply := <-room.replies:
+			room.sendReply(reply)
+
+		case message := <-room.deliver:
+			// 이 인스턴스에 접속한 참여자들에게 전달
+			if message.StreamSeq > room.streamSeq {
+				room.streamSeq = message.StreamSeq
+			}
 			room.broadcastMessage(message, cws)
 		}
 	}
 }
 
-// broadcastMessage sends message to all participants in the room
-func (room *ChatRoom) broadcastMessage(message *ChatMessage, cws *ChatWebSocketService) {
+// expiry 채팅방 만료 시간
+func (room *ChatRoom) expiry() time.Time {
 	room.mutex.RLock()
 	defer room.mutex.RUnlock()
+	return room.ExpiresAt
+}
+
+// checkExpiry 만료 시간이 된 채팅방의 DB 만료 시간을 다시 확인해 늦춰졌으면 알리고 아니면 파기
+func (room *ChatRoom) checkExpiry(cws *ChatWebSocketService) {
+	defer cws.wg.Done()
 
-	for userID, client := range room.Participants {
+	if expiresAt := cws.currentExpiry(room); expiresAt.After(time.Now()) {
 		select {
-		case client.Send <- message:
-			// Message sent successfully
-		default:
-			// Client's send channel is full, remove client
-			delete(room.Participants, userID)
-			close(client.Send)
-			cws.logger.Printf("Removed inactive client %d from room %s", userID, room.ID)
+		case room.extended <- expiresAt:
+		case <-room.done:
 		}
+		return
 	}
+	cws.expireRoom(room)
 }
 
-// saveMessage persists message to database
-func (room *ChatRoom) saveMessage(message *ChatMessage, cws *ChatWebSocketService) {
-	// Get chat room ID from database
-	var dbRoom models.ChatRoom
-	if err := cws.db.Where("signal_id = ?", room.SignalID).First(&dbRoom).Error; err != nil {
-		cws.logger.Printf("Failed to find chat room for signal %d: %v", room.SignalID, err)
+// closeAll 채팅방 종료 시 모든 클라이언트에게 close 프레임을 보내고 정리
+func (room *ChatRoom) closeAll(cws *ChatWebSocketService) {
+	code, text := room.closeCode, room.closeText
+	if code == 0 {
+		code, text = websocket.CloseGoingAway, "server shutting down"
+	}
+	expired := code == websocket.CloseNormalClosure
+
+	// 새 클라이언트가 이 채팅방을 잡지 않도록 먼저 목록에서 제거
+	cws.removeRoom(room)
+
+	for client := range room.clients {
+		room.removeClient(client, code, text)
+
+		// 만료된 채팅방은 presence 키가 이미 삭제됨
+		if !expired {
+			room.queuePresence(client, -1)
+		}
+	}
+
+	// runPresence는 done이 닫히면 남은 갱신까지 처리하고 종료
+	close(room.done)
+
+	cws.logger.Info(fmt.Sprintf("채팅방 종료: %s (%s)", room.ID, text))
+}
+
+// removeClient 클라이언트의 Send 채널을 닫아 writePump가 close 프레임을 보내도록 함
+func (room *ChatRoom) removeClient(client *ChatClient, code int, text string) {
+	delete(room.clients, client)
+	client.pending = nil
+
+	client.closeCode = code
+	client.closeText = text
+	close(client.Send)
+
+	room.mutex.Lock()
+	room.localPresence[client.UserID]--
+	if room.localPresence[client.UserID] <= 0 {
+		delete(room.localPresence, client.UserID)
+	}
+	room.mutex.Unlock()
+}
+
+// handleInbound 클라이언트 메시지를 저장하고, 실패하면 보낸 클라이언트에게만 error 이벤트 전달
+func (room *ChatRoom) handleInbound(inbound chatInbound, cws *ChatWebSocketService) {
+	if inbound.envelope.Version != models.ChatEnvelopeVersion {
+		room.sendError(inbound.client, inbound.envelope.ClientMsgID, "unsupported_version",
+			fmt.Sprintf("지원하지 않는 봉투 버전입니다 (v=%d)", models.ChatEnvelopeVersion))
 		return
 	}
 
-	var userID *uint
-	if message.UserID != 0 {
-		userID = &message.UserID
+	var payload models.ChatMessagePayload
+	if err := inbound.envelope.DecodePayload(&payload); err != nil {
+		room.sendError(inbound.client, inbound.envelope.ClientMsgID, "invalid_payload", "메시지 형식이 올바르지 않습니다")
+		return
 	}
 
-	var msgType models.MessageType
-	switch message.Type {
-	case "text":
-		msgType = models.MessageText
-	case "image":
-		msgType = models.MessageImage
-	case "system":
-		msgType = models.MessageSystem
+	userID := inbound.client.UserID
+	var err error
+
+	switch inbound.envelope.Event {
+	case models.ChatEventMessage:
+		req := &models.SendMessageRequest{
+			ClientMsgID: inbound.envelope.ClientMsgID,
+			Type:        payload.Type,
+			Content:     payload.Content,
+			Location:    payload.Location,
+		}
+		if payload.Attachment != nil && payload.Attachment.ID != 0 {
+			req.AttachmentID = &payload.Attachment.ID
+		}
+		if payload.Poll != nil {
+			req.Poll = pollRequestFromInfo(payload.Poll)
+		}
+		// 답장은 reply_to.server_id로 대상 지정
+		if payload.ReplyTo != nil && payload.ReplyTo.ServerID != 0 {
+			req.ReplyToID = &payload.ReplyTo.ServerID
+		}
+		_, err = cws.chatService.SendMessage(userID, room.ChatRoomID, req)
+
+	case models.ChatEventEdit:
+		req := &models.EditMessageRequest{Content: payload.Content}
+		_, err = cws.chatService.EditMessage(userID, room.ChatRoomID, inbound.envelope.ServerID, req)
+
+	case models.ChatEventDelete:
+		_, err = cws.chatService.DeleteMessage(userID, room.ChatRoomID, inbound.envelope.ServerID)
+
+	case models.ChatEventRead:
+		_, err = cws.chatService.MarkRead(userID, room.ChatRoomID, inbound.envelope.ServerID)
+
+	case models.ChatEventTyping:
+		// 입력 중 표시는 저장하지 않고 클러스터로만 전파
+		var typing models.ChatTypingPayload
+		if err := inbound.envelope.DecodePayload(&typing); err != nil {
+			room.sendError(inbound.client, inbound.envelope.ClientMsgID, "invalid_payload", "메시지 형식이 올바르지 않습니다")
+			return
+		}
+		envelope := models.NewChatEnvelope(models.ChatEventTyping, room.ChatRoomID, &typing)
+		envelope.Sender = &models.ChatSender{ID: userID, Username: inbound.client.Username}
+		room.publishMessage(envelope, cws)
+		return
+
+	case models.ChatEventLiveLocation:
+		var location models.ChatLiveLocationPayload
+		if err := inbound.envelope.DecodePayload(&location); err != nil {
+			room.sendError(inbound.client, inbound.envelope.ClientMsgID, "invalid_payload", "메시지 형식이 올바르지 않습니다")
+			return
+		}
+		if location.Sharing && time.Since(inbound.client.lastLocationAt) < chatLiveLocationInterval {
+			return
+		}
+		if _, err := cws.chatService.ShareLiveLocation(userID, inbound.client.Username, room.ChatRoomID, &location); err != nil {
+			room.sendError(inbound.client, inbound.envelope.ClientMsgID, "location_failed", err.Error())
+			return
+		}
+		inbound.client.sharingLocation = location.Sharing
+		inbound.client.lastLocationAt = time.Now()
+		return
+
 	default:
-		msgType = models.MessageText
+		room.sendError(inbound.client, inbound.envelope.ClientMsgID, "unsupported_event", "지원하지 않는 이벤트입니다")
+		return
 	}
 
-	dbMessage := &models.ChatMessage{
-		ChatRoomID: dbRoom.ID,
-		UserID:     userID,
-		Content:    message.Content,
-		Type:       msgType,
+	if err != nil {
+		code := "send_failed"
+		var moderationErr *ChatModerationError
+		if errors.As(err, &moderationErr) {
+			code = moderationErr.Code
+		}
+		room.sendError(inbound.client, inbound.envelope.ClientMsgID, code, err.Error())
 	}
+}
 
-	if err := cws.db.Create(dbMessage).Error; err != nil {
-		cws.logger.Printf("Failed to save message: %v", err)
+// pollRequestFromInfo WebSocket으로 받은 poll 페이로드를 생성 요청으로 변환 (집계 필드는 무시)
+func pollRequestFromInfo(info *models.ChatPollInfo) *models.CreatePollRequest {
+	req := &models.CreatePollRequest{
+		Question:       info.Question,
+		Kind:           info.Kind,
+		MultipleChoice: info.MultipleChoice,
+		Anonymous:      info.Anonymous,
+		Deadline:       info.Deadline,
+		Options:        make([]models.PollOptionRequest, 0, len(info.Options)),
+	}
+	for _, option := range info.Options {
+		req.Options = append(req.Options, models.PollOptionRequest{
+			Text:        option.Text,
+			ScheduledAt: option.ScheduledAt,
+			Location:    option.Location,
+		})
+	}
+	return req
+}
+
+// sendError 요청한 클라이언트에게만 error 이벤트 전달 (이미 채팅방을 떠났으면 채팅방 goroutine이 버림)
+func (room *ChatRoom) sendError(client *ChatClient, clientMsgID, code, message string) {
+	envelope := models.NewChatEnvelope(models.ChatEventError, room.ChatRoomID, &models.ChatErrorPayload{
+		Code:    code,
+		Message: message,
+	})
+	envelope.ClientMsgID = clientMsgID
+
+	select {
+	case room.replies <- chatReply{client: client, envelope: envelope}:
+	case <-room.done:
+	}
+}
+
+// sendReply 처리 결과를 보낸 클라이언트에게 전달 (전송 버퍼가 가득 차면 버림)
+func (room *ChatRoom) sendReply(reply chatReply) {
+	client := reply.client
+	if _, ok := room.clients[client]; !ok {
+		return
+	}
+	if client.resuming {
+		if len(client.pending) < chatSendBufferSize {
+			client.pending = append(client.pending, reply.envelope)
+		}
+		return
+	}
+
+	select {
+	case client.Send <- reply.envelope:
+	default:
+	}
+}
+
+// systemMessage 저장하지 않는 입장/퇴장 알림 (발신자 없음)
+func (room *ChatRoom) systemMessage(msgType models.MessageType, content string) *models.ChatEnvelope {
+	return models.NewChatEnvelope(models.ChatEventMessage, room.ChatRoomID, &models.ChatMessagePayload{
+		Type:    msgType,
+		Content: content,
+	})
+}
+
+// publishMessage 메시지를 클러스터 전체로 전파, 실패 시 로컬 참여자에게만 전달 (채팅방 goroutine 밖에서 호출)
+func (room *ChatRoom) publishMessage(message *models.ChatEnvelope, cws *ChatWebSocketService) {
+	if err := cws.publishEvent(room.ID, &chatClusterEvent{Kind: chatEventMessage, Envelope: message}); err != nil {
+		cws.logger.Error(fmt.Sprintf("채팅방 %s 메시지 발행 실패", room.ID), err)
+		select {
+		case room.deliver <- message:
+		case <-room.done:
+		}
+	}
+}
+
+// queuePresence presence 갱신을 runPresence에 넘김 (채팅방 goroutine 전용, 막히지 않음)
+func (room *ChatRoom) queuePresence(client *ChatClient, delta int64) {
+	room.presenceMutex.Lock()
+	room.presenceUpdates = append(room.presenceUpdates, chatPresenceUpdate{
+		userID:   client.UserID,
+		username: client.Username,
+		delta:    delta,
+	})
+	room.presenceMutex.Unlock()
+
+	select {
+	case room.presenceWake <- struct{}{}:
+	default:
+	}
+}
+
+// takePresenceUpdates 쌓인 presence 갱신을 모두 꺼냄
+func (room *ChatRoom) takePresenceUpdates() []chatPresenceUpdate {
+	room.presenceMutex.Lock()
+	defer room.presenceMutex.Unlock()
+
+	updates := room.presenceUpdates
+	room.presenceUpdates = nil
+	return updates
+}
+
+// runPresence presence 갱신을 들어온 순서대로 Redis에 반영하고 입장/퇴장 메시지 발송
+func (room *ChatRoom) runPresence(cws *ChatWebSocketService) {
+	defer cws.wg.Done()
+
+	for {
+		select {
+		case <-room.presenceWake:
+			for _, update := range room.takePresenceUpdates() {
+				room.applyPresence(update, cws)
+			}
+		case <-room.done:
+			// closeAll이 done을 닫기 전에 넣은 퇴장까지 처리
+			for _, update := range room.takePresenceUpdates() {
+				room.applyPresence(update, cws)
+			}
+			return
+		}
+	}
+}
+
+// applyPresence presence 갱신 하나를 반영하고, 클러스터 전체에서 첫 입장이나 마지막 퇴장이면 알림 발송
+func (room *ChatRoom) applyPresence(update chatPresenceUpdate, cws *ChatWebSocketService) {
+	count := room.trackPresence(update.userID, update.delta, cws)
+	switch {
+	case update.delta > 0 && count == 1:
+		room.publishMessage(room.systemMessage(models.MessageJoin, fmt.Sprintf("%s님이 입장했습니다", update.username)), cws)
+	case update.delta < 0 && count <= 0:
+		room.publishMessage(room.systemMessage(models.MessageLeave, fmt.Sprintf("%s님이 나갔습니다", update.username)), cws)
+	}
+}
+
+// trackPresence 클러스터 전체 presence 카운트를 갱신하고 변경 후 값을 반환
+func (room *ChatRoom) trackPresence(userID uint, delta int64, cws *ChatWebSocketService) int64 {
+	ctx := context.Background()
+	key := chatPresenceKey(room.ID)
+	field := strconv.FormatUint(uint64(userID), 10)
+
+	count, err := cws.redisClient.HIncrBy(ctx, key, field, delta)
+	if err != nil {
+		cws.logger.Error(fmt.Sprintf("채팅방 %s presence 갱신 실패", room.ID), err)
+		// Redis 장애 시 로컬 상태 기준으로 판단
+		room.mutex.RLock()
+		defer room.mutex.RUnlock()
+		return int64(room.localPresence[userID])
+	}
+
+	if count <= 0 {
+		cws.redisClient.HDel(ctx, key, field)
+	}
+
+	// 인스턴스 비정상 종료로 남은 presence가 영구히 남지 않도록 만료 설정
+	if ttl := time.Until(room.expiry()); ttl > 0 {
+		cws.redisClient.Expire(ctx, key, ttl)
+	}
+
+	return count
+}
+
+// loadResume 재연결한 클라이언트에게 보낼 재전송 이벤트를 조회해 채팅방 goroutine에 넘김
+//
+// 조회는 입장을 처리한 뒤에 시작하므로, 조회 결과보다 뒤의 이벤트는 모두 입장 이후에 전달되어 pending에 쌓인다.
+func (room *ChatRoom) loadResume(client *ChatClient, cws *ChatWebSocketService) {
+	defer cws.wg.Done()
+
+	resume := &chatResume{client: client, session: &models.ChatSessionPayload{}}
+	ctx := context.Background()
+	channel := chatRoomChannel(room.ID)
+
+	if replay, err := cws.redisClient.ReplayStream(ctx, channel, client.resumeFrom); err != nil || !replay.Complete {
+		if err != nil {
+			cws.logger.Warn(fmt.Sprintf("채팅방 %s 재전송 버퍼 조회 실패: %v", room.ID, err))
+		}
+		resume.session.ResyncRequired = true
 	} else {
-		message.ID = dbMessage.ID
+		for i := range replay.Messages {
+			event, err := decodeChatEvent(&replay.Messages[i])
+			if err != nil || event.Kind != chatEventMessage || event.Envelope == nil {
+				continue
+			}
+			resume.messages = append(resume.messages, event.Envelope)
+		}
+		resume.latest = replay.Latest
+		resume.session.Resumed = true
+	}
+
+	select {
+	case room.resumed <- resume:
+	case <-room.done:
+	}
+}
+
+// finishResume 재전송 이벤트와 session 이벤트를 보낸 뒤 조회 중에 모아 둔 이벤트 전달
+//
+// 재전송과 겹치는 실시간 이벤트는 클라이언트별 마지막 순번으로 걸러진다.
+// 이어받을 수 없으면 입장 시점의 순번부터 전달하고 클라이언트는 REST로 메시지를 다시 불러온다.
+func (room *ChatRoom) finishResume(resume *chatResume, cws *ChatWebSocketService) {
+	client := resume.client
+	if _, ok := room.clients[client]; !ok {
+		return
+	}
+
+	for _, envelope := range resume.messages {
+		select {
+		case client.Send <- envelope:
+			resume.session.Replayed++
+		default:
+		}
+	}
+	if resume.session.Resumed {
+		client.streamSeq = resume.latest
+	}
+	room.sendSession(client, resume.session)
+
+	pending := client.pending
+	client.resuming, client.pending = false, nil
+	for _, message := range pending {
+		if !room.sendTo(client, message) {
+			room.dropSlowClient(client, cws)
+			return
+		}
 	}
 }
 
-// writePump handles sending messages to client
+// sendSession session 이벤트 전달 (봉투의 stream_seq는 클라이언트가 따라잡은 순번)
+func (room *ChatRoom) sendSession(client *ChatClient, session *models.ChatSessionPayload) {
+	envelope := models.NewChatEnvelope(models.ChatEventSession, room.ChatRoomID, session)
+	envelope.StreamSeq = client.streamSeq
+	select {
+	case client.Send <- envelope:
+	default:
+	}
+}
+
+// broadcastMessage 이 인스턴스에 접속한 모든 참여자에게 메시지 전달
+func (room *ChatRoom) broadcastMessage(message *models.ChatEnvelope, cws *ChatWebSocketService) {
+	for client := range room.clients {
+		if client.resuming {
+			if len(client.pending) < chatSendBufferSize {
+				client.pending = append(client.pending, message)
+				continue
+			}
+		} else if room.sendTo(client, message) {
+			continue
+		}
+		room.dropSlowClient(client, cws)
+	}
+}
+
+// dropSlowClient 전송 버퍼가 가득 찬 느린 클라이언트의 연결 종료
+func (room *ChatRoom) dropSlowClient(client *ChatClient, cws *ChatWebSocketService) {
+	room.removeClient(client, websocket.CloseTryAgainLater, "client too slow")
+	room.queuePresence(client, -1)
+	cws.logger.Warn(fmt.Sprintf("비활성 클라이언트 %d를 채팅방 %s에서 제거", client.UserID, room.ID))
+}
+
+// sendTo 클라이언트 전송 버퍼에 이벤트 추가 (재전송한 이벤트는 건너뜀, 버퍼가 가득 차면 false)
+func (room *ChatRoom) sendTo(client *ChatClient, message *models.ChatEnvelope) bool {
+	// 재연결 시 이미 재전송한 이벤트
+	if message.StreamSeq != 0 && message.StreamSeq <= client.streamSeq {
+		return true
+	}
+
+	select {
+	case client.Send <- message:
+		if message.StreamSeq != 0 {
+			client.streamSeq = message.StreamSeq
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// writePump 연결에 쓰는 유일한 goroutine (Send가 닫히면 close 프레임 전송)
 func (c *ChatClient) writePump() {
-	ticker := time.NewTicker(54 * time.Second)
-	defer func() {
-		ticker.Stop()
-		c.Conn.Close()
-	}()
+	ticker := time.NewTicker(chatPingPeriod)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case message, ok := <-c.Send:
-			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.Conn.SetWriteDeadline(time.Now().Add(chatWriteWait))
 			if !ok {
-				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				// 채팅방이 연결을 정리함: close 프레임 전송 후 상대방 응답을 잠시 기다림
+				c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeText))
+				c.Conn.SetReadDeadline(time.Now().Add(chatCloseGrace))
 				return
 			}
 
 			if err := c.Conn.WriteJSON(message); err != nil {
+				c.Conn.Close()
+				c.drain()
 				return
 			}
 
 		case <-ticker.C:
-			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.Conn.SetWriteDeadline(time.Now().Add(chatWriteWait))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.Conn.Close()
+				c.drain()
 				return
 			}
 		}
 	}
 }
 
-// readPump handles receiving messages from client
-func (c *ChatClient) readPump() {
+// drain 쓰기 실패 후 채팅방이 Send를 닫을 때까지 남은 메시지를 버림
+func (c *ChatClient) drain() {
+	for range c.Send {
+	}
+}
+
+// readPump 클라이언트 메시지를 읽어 처리 (핸들러 goroutine에서 실행)
+func (c *ChatClient) readPump(cws *ChatWebSocketService) {
 	defer func() {
-		c.Room.Leave <- c
-		c.Conn.Close()
+		c.Room.unregister(c)
+
+		// 연결이 끊기면 위치 공유도 종료 (세션을 넘어 위치가 남지 않도록)
+		if c.sharingLocation {
+			if err := cws.chatService.StopLiveLocation(c.UserID, c.Username, c.Room.ChatRoomID); err != nil {
+				cws.logger.Warn(fmt.Sprintf("채팅방 %s 사용자 %d 위치 공유 종료 실패: %v", c.Room.ID, c.UserID, err))
+			}
+		}
 	}()
 
-	c.Conn.SetReadLimit(512)
-	c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	c.Conn.SetReadLimit(chatMaxMessageSize)
+	c.Conn.SetReadDeadline(time.Now().Add(chatPongWait))
 	c.Conn.SetPongHandler(func(string) error {
-		c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		c.Conn.SetReadDeadline(time.Now().Add(chatPongWait))
 		return nil
 	})
 
 	for {
-		var msgData map[string]interface{}
-		if err := c.Conn.ReadJSON(&msgData); err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket error: %v", err)
+		var envelope models.ChatEnvelope
+		if err := c.Conn.ReadJSON(&envelope); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure, websocket.CloseNormalClosure) {
+				cws.logger.Error("채팅 WebSocket 읽기 오류", err)
 			}
-			break
-		}
-
-		// Validate message data
-		content, contentOk := msgData["content"].(string)
-		msgType, typeOk := msgData["type"].(string)
-
-		if !contentOk || !typeOk || content == "" {
-			continue
+			return
 		}
 
-		// Create message
-		message := &ChatMessage{
-			RoomID:    c.Room.ID,
-			UserID:    c.UserID,
-			Username:  c.Username,
-			Content:   content,
-			Type:      msgType,
-			Timestamp: time.Now(),
+		if !c.allowInbound() {
+			cws.logger.Warn(fmt.Sprintf("채팅 WebSocket 수신 한도 초과: 사용자 %d, 채팅방 %s", c.UserID, c.Room.ID))
+			c.Conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many messages"),
+				time.Now().Add(chatWriteWait))
+			return
 		}
 
-		// Send to room
 		select {
-		case c.Room.Messages <- message:
+		case <-c.Room.done:
+			return
 		default:
-			// Room message channel is full, skip message
 		}
+
+		// 저장은 이 goroutine에서 순서대로 처리해 채팅방 goroutine이 DB를 기다리지 않음 (실패는 error 이벤트로 응답)
+		c.Room.handleInbound(chatInbound{client: c, envelope: &envelope}, cws)
 	}
 }
 
-// scheduleRoomDestruction schedules automatic room cleanup
-func (cws *ChatWebSocketService) scheduleRoomDestruction(roomID string, expiresAt time.Time) {
-	duration := time.Until(expiresAt)
-	timer := time.NewTimer(duration)
-
-	<-timer.C
-
-	// Remove room
-	cws.roomMutex.Lock()
-	if room, exists := cws.rooms[roomID]; exists {
-		// Close all client connections
-		room.mutex.Lock()
-		for _, client := range room.Participants {
-			close(client.Send)
-			client.Conn.Close()
-		}
-		room.mutex.Unlock()
+// allowInbound 연결당 수신 프레임 수 제한 (입력 중 표시 등 저장하지 않는 이벤트 폭주 방지)
+func (c *ChatClient) allowInbound() bool {
+	now := time.Now()
+	if now.Sub(c.inboundWindowStart) >= chatInboundWindow {
+		c.inboundWindowStart = now
+		c.inboundFrames = 0
+	}
+	c.inboundFrames++
+	return c.inboundFrames <= chatMaxInboundFrames
+}
 
-		// Close room channels
-		close(room.Messages)
-		close(room.Join)
-		close(room.Leave)
+// expireRoom 만료 시간이 된 채팅방 정리 (여러 인스턴스 중 하나만 데이터 정리 및 파기 이벤트 발행)
+func (cws *ChatWebSocketService) expireRoom(room *ChatRoom) {
+	acquired, err := cws.redisClient.SetNX(context.Background(), chatDestroyLockKey(room.ID), cws.instanceID, 10*time.Minute)
+	if err != nil {
+		cws.logger.Error(fmt.Sprintf("채팅방 %s 파기 락 획득 실패", room.ID), err)
+	}
 
-		// Delete old room data
-		cws.deleteChatRoomData(roomID)
-		delete(cws.rooms, roomID)
+	if acquired {
+		cws.deleteChatRoomData(room)
+		cws.redisClient.Delete(context.Background(), chatPresenceKey(room.ID), chatLiveLocationKey(room.ID))
 
-		cws.logger.Printf("Auto-destroyed chat room: %s", roomID)
+		if err := cws.publishEvent(room.ID, &chatClusterEvent{Kind: chatEventDestroy}); err != nil {
+			cws.logger.Error(fmt.Sprintf("채팅방 %s 파기 이벤트 발행 실패", room.ID), err)
+		}
+		if err := cws.redisClient.DeleteStream(context.Background(), chatRoomChannel(room.ID)); err != nil {
+			cws.logger.Warn(fmt.Sprintf("채팅방 %s 재전송 버퍼 삭제 실패: %v", room.ID, err))
+		}
 	}
-	cws.roomMutex.Unlock()
+
+	room.shutdown(websocket.CloseNormalClosure, "chat room expired")
 }
 
-// deleteChatRoomData removes old messages from database
-func (cws *ChatWebSocketService) deleteChatRoomData(roomID string) {
-	cutoff := time.Now().Add(-24 * time.Hour)
-	result := cws.db.Where("room_id = ? AND created_at < ?", roomID, cutoff).Delete(&models.ChatMessage{})
-	
-	if result.Error != nil {
-		cws.logger.Printf("Failed to cleanup messages for room %s: %v", roomID, result.Error)
-	} else {
-		cws.logger.Printf("Cleaned up %d old messages for room %s", result.RowsAffected, roomID)
+// currentExpiry DB에 저장된 채팅방 만료 시간 (조회 실패 시 기존 값)
+func (cws *ChatWebSocketService) currentExpiry(room *ChatRoom) time.Time {
+	var dbRoom models.ChatRoom
+	if err := cws.db.Select("id", "expires_at").First(&dbRoom, room.ChatRoomID).Error; err != nil || dbRoom.ExpiresAt == nil {
+		return room.ExpiresAt
 	}
+	return *dbRoom.ExpiresAt
 }
 
-// GetRoomMessages retrieves message history for a room
-func (cws *ChatWebSocketService) GetRoomMessages(roomID string, limit int, offset int) ([]models.ChatMessage, error) {
-	var messages []models.ChatMessage
-
-	err := cws.db.Where("room_id = ?", roomID).
-		Order("created_at ASC").
-		Limit(limit).
-		Offset(offset).
-		Find(&messages).Error
-
-	return messages, err
+// deleteChatRoomData 만료된 채팅방을 사용자에게서 숨김
+//
+// 메시지 삭제와 신고된 채팅방의 대화 보관은 워커의 채팅방 만료 작업이 처리한다.
+func (cws *ChatWebSocketService) deleteChatRoomData(room *ChatRoom) {
+	if err := cws.db.Delete(&models.ChatRoom{}, room.ChatRoomID).Error; err != nil {
+		cws.logger.Error(fmt.Sprintf("채팅방 %s 숨김 처리 실패", room.ID), err)
+		return
+	}
+	cws.logger.Info(fmt.Sprintf("채팅방 %s 파기 (메시지는 워커가 정리)", room.ID))
 }
 
 // GetActiveRooms returns list of currently active chat rooms
 func (cws *ChatWebSocketService) GetActiveRooms() map[string]*ChatRoom {
-	cws.roomMutex.RLock()
-	defer cws.roomMutex.RUnlock()
+	cws.roomMutex.Lock()
+	defer cws.roomMutex.Unlock()
 
 	rooms := make(map[string]*ChatRoom)
 	for id, room := range cws.rooms {
@@ -483,11 +1207,33 @@ func (cws *ChatWebSocketService) GetActiveRooms() map[string]*ChatRoom {
 	return rooms
 }
 
-// GetRoomParticipants returns current participants in a room
+// GetRoomParticipants 모든 인스턴스를 합친 채팅방의 현재 참여자
 func (cws *ChatWebSocketService) GetRoomParticipants(roomID string) []uint {
-	cws.roomMutex.RLock()
+	presence, err := cws.redisClient.HGetAll(context.Background(), chatPresenceKey(roomID))
+	if err != nil {
+		cws.logger.Error(fmt.Sprintf("채팅방 %s presence 조회 실패", roomID), err)
+		return cws.getLocalRoomParticipants(roomID)
+	}
+
+	participants := make([]uint, 0, len(presence))
+	for field, countStr := range presence {
+		count, _ := strconv.Atoi(countStr)
+		if count <= 0 {
+			continue
+		}
+		if userID, err := strconv.ParseUint(field, 10, 32); err == nil {
+			participants = append(participants, uint(userID))
+		}
+	}
+
+	return participants
+}
+
+// getLocalRoomParticipants 이 인스턴스에 접속한 채팅방 참여자
+func (cws *ChatWebSocketService) getLocalRoomParticipants(roomID string) []uint {
+	cws.roomMutex.Lock()
 	room, exists := cws.rooms[roomID]
-	cws.roomMutex.RUnlock()
+	cws.roomMutex.Unlock()
 
 	if !exists {
 		return []uint{}
@@ -496,25 +1242,10 @@ func (cws *ChatWebSocketService) GetRoomParticipants(roomID string) []uint {
 	room.mutex.RLock()
 	defer room.mutex.RUnlock()
 
-	participants := make([]uint, 0, len(room.Participants))
-	for userID := range room.Participants {
+	participants := make([]uint, 0, len(room.localPresence))
+	for userID := range room.localPresence {
 		participants = append(participants, userID)
 	}
 
 	return participants
 }
-
-// CleanupExpiredMessages removes old messages from database
-func (cws *ChatWebSocketService) CleanupExpiredMessages() error {
-	cutoff := time.Now().Add(-24 * time.Hour)
-	
-	result := cws.db.Where("created_at < ?", cutoff).Delete(&models.ChatMessage{})
-	
-	if result.Error != nil {
-		cws.logger.Printf("Failed to cleanup expired messages: %v", result.Error)
-		return result.Error
-	}
-	
-	cws.logger.Printf("Cleaned up %d expired messages", result.RowsAffected)
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,463 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// 채팅 허브 동시성 테스트 (go test -race)
+//
+// Redis와 DB에 연결할 수 없는 상태에서 실행한다. presence와 발행은 로컬 상태로 대체되고,
+// 만료 시 DB 조회가 실패하면 기존 만료 시간을 그대로 쓰므로 허브 동작만 검증할 수 있다.
+
+// fakeChatService 채팅방 조회, 메시지 전송, 위치 공유 종료만 구현 (나머지는 호출되지 않음)
+//
+// lookup과 send가 있으면 채팅방 조회와 메시지 전송 중에 호출된다 (느린 DB 흉내).
+type fakeChatService struct {
+	ChatServiceInterface
+
+	mu        sync.Mutex
+	expiresAt time.Time
+	lookup    func(signalID uint)
+	send      func(req *models.SendMessageRequest)
+}
+
+func (f *fakeChatService) GetOrCreateSignalChatRoom(signalID uint) (*models.ChatRoom, error) {
+	if f.lookup != nil {
+		f.lookup(signalID)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	expiresAt := f.expiresAt
+	room := &models.ChatRoom{Status: models.ChatRoomActive, ExpiresAt: &expiresAt}
+	room.ID = signalID
+	return room, nil
+}
+
+func (f *fakeChatService) SendMessage(userID, chatRoomID uint, req *models.SendMessageRequest) (*models.ChatEnvelope, error) {
+	if f.send != nil {
+		f.send(req)
+	}
+	return models.NewChatEnvelope(models.ChatEventMessage, chatRoomID, &models.ChatMessagePayload{Type: req.Type, Content: req.Content}), nil
+}
+
+func (f *fakeChatService) StopLiveLocation(userID uint, username string, chatRoomID uint) error {
+	return nil
+}
+
+// offlineRedis 연결할 수 없는 주소의 Redis (모든 명령이 바로 실패)
+func offlineRedis() *redis.Client {
+	return redis.NewFromClient(goredis.NewClient(&goredis.Options{
+		Addr:        "127.0.0.1:1",
+		MaxRetries:  -1,
+		DialTimeout: 100 * time.Millisecond,
+	}))
+}
+
+// offlineDB 연결할 수 없는 주소의 DB (모든 조회가 바로 실패)
+func offlineDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=signal dbname=signal sslmode=disable connect_timeout=1"), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("DB 초기화 실패: %v", err)
+	}
+	return db
+}
+
+func newTestChatWebSocketService(t testing.TB, redisClient *redis.Client, expiresAt time.Time) *ChatWebSocketService {
+	t.Helper()
+
+	appLogger := logger.New("chat-websocket-test")
+	chatService := &fakeChatService{expiresAt: expiresAt}
+	presence := NewPresenceService(redisClient, nil, appLogger)
+
+	cws := NewChatWebSocketService(offlineDB(t), redisClient, chatService, presence, nil, appLogger)
+	cws.canJoin = func(userID uint, roomID string) bool { return true }
+	return cws
+}
+
+// testChatClient 연결 없이 채팅방에 등록하는 클라이언트 (Send를 끝까지 읽음)
+type testChatClient struct {
+	*ChatClient
+	closed chan struct{}
+}
+
+func newTestChatClient(userID uint) *testChatClient {
+	client := &testChatClient{
+		ChatClient: &ChatClient{
+			UserID:     userID,
+			Username:   fmt.Sprintf("user%d", userID),
+			Send:       make(chan *models.ChatEnvelope, chatSendBufferSize),
+			resumeFrom: -1,
+		},
+		closed: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(client.closed)
+		for range client.Send {
+		}
+	}()
+	return client
+}
+
+// join 채팅방을 가져와 등록 (방금 종료된 채팅방이면 false)
+func (c *testChatClient) join(cws *ChatWebSocketService, roomID string) bool {
+	room := cws.GetOrCreateChatRoom(roomID)
+	if room == nil {
+		return false
+	}
+	c.Room = room
+	return room.register(c.ChatClient)
+}
+
+// waitClosed 채팅방이 Send를 닫을 때까지 대기하고 close 코드 반환
+func (c *testChatClient) waitClosed(t *testing.T) int {
+	t.Helper()
+
+	select {
+	case <-c.closed:
+		return c.closeCode
+	case <-time.After(5 * time.Second):
+		t.Fatalf("사용자 %d 연결이 닫히지 않았습니다", c.UserID)
+		return 0
+	}
+}
+
+func shutdownChatService(t *testing.T, cws *ChatWebSocketService) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cws.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown 실패: %v", err)
+	}
+}
+
+func TestChatRoomConcurrentJoinLeave(t *testing.T) {
+	cws := newTestChatWebSocketService(t, offlineRedis(), time.Now().Add(time.Hour))
+	defer shutdownChatService(t, cws)
+
+	const clients = 50
+	var wg sync.WaitGroup
+	for i := 1; i <= clients; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+
+			client := newTestChatClient(userID)
+			if !client.join(cws, "signal_1") {
+				t.Errorf("사용자 %d 입장 실패", userID)
+				return
+			}
+			// 중복 퇴장은 무시되어야 함
+			client.Room.unregister(client.ChatClient)
+			client.Room.unregister(client.ChatClient)
+
+			if code := client.waitClosed(t); code != websocket.CloseNormalClosure {
+				t.Errorf("사용자 %d close 코드: %d", userID, code)
+			}
+		}(uint(i))
+	}
+	wg.Wait()
+
+	if participants := cws.getLocalRoomParticipants("signal_1"); len(participants) != 0 {
+		t.Fatalf("퇴장 후 남은 참여자: %v", participants)
+	}
+}
+
+func TestChatRoomExpireWhileJoiningAndLeaving(t *testing.T) {
+	cws := newTestChatWebSocketService(t, offlineRedis(), time.Now().Add(200*time.Millisecond))
+	defer shutdownChatService(t, cws)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+
+			// 만료될 때까지 입장과 퇴장을 반복하고, 마지막 연결은 만료로 닫혀야 함
+			for attempt := 0; ; attempt++ {
+				client := newTestChatClient(userID)
+				if !client.join(cws, "signal_2") {
+					return
+				}
+				if attempt%2 == 0 {
+					client.Room.unregister(client.ChatClient)
+					client.waitClosed(t)
+					continue
+				}
+
+				code := client.waitClosed(t)
+				if code != websocket.CloseNormalClosure || client.closeText != "chat room expired" {
+					t.Errorf("사용자 %d 만료 close: %d %q", userID, code, client.closeText)
+				}
+				return
+			}
+		}(uint(i))
+	}
+	wg.Wait()
+
+	if _, exists := cws.GetActiveRooms()["signal_2"]; exists {
+		t.Fatal("만료된 채팅방이 목록에 남아 있습니다")
+	}
+	if room := cws.GetOrCreateChatRoom("signal_2"); room != nil {
+		t.Fatal("만료된 채팅방을 다시 만들었습니다")
+	}
+}
+
+func TestChatWebSocketShutdownWhileJoining(t *testing.T) {
+	cws := newTestChatWebSocketService(t, offlineRedis(), time.Now().Add(time.Hour))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		joined  []*testChatClient
+		started = make(chan struct{})
+	)
+	for i := 1; i <= 40; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			<-started
+
+			client := newTestChatClient(userID)
+			if !client.join(cws, fmt.Sprintf("signal_%d", 10+userID%4)) {
+				// 종료가 시작된 뒤에는 입장할 수 없음
+				return
+			}
+			mu.Lock()
+			joined = append(joined, client)
+			mu.Unlock()
+
+			if userID%3 == 0 {
+				client.Room.unregister(client.ChatClient)
+			}
+		}(uint(i))
+	}
+
+	close(started)
+	time.Sleep(5 * time.Millisecond)
+	shutdownChatService(t, cws)
+	wg.Wait()
+
+	for _, client := range joined {
+		code := client.waitClosed(t)
+		if code != websocket.CloseGoingAway && code != websocket.CloseNormalClosure {
+			t.Errorf("사용자 %d close 코드: %d", client.UserID, code)
+		}
+	}
+	if rooms := cws.GetActiveRooms(); len(rooms) != 0 {
+		t.Fatalf("종료 후 남은 채팅방: %d개", len(rooms))
+	}
+	if client := newTestChatClient(99); client.join(cws, "signal_10") {
+		t.Fatal("종료 후 입장했습니다")
+	}
+}
+
+// newChatTestServer user_id/username 쿼리로 인증을 대신하는 채팅 WebSocket 서버
+func newChatTestServer(cws *ChatWebSocketService) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/chat/:room_id", func(c *gin.Context) {
+		userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 64)
+		c.Set("user_id", uint(userID))
+		c.Set("username", c.Query("username"))
+		cws.HandleChatWebSocket(c)
+	})
+	return httptest.NewServer(router)
+}
+
+func dialChat(server *httptest.Server, roomID string, userID uint) (*websocket.Conn, *http.Response, error) {
+	url := fmt.Sprintf("ws%s/chat/%s?user_id=%d&username=user%d", strings.TrimPrefix(server.URL, "http"), roomID, userID, userID)
+	return websocket.DefaultDialer.Dial(url, nil)
+}
+
+// readChatEnvelope 다음 봉투 읽기 (event가 비어 있지 않으면 그 이벤트가 나올 때까지)
+func readChatEnvelope(t *testing.T, conn *websocket.Conn, event models.ChatEventType) *models.ChatEnvelope {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var envelope models.ChatEnvelope
+		if err := conn.ReadJSON(&envelope); err != nil {
+			t.Fatalf("봉투 읽기 실패: %v", err)
+		}
+		if event == "" || envelope.Event == event {
+			return &envelope
+		}
+	}
+}
+
+func TestHandleChatWebSocketShutdown(t *testing.T) {
+	cws := newTestChatWebSocketService(t, offlineRedis(), time.Now().Add(time.Hour))
+	server := newChatTestServer(cws)
+	defer server.Close()
+
+	var conns []*websocket.Conn
+	for userID := uint(1); userID <= 5; userID++ {
+		conn, _, err := dialChat(server, "signal_20", userID)
+		if err != nil {
+			t.Fatalf("사용자 %d 연결 실패: %v", userID, err)
+		}
+		defer conn.Close()
+		readChatEnvelope(t, conn, models.ChatEventSession)
+		conns = append(conns, conn)
+	}
+
+	// 일부는 종료와 동시에 직접 연결을 끊음
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		if i%2 == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(conn *websocket.Conn) {
+			defer wg.Done()
+			conn.Close()
+		}(conn)
+	}
+	shutdownChatService(t, cws)
+	wg.Wait()
+
+	for i, conn := range conns {
+		if i%2 != 0 {
+			continue
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			_, _, err := conn.ReadMessage()
+			if err == nil {
+				continue
+			}
+			if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+				t.Errorf("연결 %d 종료 사유: %v", i, err)
+			}
+			break
+		}
+	}
+
+	// 종료가 시작된 뒤의 연결은 거부
+	if _, resp, err := dialChat(server, "signal_20", 10); err == nil {
+		t.Fatal("종료 후 연결이 수락되었습니다")
+	} else if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("종료 후 연결 응답: %v", err)
+	}
+}
+
+func TestGetOrCreateChatRoomLooksUpOutsideLock(t *testing.T) {
+	cws := newTestChatWebSocketService(t, offlineRedis(), time.Now().Add(time.Hour))
+	defer shutdownChatService(t, cws)
+
+	// signal_31 채팅방 조회가 DB에서 멈춘 동안
+	entered := make(chan struct{}, 8)
+	release := make(chan struct{})
+	releaseLookup := sync.OnceFunc(func() { close(release) })
+	defer releaseLookup()
+	cws.chatService.(*fakeChatService).lookup = func(signalID uint) {
+		if signalID == 31 {
+			entered <- struct{}{}
+			<-release
+		}
+	}
+
+	const callers = 4
+	rooms := make(chan *ChatRoom, callers)
+	for i := 0; i < callers; i++ {
+		go func() { rooms <- cws.GetOrCreateChatRoom("signal_31") }()
+	}
+	<-entered
+
+	// 다른 채팅방은 기다리지 않고 만들어짐
+	created := make(chan *ChatRoom, 1)
+	go func() { created <- cws.GetOrCreateChatRoom("signal_32") }()
+	select {
+	case room := <-created:
+		if room == nil {
+			t.Fatal("다른 채팅방을 만들지 못했습니다")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("다른 채팅방 조회가 DB 조회를 기다립니다")
+	}
+
+	// 동시에 조회한 요청은 모두 같은 채팅방을 받음
+	releaseLookup()
+	first := <-rooms
+	for i := 1; i < callers; i++ {
+		if room := <-rooms; room == nil || room != first {
+			t.Fatal("동시에 조회한 요청이 다른 채팅방을 받았습니다")
+		}
+	}
+	if active := cws.GetActiveRooms()["signal_31"]; active != first {
+		t.Fatal("목록의 채팅방이 반환한 채팅방과 다릅니다")
+	}
+}
+
+func TestChatRoomSlowSendDoesNotBlockRoom(t *testing.T) {
+	cws := newTestChatWebSocketService(t, offlineRedis(), time.Now().Add(time.Hour))
+	server := newChatTestServer(cws)
+	defer server.Close()
+	defer shutdownChatService(t, cws)
+
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	releaseSend := sync.OnceFunc(func() { close(release) })
+	defer releaseSend()
+	cws.chatService.(*fakeChatService).send = func(req *models.SendMessageRequest) {
+		close(sending)
+		<-release
+	}
+
+	alice, _, err := dialChat(server, "signal_40", 1)
+	if err != nil {
+		t.Fatalf("연결 실패: %v", err)
+	}
+	defer alice.Close()
+	readChatEnvelope(t, alice, models.ChatEventSession)
+
+	message := models.NewChatEnvelope(models.ChatEventMessage, 0, &models.ChatMessagePayload{Type: models.MessageText, Content: "안녕하세요"})
+	if err := alice.WriteJSON(message); err != nil {
+		t.Fatalf("메시지 전송 실패: %v", err)
+	}
+	<-sending
+
+	// 메시지 저장이 끝나지 않아도 다른 사용자의 입장과 이벤트 전달은 계속됨
+	bob, _, err := dialChat(server, "signal_40", 2)
+	if err != nil {
+		t.Fatalf("연결 실패: %v", err)
+	}
+	defer bob.Close()
+	readChatEnvelope(t, bob, models.ChatEventSession)
+
+	typing := models.NewChatEnvelope(models.ChatEventTyping, 0, &models.ChatTypingPayload{IsTyping: true})
+	if err := bob.WriteJSON(typing); err != nil {
+		t.Fatalf("입력 중 표시 전송 실패: %v", err)
+	}
+	if envelope := readChatEnvelope(t, alice, models.ChatEventTyping); envelope.Sender == nil || envelope.Sender.ID != 2 {
+		t.Fatalf("입력 중 표시 발신자: %+v", envelope.Sender)
+	}
+}
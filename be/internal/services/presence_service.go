@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"signal-be/internal/repositories"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/redis"
+)
+
+const (
+	// 하트비트가 끊긴 연결을 오프라인으로 보기까지의 시간과 하트비트 간격
+	presenceTTL               = 90 * time.Second
+	presenceHeartbeatInterval = 30 * time.Second
+
+	presenceMaxDeviceIDLength = 64
+)
+
+type PresenceServiceInterface interface {
+	Connect(userID uint, deviceID string) *PresenceConnection
+	IsOnline(ctx context.Context, userID uint) bool
+	GetPresence(viewerID uint, userIDs []uint) map[uint]*models.UserPresence
+	AttachPresence(viewerID uint, users ...*models.User)
+}
+
+// PresenceService 기기별 연결을 추적해 사용자 접속 상태와 마지막 접속 시각 제공
+//
+// 실시간 연결(지도 WebSocket/SSE, 채팅 WebSocket)이 열려 있는 동안 연결마다 하트비트를 보내므로
+// 한 기기의 연결이 끊겨도 다른 기기가 접속해 있으면 온라인으로 유지된다.
+// 다른 사용자에게는 presence_visible 설정을 끈 사용자의 상태를 보여주지 않는다.
+type PresenceService struct {
+	redisClient *redis.Client
+	userRepo    repositories.UserRepositoryInterface
+	logger      *logger.Logger
+}
+
+func NewPresenceService(redisClient *redis.Client, userRepo repositories.UserRepositoryInterface, logger *logger.Logger) PresenceServiceInterface {
+	return &PresenceService{
+		redisClient: redisClient,
+		userRepo:    userRepo,
+		logger:      logger,
+	}
+}
+
+// PresenceConnection 실시간 연결 하나의 접속 상태 (Close할 때까지 하트비트)
+type PresenceConnection struct {
+	service      *PresenceService
+	userID       uint
+	deviceID     string
+	connectionID string
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// Connect 연결 시작을 기록하고 하트비트 시작 (deviceID가 없으면 연결마다 다른 기기로 취급)
+func (s *PresenceService) Connect(userID uint, deviceID string) *PresenceConnection {
+	connectionID := newSignalSessionID()
+	deviceID = strings.ReplaceAll(strings.TrimSpace(deviceID), "|", "")
+	if deviceID == "" || len(deviceID) > presenceMaxDeviceIDLength {
+		deviceID = connectionID
+	}
+
+	conn := &PresenceConnection{
+		service:      s,
+		userID:       userID,
+		deviceID:     deviceID,
+		connectionID: connectionID,
+		stop:         make(chan struct{}),
+	}
+	conn.touch()
+	go conn.heartbeat()
+
+	return conn
+}
+
+func (p *PresenceConnection) heartbeat() {
+	ticker := time.NewTicker(presenceHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.touch()
+		}
+	}
+}
+
+func (p *PresenceConnection) touch() {
+	err := p.service.redisClient.TouchPresence(context.Background(), p.userID, p.deviceID, p.connectionID, presenceTTL)
+	if err != nil {
+		p.service.logger.Warn(fmt.Sprintf("사용자 %d 접속 상태 갱신 실패: %v", p.userID, err))
+	}
+}
+
+// Close 연결 종료 기록 (여러 번 호출해도 한 번만 처리)
+func (p *PresenceConnection) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		err := p.service.redisClient.RemovePresence(context.Background(), p.userID, p.deviceID, p.connectionID)
+		if err != nil {
+			p.service.logger.Warn(fmt.Sprintf("사용자 %d 접속 종료 기록 실패: %v", p.userID, err))
+		}
+	})
+}
+
+// IsOnline 기기 중 하나라도 접속해 있는지 확인 (푸시/오프라인 알림 발송 판단용, 공개 설정과 무관)
+func (s *PresenceService) IsOnline(ctx context.Context, userID uint) bool {
+	online, err := s.redisClient.IsUserOnline(ctx, userID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("사용자 %d 접속 상태 조회 실패: %v", userID, err))
+		return false
+	}
+	return online
+}
+
+// GetPresence viewerID가 볼 수 있는 사용자들의 접속 상태 (비공개 사용자는 결과에 없음)
+func (s *PresenceService) GetPresence(viewerID uint, userIDs []uint) map[uint]*models.UserPresence {
+	result := make(map[uint]*models.UserPresence, len(userIDs))
+	if len(userIDs) == 0 {
+		return result
+	}
+
+	states, err := s.redisClient.GetPresence(context.Background(), userIDs)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("접속 상태 조회 실패: %v", err))
+		return result
+	}
+
+	hidden, err := s.userRepo.GetPresenceHiddenUserIDs(userIDs)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("접속 상태 공개 설정 조회 실패: %v", err))
+		return result
+	}
+
+	for _, userID := range userIDs {
+		if userID != viewerID && hidden[userID] {
+			continue
+		}
+
+		state := states[userID]
+		presence := &models.UserPresence{IsOnline: state.Online}
+		if !state.Online && !state.LastSeen.IsZero() {
+			lastSeen := state.LastSeen
+			presence.LastSeenAt = &lastSeen
+		}
+		if userID == viewerID {
+			presence.Devices = state.Devices
+		}
+		result[userID] = presence
+	}
+
+	return result
+}
+
+// AttachPresence 사용자 목록에 접속 상태 채우기
+func (s *PresenceService) AttachPresence(viewerID uint, users ...*models.User) {
+	userIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		if user != nil && user.ID != 0 {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+
+	presence := s.GetPresence(viewerID, userIDs)
+	for _, user := range users {
+		if user != nil {
+			user.Presence = presence[user.ID]
+		}
+	}
+}
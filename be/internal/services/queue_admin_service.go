@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"signal-module/pkg/logger"
+	"signal-module/pkg/queue"
+	"signal-module/pkg/utils"
+)
+
+type QueueAdminServiceInterface interface {
+	GetStats() (map[queue.JobType]map[string]int64, error)
+	ListDeadLetters(jobType string, page, limit int) ([]*queue.Job, *utils.Pagination, error)
+	GetDeadLetter(jobType, jobID string) (*queue.Job, error)
+	ReplayDeadLetter(jobType, jobID string) error
+	ReplayDeadLetters(jobType string) (int, error)
+	PurgeDeadLetter(jobType, jobID string) error
+	PurgeDeadLetters(jobType string) (int64, error)
+}
+
+// ErrQueueJobNotFound 데드 레터 큐에 작업이 없음
+var ErrQueueJobNotFound = errors.New("작업을 찾을 수 없습니다")
+
+// QueueAdminService 관리자용 작업 큐 상태 조회와 데드 레터 관리 (signal-queue CLI와 같은 기능)
+type QueueAdminService struct {
+	queue  *queue.Queue
+	logger *logger.Logger
+}
+
+func NewQueueAdminService(jobQueue *queue.Queue, logger *logger.Logger) QueueAdminServiceInterface {
+	return &QueueAdminService{
+		queue:  jobQueue,
+		logger: logger,
+	}
+}
+
+func (s *QueueAdminService) GetStats() (map[queue.JobType]map[string]int64, error) {
+	return s.queue.AllQueueStats(context.Background())
+}
+
+func (s *QueueAdminService) ListDeadLetters(jobType string, page, limit int) ([]*queue.Job, *utils.Pagination, error) {
+	parsed, err := parseQueueJobType(jobType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, total, err := s.queue.DeadLetters(context.Background(), parsed, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("%s 데드 레터 조회 실패", parsed), err)
+		return nil, nil, fmt.Errorf("데드 레터 조회에 실패했습니다")
+	}
+
+	pagination := utils.CalculatePagination(page, limit, total)
+	return jobs, &pagination, nil
+}
+
+func (s *QueueAdminService) GetDeadLetter(jobType, jobID string) (*queue.Job, error) {
+	parsed, err := parseQueueJobType(jobType)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.queue.DeadLetter(context.Background(), parsed, jobID)
+	if err != nil {
+		return nil, s.deadLetterError(parsed, "조회", err)
+	}
+	return job, nil
+}
+
+func (s *QueueAdminService) ReplayDeadLetter(jobType, jobID string) error {
+	parsed, err := parseQueueJobType(jobType)
+	if err != nil {
+		return err
+	}
+
+	if err := s.queue.ReplayDeadLetter(context.Background(), parsed, jobID); err != nil {
+		return s.deadLetterError(parsed, "재실행", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("데드 레터 작업 재실행: %s/%s", parsed, jobID))
+	return nil
+}
+
+func (s *QueueAdminService) ReplayDeadLetters(jobType string) (int, error) {
+	parsed, err := parseQueueJobType(jobType)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed, err := s.queue.ReplayDeadLetters(context.Background(), parsed)
+	if err != nil {
+		return 0, s.deadLetterError(parsed, "재실행", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("데드 레터 작업 일괄 재실행: %s %d건", parsed, replayed))
+	return replayed, nil
+}
+
+func (s *QueueAdminService) PurgeDeadLetter(jobType, jobID string) error {
+	parsed, err := parseQueueJobType(jobType)
+	if err != nil {
+		return err
+	}
+
+	if err := s.queue.PurgeDeadLetter(context.Background(), parsed, jobID); err != nil {
+		return s.deadLetterError(parsed, "삭제", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("데드 레터 작업 삭제: %s/%s", parsed, jobID))
+	return nil
+}
+
+func (s *QueueAdminService) PurgeDeadLetters(jobType string) (int64, error) {
+	parsed, err := parseQueueJobType(jobType)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := s.queue.PurgeDeadLetters(context.Background(), parsed)
+	if err != nil {
+		return 0, s.deadLetterError(parsed, "삭제", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("데드 레터 큐 비움: %s %d건", parsed, purged))
+	return purged, nil
+}
+
+func (s *QueueAdminService) deadLetterError(jobType queue.JobType, action string, err error) error {
+	if errors.Is(err, queue.ErrJobNotFound) {
+		return ErrQueueJobNotFound
+	}
+	s.logger.Error(fmt.Sprintf("%s 데드 레터 %s 실패", jobType, action), err)
+	return fmt.Errorf("데드 레터 %s에 실패했습니다", action)
+}
+
+func parseQueueJobType(value string) (queue.JobType, error) {
+	jobType, ok := queue.ParseJobType(value)
+	if !ok {
+		return "", fmt.Errorf("알 수 없는 작업 타입입니다")
+	}
+	return jobType, nil
+}
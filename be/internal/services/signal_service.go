@@ -25,8 +25,13 @@ type SignalServiceInterface interface {
 	RejectParticipant(signalID, creatorID, userID uint) error
 	GetMySignals(userID uint, page, limit int) ([]models.Signal, *utils.Pagination, error)
 	GetNearbySignals(lat, lon, radius float64, categories []models.InterestCategory) ([]models.SignalWithDistance, error)
+	UpdateSignal(signalID, userID uint, req *models.UpdateSignalRequest) (*models.Signal, error)
+	CancelSignal(signalID, userID uint) error
 }
 
+// 시그널 만료 시간 (예정 시간 기준)
+const signalExpiryAfterSchedule = 2 * time.Hour
+
 type SignalService struct {
 	signalRepo repositories.SignalRepositoryInterface
 	userRepo   repositories.UserRepositoryInterface
@@ -71,22 +76,13 @@ func (s *SignalService) CreateSignal(creatorID uint, req *models.CreateSignalReq
 
 	// 2. 시간 유효성 검사
 	now := time.Now()
-	if req.ScheduledAt.Before(now.Add(10 * time.Minute)) {
-		return nil, fmt.Errorf("최소 10분 후 시간으로 설정해야 합니다")
-	}
-
-	if req.ScheduledAt.After(now.Add(168 * time.Hour)) { // 1주일
-		return nil, fmt.Errorf("1주일 이후의 시그널은 생성할 수 없습니다")
+	if err := validateSignalSchedule(req.ScheduledAt, now); err != nil {
+		return nil, err
 	}
 
 	// 3. 위치 유효성 검사
-	if !utils.IsValidCoordinate(req.Latitude, req.Longitude) {
-		return nil, fmt.Errorf("유효하지 않은 좌표입니다")
-	}
-
-	// 한국 내 위치인지 확인 (대략적)
-	if !utils.IsWithinKorea(req.Latitude, req.Longitude) {
-		return nil, fmt.Errorf("한국 내 위치만 지원됩니다")
+	if err := validateSignalLocation(req.Latitude, req.Longitude); err != nil {
+		return nil, err
 	}
 
 	// 4. 일일 시그널 생성 제한 확인
@@ -113,7 +109,7 @@ func (s *SignalService) CreateSignal(creatorID uint, req *models.CreateSignalReq
 	}
 
 	// 만료 시간 설정 (예정 시간 + 2시간)
-	expiresAt := req.ScheduledAt.Add(2 * time.Hour)
+	expiresAt := req.ScheduledAt.Add(signalExpiryAfterSchedule)
 
 	signal := &models.Signal{
 		CreatorID:           creatorID,
@@ -181,17 +177,146 @@ func (s *SignalService) CreateSignal(creatorID uint, req *models.CreateSignalReq
 		}
 	}()
 
-	// 12. 근처 시그널 캐시 무효화
+	// 12. 근처 시그널 캐시 무효화 및 지도 구독자에게 전파
 	go s.invalidateNearbyCache(signal.Latitude, signal.Longitude)
+	s.publishSignalEvent(models.NewSignalEvent(models.SignalEventCreated, signal))
 
 	// 13. 주변 사용자들에게 푸시 알림 발송 (매칭 기반)
-	go s.notifyMatchedUsers(signal)
+	go s.notifyMatchedUsers(ctx, signal)
 
 	s.logger.LogSignalCreated(ctx, signal.ID, creatorID)
 
 	return signal, nil
 }
 
+// UpdateSignal 생성자가 시그널 정보 수정 (시간/위치는 생성할 때와 같은 기준으로 검사)
+func (s *SignalService) UpdateSignal(signalID, userID uint, req *models.UpdateSignalRequest) (*models.Signal, error) {
+	ctx := context.Background()
+
+	signal, err := s.signalRepo.GetByID(signalID)
+	if err != nil {
+		return nil, fmt.Errorf("시그널을 찾을 수 없습니다")
+	}
+
+	if signal.CreatorID != userID {
+		return nil, fmt.Errorf("시그널 생성자만 수정할 수 있습니다")
+	}
+
+	if signal.Status != models.SignalActive && signal.Status != models.SignalFull {
+		return nil, fmt.Errorf("진행 중인 시그널만 수정할 수 있습니다")
+	}
+
+	if req.Title != nil {
+		if len(*req.Title) < 5 || len(*req.Title) > 100 {
+			return nil, fmt.Errorf("제목은 5자 이상 100자 이하로 입력해주세요")
+		}
+		signal.Title = *req.Title
+	}
+
+	if req.Description != nil {
+		if len(*req.Description) > 500 {
+			return nil, fmt.Errorf("설명은 500자 이하로 입력해주세요")
+		}
+		signal.Description = *req.Description
+	}
+
+	oldLat, oldLon := signal.Latitude, signal.Longitude
+	locationChanged := req.Latitude != nil || req.Longitude != nil
+	if locationChanged {
+		if req.Latitude == nil || req.Longitude == nil {
+			return nil, fmt.Errorf("위도와 경도를 함께 입력해주세요")
+		}
+		if err := validateSignalLocation(*req.Latitude, *req.Longitude); err != nil {
+			return nil, err
+		}
+		signal.Latitude = *req.Latitude
+		signal.Longitude = *req.Longitude
+	}
+	if req.Address != nil {
+		signal.Address = *req.Address
+	}
+	if req.PlaceName != nil {
+		signal.PlaceName = *req.PlaceName
+	}
+
+	scheduleChanged := req.ScheduledAt != nil && !req.ScheduledAt.Equal(signal.ScheduledAt)
+	if scheduleChanged {
+		if err := validateSignalSchedule(*req.ScheduledAt, time.Now()); err != nil {
+			return nil, err
+		}
+		signal.ScheduledAt = *req.ScheduledAt
+		signal.ExpiresAt = req.ScheduledAt.Add(signalExpiryAfterSchedule)
+	}
+
+	if err := s.signalRepo.Update(signal); err != nil {
+		s.logger.Error("시그널 수정 실패", err)
+		return nil, fmt.Errorf("시그널 수정에 실패했습니다")
+	}
+
+	if scheduleChanged {
+		// 같은 시그널의 이전 만료 작업은 새 작업으로 대체됨
+		if err := s.queue.ScheduleSignalExpiration(ctx, signal.ID, signal.ExpiresAt); err != nil {
+			s.logger.Warn(fmt.Sprintf("시그널 만료 스케줄링 실패: %v", err))
+		}
+		if err := s.signalRepo.UpdateChatRoomExpiry(signal.ID, signal.ScheduledAt.Add(models.ChatRoomLifetime)); err != nil {
+			s.logger.Warn(fmt.Sprintf("시그널 %d 채팅방 만료 시간 변경 실패: %v", signal.ID, err))
+		}
+	}
+
+	event := models.NewSignalEvent(models.SignalEventUpdated, signal)
+	if locationChanged {
+		event.PrevLatitude, event.PrevLongitude = &oldLat, &oldLon
+		if err := s.redisClient.AddActiveSignal(ctx, signal.ID, signal.Latitude, signal.Longitude); err != nil {
+			s.logger.Warn(fmt.Sprintf("Redis 시그널 위치 갱신 실패: %v", err))
+		}
+		go s.invalidateNearbyCache(oldLat, oldLon)
+		go s.invalidateNearbyCache(signal.Latitude, signal.Longitude)
+	}
+
+	s.publishSignalEvent(event)
+
+	s.logger.Info(fmt.Sprintf("시그널 %d 수정 완료", signal.ID))
+
+	return signal, nil
+}
+
+// CancelSignal 생성자가 진행 중인 시그널 취소 (지도와 주변 검색에서 즉시 제외)
+func (s *SignalService) CancelSignal(signalID, userID uint) error {
+	ctx := context.Background()
+
+	signal, err := s.signalRepo.GetByID(signalID)
+	if err != nil {
+		return fmt.Errorf("시그널을 찾을 수 없습니다")
+	}
+
+	if signal.CreatorID != userID {
+		return fmt.Errorf("시그널 생성자만 취소할 수 있습니다")
+	}
+
+	if signal.Status != models.SignalActive && signal.Status != models.SignalFull {
+		return fmt.Errorf("진행 중인 시그널만 취소할 수 있습니다")
+	}
+
+	signal.Status = models.SignalCancelled
+	if err := s.signalRepo.Update(signal); err != nil {
+		s.logger.Error("시그널 취소 실패", err)
+		return fmt.Errorf("시그널 취소에 실패했습니다")
+	}
+
+	if err := s.redisClient.RemoveActiveSignal(ctx, signal.ID); err != nil {
+		s.logger.Warn(fmt.Sprintf("Redis 시그널 제거 실패: %v", err))
+	}
+	if err := s.queue.CancelSignalExpiration(ctx, signal.ID); err != nil {
+		s.logger.Warn(fmt.Sprintf("시그널 %d 만료 작업 취소 실패: %v", signal.ID, err))
+	}
+	go s.invalidateNearbyCache(signal.Latitude, signal.Longitude)
+	s.publishSignalEvent(models.NewSignalEvent(models.SignalEventCancelled, signal))
+
+	s.logger.Info(fmt.Sprintf("시그널 %d 취소 완료", signal.ID))
+
+	return nil
+}
+
 func (s *SignalService) GetSignal(signalID uint) (*models.Signal, error) {
 	signal, err := s.signalRepo.GetByID(signalID)
 	if err != nil {
@@ -326,8 +451,9 @@ func (s *SignalService) JoinSignal(signalID, userID uint, req *models.JoinSignal
 		return fmt.Errorf("시그널 참여에 실패했습니다")
 	}
 
-	// 10. 승인된 경우 즉시 채팅방 초대
+	// 10. 승인된 경우 즉시 채팅방 초대 (참여 인원이 바뀌었으므로 지도에도 전파)
 	if status == models.ParticipantApproved {
+		s.publishSeatsChanged(signalID)
 		go func() {
 			if err := s.inviteUserToChatRoom(signalID, userID); err != nil {
 				s.logger.Error("채팅방 초대 실패", err)
@@ -338,9 +464,9 @@ func (s *SignalService) JoinSignal(signalID, userID uint, req *models.JoinSignal
 	// 11. 생성자에게 알림 발송
 	go func() {
 		if status == models.ParticipantPending {
-			s.notifyCreatorOfJoinRequest(signal.CreatorID, signal, user)
+			s.notifyCreatorOfJoinRequest(ctx, signal.CreatorID, signal, user)
 		} else {
-			s.notifyCreatorOfJoinApproval(signal.CreatorID, signal, user)
+			s.notifyCreatorOfJoinApproval(ctx, signal.CreatorID, signal, user)
 		}
 	}()
 
@@ -355,6 +481,8 @@ func (s *SignalService) LeaveSignal(signalID, userID uint) error {
 		return fmt.Errorf("시그널 나가기에 실패했습니다")
 	}
 
+	s.publishSeatsChanged(signalID)
+
 	s.logger.Info(fmt.Sprintf("시그널 나가기: 사용자 %d, 시그널 %d", userID, signalID))
 
 	return nil
@@ -376,6 +504,8 @@ func (s *SignalService) ApproveParticipant(signalID, creatorID, userID uint) err
 		return fmt.Errorf("참여자 승인에 실패했습니다")
 	}
 
+	s.publishSeatsChanged(signalID)
+
 	s.logger.Info(fmt.Sprintf("참여자 승인: 시그널 %d, 사용자 %d", signalID, userID))
 
 	return nil
@@ -409,6 +539,8 @@ func (s *SignalService) RejectParticipant(signalID, creatorID, userID uint) erro
 		return fmt.Errorf("참여자 거절에 실패했습니다")
 	}
 
+	s.publishSeatsChanged(signalID)
+
 	s.logger.Info(fmt.Sprintf("참여자 거절: 시그널 %d, 사용자 %d", signalID, userID))
 
 	return nil
@@ -475,6 +607,40 @@ func (s *SignalService) roundToGrid(coord, gridSize float64) float64 {
 	return float64(int(coord/gridSize)) * gridSize
 }
 
+// publishSignalEvent 지도 WebSocket 구독자에게 시그널 변경 전파 (Redis를 통해 모든 인스턴스로)
+func (s *SignalService) publishSignalEvent(event *models.SignalEvent) {
+	if err := s.redisClient.PublishSignalEvent(context.Background(), event); err != nil {
+		s.logger.Warn(fmt.Sprintf("시그널 %d 이벤트 발행 실패: %v", event.Signal.ID, err))
+	}
+}
+
+// publishSeatsChanged 참여 인원이 바뀐 시그널을 다시 조회해 전파 (정원 마감/해제 상태 포함)
+func (s *SignalService) publishSeatsChanged(signalID uint) {
+	signal, err := s.signalRepo.GetByID(signalID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("시그널 %d 조회 실패: %v", signalID, err))
+		return
+	}
+	s.publishSignalEvent(models.NewSignalEvent(models.SignalEventSeats, signal))
+}
+
+// pushNotification 푸시 알림 작업을 추가하고 접속 중인 대상에게는 실시간 알림 발행 (지도 WebSocket/SSE)
+func (s *SignalService) pushNotification(ctx context.Context, userIDs []uint, title, body string, data map[string]string) error {
+	notification := &models.UserNotification{
+		UserIDs:   userIDs,
+		Type:      data["type"],
+		Title:     title,
+		Body:      body,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+	if err := s.redisClient.PublishUserNotification(ctx, notification); err != nil {
+		s.logger.Warn(fmt.Sprintf("실시간 알림 발행 실패: %v", err))
+	}
+
+	return s.queue.PushNotification(ctx, userIDs, title, body, data)
+}
+
 // filterSignalsByCategory filters signals by categories if provided
 func (s *SignalService) filterSignalsByCategory(signals []models.SignalWithDistance, categories []models.InterestCategory) []models.SignalWithDistance {
 	if len(categories) == 0 {
@@ -519,6 +685,33 @@ func (s *SignalService) invalidateNearbyCache(lat, lon float64) {
 	s.logger.Info("근처 시그널 캐시 무효화 완료")
 }
 
+// validateSignalSchedule 예정 시간은 최소 10분 후부터 1주일 이내
+func validateSignalSchedule(scheduledAt, now time.Time) error {
+	if scheduledAt.Before(now.Add(10 * time.Minute)) {
+		return fmt.Errorf("최소 10분 후 시간으로 설정해야 합니다")
+	}
+
+	if scheduledAt.After(now.Add(168 * time.Hour)) { // 1주일
+		return fmt.Errorf("1주일 이후의 시그널은 생성할 수 없습니다")
+	}
+
+	return nil
+}
+
+// validateSignalLocation 유효한 좌표이며 한국 내 위치인지 확인
+func validateSignalLocation(lat, lon float64) error {
+	if !utils.IsValidCoordinate(lat, lon) {
+		return fmt.Errorf("유효하지 않은 좌표입니다")
+	}
+
+	// 한국 내 위치인지 확인 (대략적)
+	if !utils.IsWithinKorea(lat, lon) {
+		return fmt.Errorf("한국 내 위치만 지원됩니다")
+	}
+
+	return nil
+}
+
 // validateSignalSettings 시그널 설정 유효성 검사
 func (s *SignalService) validateSignalSettings(req *models.CreateSignalRequest) error {
 	// 제목 길이 확인
@@ -625,7 +818,7 @@ func (s *SignalService) inviteUserToChatRoom(signalID, userID uint) error {
 }
 
 // notifyCreatorOfJoinRequest 생성자에게 참여 요청 알림
-func (s *SignalService) notifyCreatorOfJoinRequest(creatorID uint, signal *models.Signal, user *models.User) {
+func (s *SignalService) notifyCreatorOfJoinRequest(ctx context.Context, creatorID uint, signal *models.Signal, user *models.User) {
 	title := fmt.Sprintf("📝 %s 참여 요청", signal.Title)
 	body := fmt.Sprintf("%s님이 참여를 요청했습니다", user.Profile.DisplayName)
 	data := map[string]string{
@@ -634,13 +827,13 @@ func (s *SignalService) notifyCreatorOfJoinRequest(creatorID uint, signal *model
 		"user_id":   fmt.Sprintf("%d", user.ID),
 	}
 
-	if err := s.queue.PushNotification(nil, []uint{creatorID}, title, body, data); err != nil {
+	if err := s.pushNotification(ctx, []uint{creatorID}, title, body, data); err != nil {
 		s.logger.Error("참여 요청 알림 발송 실패", err)
 	}
 }
 
 // notifyCreatorOfJoinApproval 생성자에게 즉시 참여 알림
-func (s *SignalService) notifyCreatorOfJoinApproval(creatorID uint, signal *models.Signal, user *models.User) {
+func (s *SignalService) notifyCreatorOfJoinApproval(ctx context.Context, creatorID uint, signal *models.Signal, user *models.User) {
 	title := fmt.Sprintf("✅ %s 새 참여자", signal.Title)
 	body := fmt.Sprintf("%s님이 참여했습니다", user.Profile.DisplayName)
 	data := map[string]string{
@@ -649,13 +842,13 @@ func (s *SignalService) notifyCreatorOfJoinApproval(creatorID uint, signal *mode
 		"user_id":   fmt.Sprintf("%d", user.ID),
 	}
 
-	if err := s.queue.PushNotification(nil, []uint{creatorID}, title, body, data); err != nil {
+	if err := s.pushNotification(ctx, []uint{creatorID}, title, body, data); err != nil {
 		s.logger.Error("참여 알림 발송 실패", err)
 	}
 }
 
 // notifyMatchedUsers 매칭된 사용자들에게 알림 발송
-func (s *SignalService) notifyMatchedUsers(signal *models.Signal) {
+func (s *SignalService) notifyMatchedUsers(ctx context.Context, signal *models.Signal) {
 	// 사용자의 관심사와 위치를 기반으로 매칭된 사용자들에게만 알림
 	users, err := s.userRepo.GetMatchedUsersForSignal(signal)
 	if err != nil {
@@ -683,14 +876,14 @@ func (s *SignalService) notifyMatchedUsers(signal *models.Signal) {
 	}
 
 	// 푸시 알림 큐에 추가
-	if err := s.queue.PushNotification(nil, userIDs, title, body, data); err != nil {
+	if err := s.pushNotification(ctx, userIDs, title, body, data); err != nil {
 		s.logger.Error("매칭 사용자 푸시 알림 큐 추가 실패", err)
 	} else {
 		s.logger.Info(fmt.Sprintf("매칭 사용자 %d명에게 알림 발송", len(userIDs)))
 	}
 }
 
-func (s *SignalService) notifyNearbyUsers(signal *models.Signal) {
+func (s *SignalService) notifyNearbyUsers(ctx context.Context, signal *models.Signal) {
 	// 주변 사용자 조회 (5km 반경)
 	users, err := s.userRepo.GetUsersInRadius(signal.Latitude, signal.Longitude, 5000, signal.CreatorID)
 	if err != nil {
@@ -716,7 +909,7 @@ func (s *SignalService) notifyNearbyUsers(signal *models.Signal) {
 	}
 
 	// 푸시 알림 큐에 추가
-	if err := s.queue.PushNotification(nil, userIDs, title, body, data); err != nil {
+	if err := s.pushNotification(ctx, userIDs, title, body, data); err != nil {
 		s.logger.Error("푸시 알림 큐 추가 실패", err)
 	}
 }
\ No newline at end of file
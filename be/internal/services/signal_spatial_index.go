@@ -0,0 +1,151 @@
+package services
+
+import "math"
+
+// 위도 1도의 길이 (미터)
+const metersPerDegree = 111320.0
+
+// 격자 단계별 칸 크기 (도)
+//
+// 구독 영역은 가로/세로로 최대 2칸에 걸치는 가장 작은 단계에 등록되므로 한 영역은 최대 4칸에 들어가고,
+// 한 지점을 조회할 때는 단계마다 그 지점이 속한 칸 하나씩만 확인한다.
+var spatialCellSizes = []float64{0.005, 0.02, 0.08, 0.32, 1.28, 5.12, 20.48, 81.92, 360}
+
+type LocationBounds struct {
+	MinLat float64
+	MaxLat float64
+	MinLon float64
+	MaxLon float64
+}
+
+func (b LocationBounds) contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+// signalViewport 연결 하나가 구독하는 지도 영역 (날짜 변경선을 넘는 영역은 둘로 나뉘어 같은 ID를 가짐)
+type signalViewport struct {
+	client    *SignalClient
+	id        string
+	bounds    LocationBounds
+	centerLat float64
+	centerLon float64
+	cells     []spatialCell
+}
+
+type spatialCell struct {
+	level int
+	x, y  int
+}
+
+// spatialIndex 구독 영역의 계층 격자 인덱스 (WebSocketService 뮤텍스로 보호)
+type spatialIndex struct {
+	cells map[spatialCell]map[*signalViewport]struct{}
+}
+
+func newSpatialIndex() *spatialIndex {
+	return &spatialIndex{cells: make(map[spatialCell]map[*signalViewport]struct{})}
+}
+
+func (idx *spatialIndex) insert(v *signalViewport) {
+	level, minX, maxX, minY, maxY := coverCells(v.bounds)
+
+	v.cells = v.cells[:0]
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			cell := spatialCell{level: level, x: x, y: y}
+			set, ok := idx.cells[cell]
+			if !ok {
+				set = make(map[*signalViewport]struct{})
+				idx.cells[cell] = set
+			}
+			set[v] = struct{}{}
+			v.cells = append(v.cells, cell)
+		}
+	}
+}
+
+func (idx *spatialIndex) remove(v *signalViewport) {
+	for _, cell := range v.cells {
+		set := idx.cells[cell]
+		delete(set, v)
+		if len(set) == 0 {
+			delete(idx.cells, cell)
+		}
+	}
+	v.cells = nil
+}
+
+// query 지점을 포함하는 구독 영역마다 fn 호출
+func (idx *spatialIndex) query(lat, lon float64, fn func(v *signalViewport)) {
+	for level, size := range spatialCellSizes {
+		cell := spatialCell{level: level, x: cellCoord(lon, size), y: cellCoord(lat, size)}
+		for v := range idx.cells[cell] {
+			if v.bounds.contains(lat, lon) {
+				fn(v)
+			}
+		}
+	}
+}
+
+// coverCells 영역이 가로/세로 2칸 이내로 들어가는 가장 작은 단계와 칸 범위
+func coverCells(b LocationBounds) (level, minX, maxX, minY, maxY int) {
+	for level = 0; level < len(spatialCellSizes); level++ {
+		size := spatialCellSizes[level]
+		minX, maxX = cellCoord(b.MinLon, size), cellCoord(b.MaxLon, size)
+		minY, maxY = cellCoord(b.MinLat, size), cellCoord(b.MaxLat, size)
+		if maxX-minX <= 1 && maxY-minY <= 1 {
+			return
+		}
+	}
+	// 마지막 단계(360도)는 올바른 좌표 범위를 항상 2칸 안에 담음
+	level--
+	return
+}
+
+func cellCoord(deg, size float64) int {
+	return int(math.Floor(deg / size))
+}
+
+// radiusBounds 중심과 반경(미터)으로 구독 영역 계산
+//
+// 경도 1도의 길이는 위도에 따라 줄어들므로 영역에서 극에 가장 가까운 위도를 기준으로 보정하고,
+// 날짜 변경선을 넘으면 둘로 나눈다.
+func radiusBounds(lat, lon, radius float64) []LocationBounds {
+	latDelta := radius / metersPerDegree
+	edgeLat := math.Min(math.Abs(lat)+latDelta, 90)
+
+	lonDelta := 180.0
+	if cos := math.Cos(edgeLat * math.Pi / 180); cos > 1e-9 {
+		lonDelta = math.Min(radius/(metersPerDegree*cos), 180)
+	}
+
+	return splitAntimeridian(LocationBounds{
+		MinLat: math.Max(lat-latDelta, -90),
+		MaxLat: math.Min(lat+latDelta, 90),
+		MinLon: lon - lonDelta,
+		MaxLon: lon + lonDelta,
+	})
+}
+
+// splitAntimeridian -180~180 범위를 벗어난 경도를 나눠 담음 (min_lon > max_lon이면 날짜 변경선을 넘는 영역)
+func splitAntimeridian(b LocationBounds) []LocationBounds {
+	if b.MinLon > b.MaxLon {
+		b.MaxLon += 360
+	}
+
+	switch {
+	case b.MaxLon-b.MinLon >= 360:
+		b.MinLon, b.MaxLon = -180, 180
+	case b.MinLon < -180:
+		east := b
+		east.MinLon, east.MaxLon = b.MinLon+360, 180
+		b.MinLon = -180
+		return []LocationBounds{b, east}
+	case b.MaxLon > 180:
+		west := b
+		west.MinLon, west.MaxLon = -180, b.MaxLon-360
+		b.MaxLon = 180
+		return []LocationBounds{b, west}
+	}
+	return []LocationBounds{b}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// 서울을 중심으로 흩어진 지도 구독 50,000개 (10개 중 1개 연결은 영역 3개)
+const (
+	benchSubscribers = 50_000
+	benchCenterLat   = 37.5665
+	benchCenterLon   = 126.9780
+)
+
+// spatialBenchScenario 구독이 흩어진 범위(도)와 구독 반경 상한(미터)
+type spatialBenchScenario struct {
+	name      string
+	spreadDeg float64
+	maxRadius float64
+}
+
+var spatialBenchScenarios = []spatialBenchScenario{
+	{name: "metro", spreadDeg: 0.9, maxRadius: 20_000}, // 수도권 100km, 반경 500m~20km (구독이 많이 겹침)
+	{name: "nation", spreadDeg: 3, maxRadius: 5_000},   // 한반도 남부 650km, 반경 500m~5km
+}
+
+type spatialBenchFixture struct {
+	index     *spatialIndex
+	viewports []*signalViewport
+	points    [][2]float64
+}
+
+func newSpatialBenchFixture(b *testing.B, scenario spatialBenchScenario) *spatialBenchFixture {
+	b.Helper()
+
+	rng := rand.New(rand.NewSource(1))
+	randomPoint := func() (float64, float64) {
+		return benchCenterLat + (rng.Float64()*2-1)*scenario.spreadDeg, benchCenterLon + (rng.Float64()*2-1)*scenario.spreadDeg
+	}
+
+	fixture := &spatialBenchFixture{index: newSpatialIndex()}
+	for i := 0; i < benchSubscribers; i++ {
+		client := &SignalClient{}
+		viewports := 1
+		if i%10 == 0 {
+			viewports = 3
+		}
+		for j := 0; j < viewports; j++ {
+			lat, lon := randomPoint()
+			radius := 500 + rng.Float64()*(scenario.maxRadius-500)
+			for _, bounds := range radiusBounds(lat, lon, radius) {
+				v := &signalViewport{client: client, bounds: bounds, centerLat: lat, centerLon: lon}
+				fixture.index.insert(v)
+				fixture.viewports = append(fixture.viewports, v)
+			}
+		}
+	}
+
+	for i := 0; i < 1024; i++ {
+		lat, lon := randomPoint()
+		fixture.points = append(fixture.points, [2]float64{lat, lon})
+	}
+
+	// 인덱스 조회 결과가 전체 탐색과 같은지 확인
+	for _, point := range fixture.points[:64] {
+		indexed := 0
+		fixture.index.query(point[0], point[1], func(v *signalViewport) { indexed++ })
+		if linear := fixture.linearQuery(point[0], point[1], func(v *signalViewport) {}); linear != indexed {
+			b.Fatalf("(%f, %f) 인덱스 %d개, 전체 탐색 %d개", point[0], point[1], indexed, linear)
+		}
+	}
+	return fixture
+}
+
+// linearQuery 인덱스 없이 모든 구독 영역을 확인 (비교 기준)
+func (f *spatialBenchFixture) linearQuery(lat, lon float64, fn func(v *signalViewport)) int {
+	matched := 0
+	for _, v := range f.viewports {
+		if v.bounds.contains(lat, lon) {
+			fn(v)
+			matched++
+		}
+	}
+	return matched
+}
+
+// candidates 조회 시 범위 검사를 하는 구독 영역 수 (지점이 속한 단계별 칸의 구독 수 합)
+func (f *spatialBenchFixture) candidates(lat, lon float64) int {
+	total := 0
+	for level, size := range spatialCellSizes {
+		total += len(f.index.cells[spatialCell{level: level, x: cellCoord(lon, size), y: cellCoord(lat, size)}])
+	}
+	return total
+}
+
+// BenchmarkSpatialIndex 시그널 이벤트 하나를 받을 구독자를 찾는 비용 (fan-out)
+//
+// subscribers/op은 실제로 이벤트를 받는 연결 수, candidates/op은 범위를 검사한 구독 영역 수다.
+// linear는 인덱스 없이 모든 구독 영역을 확인하는 비교 기준이다.
+func BenchmarkSpatialIndex(b *testing.B) {
+	for _, scenario := range spatialBenchScenarios {
+		fixture := newSpatialBenchFixture(b, scenario)
+
+		b.Run(scenario.name+"/query", func(b *testing.B) {
+			var subscribers, candidates int
+			targets := make(map[*SignalClient]struct{})
+			collect := func(v *signalViewport) { targets[v.client] = struct{}{} }
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				point := fixture.points[i%len(fixture.points)]
+				clear(targets)
+				fixture.index.query(point[0], point[1], collect)
+				subscribers += len(targets)
+			}
+			b.StopTimer()
+
+			for i := 0; i < b.N; i++ {
+				point := fixture.points[i%len(fixture.points)]
+				candidates += fixture.candidates(point[0], point[1])
+			}
+			b.ReportMetric(float64(subscribers)/float64(b.N), "subscribers/op")
+			b.ReportMetric(float64(candidates)/float64(b.N), "candidates/op")
+		})
+
+		b.Run(scenario.name+"/linear", func(b *testing.B) {
+			var subscribers int
+			targets := make(map[*SignalClient]struct{})
+			collect := func(v *signalViewport) { targets[v.client] = struct{}{} }
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				point := fixture.points[i%len(fixture.points)]
+				clear(targets)
+				fixture.linearQuery(point[0], point[1], collect)
+				subscribers += len(targets)
+			}
+			b.ReportMetric(float64(subscribers)/float64(b.N), "subscribers/op")
+			b.ReportMetric(float64(len(fixture.viewports)), "candidates/op")
+		})
+
+		// 지도를 움직일 때마다 일어나는 구독 영역 교체
+		b.Run(scenario.name+"/move", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				v := fixture.viewports[i%len(fixture.viewports)]
+				point := fixture.points[i%len(fixture.points)]
+				fixture.index.remove(v)
+				v.bounds = radiusBounds(point[0], point[1], 5000)[0]
+				fixture.index.insert(v)
+			}
+		})
+	}
+}
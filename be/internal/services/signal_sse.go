@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"signal-module/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 프록시가 유휴 연결을 끊지 않도록 보내는 주석 간격
+	sseHeartbeatInterval = 15 * time.Second
+	// 연결이 끊겼을 때 브라우저 EventSource가 다시 연결하기까지 기다리는 시간 (밀리초)
+	sseRetryMillis = 3000
+)
+
+// HandleSignalSSE 지도 이벤트와 사용자 알림을 Server-Sent Events로 전달 (WebSocket 업그레이드가 막힌 네트워크용)
+//
+// WebSocket과 같은 구독(Run)에서 이벤트를 받는다. 구독 영역은 쿼리의 latitude/longitude/radius 또는
+// min_lat/max_lat/min_lon/max_lon, 카테고리는 categories로 지정하며, 재연결할 때 Last-Event-ID 헤더
+// (또는 last_event_id 쿼리)를 보내면 끊긴 동안의 이벤트를 이어받는다.
+func (ws *WebSocketService) HandleSignalSSE(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	bounds, centerLat, centerLon, ok := parseViewport(func(key string) (float64, bool) {
+		value, err := strconv.ParseFloat(c.Query(key), 64)
+		return value, err == nil && !math.IsNaN(value)
+	})
+	if !ok {
+		utils.BadRequestResponse(c, "잘못된 영역입니다")
+		return
+	}
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		utils.InternalServerErrorResponse(c, "스트리밍을 지원하지 않는 연결입니다", nil)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	client := &SignalClient{
+		ID:         fmt.Sprintf("sse_user_%d_%d", userID, time.Now().UnixNano()),
+		UserID:     userID,
+		Send:       make(chan *SignalUpdate, signalSendBufferSize),
+		viewports:  make(map[string][]*signalViewport),
+		categories: parseCategories(c.Query("categories")),
+		presence:   ws.presence.Connect(userID, c.Query("device_id")),
+		disconnect: cancel,
+	}
+	defer client.presence.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // nginx 응답 버퍼링 끔
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetryMillis)
+	flusher.Flush()
+
+	defer ws.removeClient(client)
+	ws.attachClient(client, []signalSessionViewport{{
+		ID:        signalDefaultViewport,
+		Bounds:    bounds,
+		CenterLat: centerLat,
+		CenterLon: centerLon,
+	}})
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if signalFrom, notifyFrom, ok := parseSignalEventID(lastEventID); ok {
+		result, replayed := ws.replayMissed(client, signalFrom, notifyFrom)
+		ws.logger.Info(fmt.Sprintf("시그널 SSE 이어받기: 사용자 %d, %s (재전송 %d건)", userID, result, replayed))
+	} else {
+		ws.startLive(client, signalUpdateSession)
+		ws.logger.Info(fmt.Sprintf("시그널 SSE 연결: 사용자 %d", userID))
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-client.Send:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(c.Writer, update); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEvent id(보낸 시점의 두 순번), event(메시지 종류), data(JSON) 형식으로 기록
+func writeSSEEvent(w io.Writer, update *SignalUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", update.eventID, update.Type, data)
+	return err
+}
+
+// formatSignalEventID 시그널 이벤트 순번과 알림 순번을 묶은 SSE 이벤트 ID ("<seq>-<notification_seq>")
+func formatSignalEventID(signalSeq, notifySeq int64) string {
+	return strconv.FormatInt(signalSeq, 10) + "-" + strconv.FormatInt(notifySeq, 10)
+}
+
+func parseSignalEventID(id string) (int64, int64, bool) {
+	signalPart, notifyPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	signalSeq, err1 := strconv.ParseInt(signalPart, 10, 64)
+	notifySeq, err2 := strconv.ParseInt(notifyPart, 10, 64)
+	if err1 != nil || err2 != nil || signalSeq < 0 || notifySeq < 0 {
+		return 0, 0, false
+	}
+	return signalSeq, notifySeq, true
+}
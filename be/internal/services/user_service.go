@@ -1,12 +1,14 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"signal-be/internal/repositories"
 	"signal-module/pkg/logger"
 	"signal-module/pkg/models"
+	"signal-module/pkg/queue"
 	"signal-module/pkg/utils"
 
 	"gorm.io/gorm"
@@ -32,17 +34,20 @@ type UserServiceInterface interface {
 type UserService struct {
 	userRepo   repositories.UserRepositoryInterface
 	jwtManager *utils.JWTManager
+	queue      *queue.Queue
 	logger     *logger.Logger
 }
 
 func NewUserService(
 	userRepo repositories.UserRepositoryInterface,
 	jwtManager *utils.JWTManager,
+	queue *queue.Queue,
 	logger *logger.Logger,
 ) UserServiceInterface {
 	return &UserService{
 		userRepo:   userRepo,
 		jwtManager: jwtManager,
+		queue:      queue,
 		logger:     logger,
 	}
 }
@@ -235,6 +240,9 @@ func (s *UserService) UpdateProfile(userID uint, req *models.UpdateProfileReques
 	user.Profile.Bio = req.Bio
 	user.Profile.Age = req.Age
 	user.Profile.Gender = req.Gender
+	if req.PresenceVisible != nil {
+		user.Profile.PresenceVisible = *req.PresenceVisible
+	}
 
 	if err := s.userRepo.Update(user); err != nil {
 		s.logger.Error("프로필 업데이트 실패", err)
@@ -327,6 +335,11 @@ func (s *UserService) RateUser(raterID uint, req *models.UserRating) error {
 
 	s.logger.Info(fmt.Sprintf("사용자 평가: %d -> %d, 점수 %d", raterID, req.RateeID, req.Score))
 
+	// 평가받은 사용자의 매너 점수 재계산 (실패해도 스케줄러의 전체 재계산에서 반영됨)
+	if err := s.queue.UpdateMannerScore(context.Background(), req.RateeID); err != nil {
+		s.logger.Warn(fmt.Sprintf("매너 점수 재계산 작업 추가 실패: %v", err))
+	}
+
 	return nil
 }
 
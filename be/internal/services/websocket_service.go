@@ -1,69 +1,226 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"signal-module/pkg/logger"
 	"signal-module/pkg/models"
 	"signal-module/pkg/redis"
+	"signal-module/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
+// 지도 WebSocket으로 보내는 변경 종류
+const (
+	signalUpdateCreated      = "created"
+	signalUpdateUpdated      = "updated"
+	signalUpdateRemoved      = "removed"
+	signalUpdateNotification = "notification" // 나에게 온 알림 (참여 요청, 주변 새 시그널 등)
+	signalUpdateError        = "error"
+
+	// 연결 직후 세션 안내
+	signalUpdateSession        = "session"         // 새 세션 (seq는 현재 이벤트 순번)
+	signalUpdateResumed        = "resumed"         // 끊긴 동안의 이벤트를 모두 다시 보냄
+	signalUpdateResyncRequired = "resync_required" // 이어받을 수 없음 (주변 시그널을 다시 조회)
+)
+
+const (
+	signalMaxViewports      = 5
+	signalMaxViewportRadius = 50000.0   // 미터
+	signalDefaultViewport   = "default" // location_update가 사용하는 영역
+
+	// 재연결 세션: 구독 영역을 보관하는 시간과 한 번에 다시 보낼 수 있는 최대 변경 수 (전송 버퍼보다 작게)
+	signalSessionTTL     = 10 * time.Minute
+	signalMaxReplayed    = 200
+	signalSendBufferSize = 256
+)
+
+func signalSessionKey(sessionID string) string { return "signal:ws:session:" + sessionID }
+
+// signalSession 재연결 시 복원할 구독 영역 (Redis에 보관되어 다른 인스턴스로 재연결해도 이어받음)
+type signalSession struct {
+	UserID     uint                      `json:"user_id"`
+	Viewports  []signalSessionViewport   `json:"viewports"`
+	Categories []models.InterestCategory `json:"categories,omitempty"`
+}
+
+type signalSessionViewport struct {
+	ID        string           `json:"id"`
+	Bounds    []LocationBounds `json:"bounds"`
+	CenterLat float64          `json:"center_lat"`
+	CenterLon float64          `json:"center_lon"`
+}
+
+// pendingSignalUpdate 재전송 중에 도착해 나중에 보낼 실시간 이벤트 (시그널 변경 또는 알림)
+type pendingSignalUpdate struct {
+	seq          int64
+	event        *models.SignalEvent
+	notification *models.UserNotification
+}
+
+// WebSocketService 지도 이벤트와 사용자 알림을 실시간으로 전달 (WebSocket과 SSE 연결이 같은 구독을 공유)
 type WebSocketService struct {
 	logger      *logger.Logger
 	redisClient *redis.Client
-	
-	// 연결된 클라이언트들을 위치별로 관리
-	locationClients map[string][]*SignalClient
-	mutex          sync.RWMutex
-	
+	presence    PresenceServiceInterface
+
+	// 연결된 클라이언트, 사용자별 연결과 구독 영역 인덱스 (클라이언트의 viewports도 이 뮤텍스로 보호)
+	clients map[*SignalClient]struct{}
+	users   map[uint]map[*SignalClient]struct{}
+	index   *spatialIndex
+	mutex   sync.RWMutex
+
 	upgrader websocket.Upgrader
 }
 
 type SignalClient struct {
-	ID       string
-	UserID   uint
-	Conn     *websocket.Conn
-	Location LocationBounds
-	Send     chan []byte
+	ID     string
+	UserID uint
+	Conn   *websocket.Conn // SSE 연결이면 nil
+	Send   chan *SignalUpdate
+
+	// 재연결 세션 ID (WebSocket)
+	SessionID string
+
+	// 영역 ID별 구독 영역 (날짜 변경선을 넘으면 둘)과 받을 카테고리 (비어 있으면 전체)
+	viewports  map[string][]*signalViewport
+	categories map[models.InterestCategory]struct{}
+
+	// 마지막으로 보낸 시그널 이벤트/알림 순번, 재전송 중 여부와 그동안 도착한 실시간 이벤트
+	streamSeq int64
+	notifySeq int64
+	replaying bool
+	pending   []pendingSignalUpdate
+
+	// 접속 상태 하트비트와 연결 종료 (전송 버퍼가 가득 찼거나 서버 종료 시)
+	presence   *PresenceConnection
+	disconnect func()
+	closeOnce  sync.Once
 }
 
-type LocationBounds struct {
-	MinLat float64
-	MaxLat float64
-	MinLon float64
-	MaxLon float64
+// viewportAt 지점을 포함하는 구독 영역 (없으면 nil)
+func (c *SignalClient) viewportAt(lat, lon float64) *signalViewport {
+	for _, parts := range c.viewports {
+		for _, v := range parts {
+			if v.bounds.contains(lat, lon) {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// wantsCategory 받기로 한 카테고리인지 확인
+func (c *SignalClient) wantsCategory(category models.InterestCategory) bool {
+	if len(c.categories) == 0 {
+		return true
+	}
+	_, ok := c.categories[category]
+	return ok
 }
 
 type SignalUpdate struct {
-	Type    string                   `json:"type"`
-	Signal  *models.SignalWithDistance `json:"signal,omitempty"`
-	Message string                   `json:"message,omitempty"`
+	Type            string                     `json:"type"`                       // created, updated, removed, notification
+	Reason          string                     `json:"reason,omitempty"`           // removed: cancelled, expired, moved
+	Seq             int64                      `json:"seq,omitempty"`              // 이벤트 순번 (재연결 시 resume_from으로 전달)
+	NotificationSeq int64                      `json:"notification_seq,omitempty"` // 알림 순번 (재연결 시 notify_from으로 전달)
+	SessionID       string                     `json:"session_id,omitempty"`
+	Signal          *models.SignalWithDistance `json:"signal,omitempty"`
+	Notification    *models.UserNotification   `json:"notification,omitempty"`
+	Message         string                     `json:"message,omitempty"`
+
+	// 보낸 시점의 두 순번 (SSE 이벤트 ID)
+	eventID string
 }
 
-func NewWebSocketService(logger *logger.Logger, redisClient *redis.Client) *WebSocketService {
+func NewWebSocketService(logger *logger.Logger, redisClient *redis.Client, presence PresenceServiceInterface, allowedOrigins []string) *WebSocketService {
 	return &WebSocketService{
-		logger:          logger,
-		redisClient:     redisClient,
-		locationClients: make(map[string][]*SignalClient),
+		logger:      logger,
+		redisClient: redisClient,
+		presence:    presence,
+		clients:     make(map[*SignalClient]struct{}),
+		users:       make(map[uint]map[*SignalClient]struct{}),
+		index:       newSpatialIndex(),
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true
-			},
+			CheckOrigin: newOriginChecker(allowedOrigins),
 		},
 	}
 }
 
+// Run 시그널 변경 이벤트와 사용자 알림을 구독해 이 인스턴스의 클라이언트에게 전달 (ctx가 끝날 때까지)
+func (ws *WebSocketService) Run(ctx context.Context) {
+	pubsub := ws.redisClient.SubscribeRealtimeEvents(ctx)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var sequenced redis.SequencedMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &sequenced); err != nil {
+				ws.logger.Error("실시간 이벤트 역직렬화 실패", err)
+				continue
+			}
+
+			switch msg.Channel {
+			case redis.SignalEventsChannel:
+				var event models.SignalEvent
+				if err := json.Unmarshal(sequenced.Event, &event); err != nil {
+					ws.logger.Error("시그널 이벤트 역직렬화 실패", err)
+					continue
+				}
+				ws.BroadcastSignalEvent(sequenced.Seq, &event)
+			case redis.UserNotificationsChannel:
+				var notification models.UserNotification
+				if err := json.Unmarshal(sequenced.Event, &notification); err != nil {
+					ws.logger.Error("사용자 알림 역직렬화 실패", err)
+					continue
+				}
+				ws.BroadcastUserNotification(sequenced.Seq, &notification)
+			}
+		}
+	}
+}
+
+// Shutdown 모든 클라이언트 연결 종료 (하이재킹된 WebSocket과 끝나지 않는 SSE 응답은 server.Shutdown이 정리하지 않음)
+func (ws *WebSocketService) Shutdown() {
+	ws.mutex.RLock()
+	defer ws.mutex.RUnlock()
+
+	deadline := time.Now().Add(time.Second)
+	for client := range ws.clients {
+		if client.Conn != nil {
+			client.Conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), deadline)
+		}
+		client.disconnect()
+	}
+}
+
 // HandleSignalWebSocket handles WebSocket connections for real-time signal updates
+//
+// 재연결할 때 session_id와 마지막으로 받은 seq를 resume_from으로(알림은 notification_seq를 notify_from으로) 보내면
+// 구독 영역을 복원하고 끊긴 동안의 변경을 다시 보낸다.
 func (ws *WebSocketService) HandleSignalWebSocket(c *gin.Context) {
 	userID := c.GetUint("user_id")
-	
+
 	conn, err := ws.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		ws.logger.Error("WebSocket 업그레이드 실패", err)
@@ -71,10 +228,27 @@ func (ws *WebSocketService) HandleSignalWebSocket(c *gin.Context) {
 	}
 
 	client := &SignalClient{
-		ID:     fmt.Sprintf("user_%d_%d", userID, time.Now().UnixNano()),
-		UserID: userID,
-		Conn:   conn,
-		Send:   make(chan []byte, 256),
+		ID:         fmt.Sprintf("user_%d_%d", userID, time.Now().UnixNano()),
+		UserID:     userID,
+		Conn:       conn,
+		Send:       make(chan *SignalUpdate, signalSendBufferSize),
+		viewports:  make(map[string][]*signalViewport),
+		categories: parseCategories(c.Query("categories")),
+		presence:   ws.presence.Connect(userID, c.Query("device_id")),
+		disconnect: func() { conn.Close() },
+	}
+
+	resumeFrom, err := strconv.ParseInt(c.Query("resume_from"), 10, 64)
+	if sessionID := c.Query("session_id"); sessionID != "" && err == nil && resumeFrom >= 0 {
+		notifyFrom, err := strconv.ParseInt(c.Query("notify_from"), 10, 64)
+		if err != nil || notifyFrom < 0 {
+			notifyFrom = -1
+		}
+		ws.resumeSession(client, sessionID, resumeFrom, notifyFrom)
+	} else {
+		client.SessionID = newSignalSessionID()
+		ws.attachClient(client, nil)
+		ws.startLive(client, signalUpdateSession)
 	}
 
 	ws.logger.Info(fmt.Sprintf("WebSocket 연결: 사용자 %d", userID))
@@ -84,10 +258,214 @@ func (ws *WebSocketService) HandleSignalWebSocket(c *gin.Context) {
 	go ws.writeHandler(client)
 }
 
+// attachClient 구독 영역과 함께 클라이언트 등록
+//
+// 전달을 시작할 순번이 정해질 때까지(startLive 또는 replayMissed) 도착한 실시간 이벤트는 pending에 모은다.
+func (ws *WebSocketService) attachClient(client *SignalClient, viewports []signalSessionViewport) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	client.replaying = true
+	for _, saved := range viewports {
+		ws.insertViewportLocked(client, saved.ID, saved.Bounds, saved.CenterLat, saved.CenterLon)
+	}
+
+	ws.clients[client] = struct{}{}
+	if ws.users[client.UserID] == nil {
+		ws.users[client.UserID] = make(map[*SignalClient]struct{})
+	}
+	ws.users[client.UserID][client] = struct{}{}
+}
+
+// startLive 현재 순번 이후의 이벤트부터 전달하고 notice(session 또는 resync_required)로 기준 순번 안내
+func (ws *WebSocketService) startLive(client *SignalClient, notice string) {
+	ctx := context.Background()
+
+	signalLatest, err := ws.redisClient.StreamLatest(ctx, redis.SignalEventsChannel)
+	if err != nil {
+		ws.logger.Warn(fmt.Sprintf("시그널 이벤트 순번 조회 실패: %v", err))
+	}
+	notifyLatest, err := ws.redisClient.StreamLatest(ctx, redis.UserNotificationsChannel)
+	if err != nil {
+		ws.logger.Warn(fmt.Sprintf("사용자 알림 순번 조회 실패: %v", err))
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	client.streamSeq = signalLatest
+	client.notifySeq = notifyLatest
+	ws.flushPendingLocked(client)
+	ws.sendLocked(client, &SignalUpdate{Type: notice, SessionID: client.SessionID, Seq: signalLatest, NotificationSeq: notifyLatest})
+}
+
+// resumeSession 저장된 구독 영역을 복원하고 resume_from 이후의 변경을 다시 보냄 (notifyFrom이 음수면 알림은 새로 시작)
+func (ws *WebSocketService) resumeSession(client *SignalClient, sessionID string, resumeFrom, notifyFrom int64) {
+	ctx := context.Background()
+
+	session := ws.loadSession(ctx, sessionID, client.UserID)
+	if session == nil {
+		client.SessionID = newSignalSessionID()
+		ws.attachClient(client, nil)
+		ws.startLive(client, signalUpdateResyncRequired)
+		return
+	}
+
+	client.SessionID = sessionID
+	if client.categories == nil {
+		client.categories = categorySet(session.Categories)
+	}
+	ws.attachClient(client, session.Viewports)
+
+	if notifyFrom < 0 {
+		latest, err := ws.redisClient.StreamLatest(ctx, redis.UserNotificationsChannel)
+		if err != nil {
+			ws.logger.Warn(fmt.Sprintf("사용자 알림 순번 조회 실패: %v", err))
+		}
+		notifyFrom = latest
+	}
+
+	result, replayed := ws.replayMissed(client, resumeFrom, notifyFrom)
+
+	ws.logger.Info(fmt.Sprintf("시그널 WebSocket 세션 이어받기: 사용자 %d, %s (재전송 %d건)", client.UserID, result, replayed))
+}
+
+// replayMissed 끊긴 동안의 시그널 변경과 알림을 다시 보낸 뒤 보류한 실시간 이벤트를 이어서 전송
+//
+// 클라이언트는 attachClient로 등록된 상태여야 하며, 이 클라이언트의 영역과 카테고리에 해당하는 변경과
+// 이 사용자에게 온 알림만 골라 보낸다. 빠진 구간이 있거나 너무 많으면 resync_required로 다시 조회하도록 안내한다.
+func (ws *WebSocketService) replayMissed(client *SignalClient, signalFrom, notifyFrom int64) (string, int) {
+	ctx := context.Background()
+
+	signalReplay, signalErr := ws.redisClient.ReplayStream(ctx, redis.SignalEventsChannel, signalFrom)
+	if signalErr != nil {
+		ws.logger.Warn(fmt.Sprintf("시그널 재전송 버퍼 조회 실패: %v", signalErr))
+	}
+	notifyReplay, notifyErr := ws.redisClient.ReplayStream(ctx, redis.UserNotificationsChannel, notifyFrom)
+	if notifyErr != nil {
+		ws.logger.Warn(fmt.Sprintf("사용자 알림 재전송 버퍼 조회 실패: %v", notifyErr))
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	client.streamSeq = signalFrom
+	client.notifySeq = notifyFrom
+
+	var updates []*SignalUpdate
+	complete := signalErr == nil && signalReplay.Complete && notifyErr == nil && notifyReplay.Complete
+	if complete {
+		for _, message := range signalReplay.Messages {
+			var event models.SignalEvent
+			if err := json.Unmarshal(message.Event, &event); err != nil {
+				continue
+			}
+			if update := ws.updateForClient(&event, client); update != nil {
+				update.Seq = message.Seq
+				updates = append(updates, update)
+			}
+		}
+		for _, message := range notifyReplay.Messages {
+			var notification models.UserNotification
+			if err := json.Unmarshal(message.Event, &notification); err != nil || !notification.IsFor(client.UserID) {
+				continue
+			}
+			updates = append(updates, notificationUpdate(message.Seq, &notification))
+		}
+		complete = len(updates) <= signalMaxReplayed
+	}
+
+	if complete {
+		for _, update := range updates {
+			ws.sendLocked(client, update)
+		}
+	}
+
+	// 이어받지 못했더라도 이후 실시간 이벤트는 현재 순번 다음부터
+	if signalReplay != nil && signalReplay.Latest > client.streamSeq {
+		client.streamSeq = signalReplay.Latest
+	}
+	if notifyReplay != nil && notifyReplay.Latest > client.notifySeq {
+		client.notifySeq = notifyReplay.Latest
+	}
+	ws.flushPendingLocked(client)
+
+	result := signalUpdateResumed
+	if !complete {
+		result = signalUpdateResyncRequired
+		updates = nil
+	}
+	ws.sendLocked(client, &SignalUpdate{Type: result, SessionID: client.SessionID, Seq: client.streamSeq, NotificationSeq: client.notifySeq})
+
+	return result, len(updates)
+}
+
+// flushPendingLocked 보류한 실시간 이벤트 중 이미 보낸 순번 이후의 것만 전송하고 실시간 전달 시작
+func (ws *WebSocketService) flushPendingLocked(client *SignalClient) {
+	client.replaying = false
+	for _, pending := range client.pending {
+		if pending.notification != nil {
+			ws.deliverNotificationLocked(client, pending.seq, pending.notification)
+		} else {
+			ws.deliverLocked(client, pending.seq, pending.event)
+		}
+	}
+	client.pending = nil
+}
+
+func (ws *WebSocketService) loadSession(ctx context.Context, sessionID string, userID uint) *signalSession {
+	data, err := ws.redisClient.Get(ctx, signalSessionKey(sessionID))
+	if err != nil {
+		return nil
+	}
+
+	var session signalSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil || session.UserID != userID {
+		return nil
+	}
+	return &session
+}
+
+// saveSession 현재 구독 영역을 세션에 저장 (영역이 바뀔 때와 연결이 끊길 때)
+func (ws *WebSocketService) saveSession(client *SignalClient) {
+	ws.mutex.RLock()
+	session := signalSession{UserID: client.UserID}
+	for category := range client.categories {
+		session.Categories = append(session.Categories, category)
+	}
+	for id, parts := range client.viewports {
+		saved := signalSessionViewport{ID: id}
+		for _, v := range parts {
+			saved.Bounds = append(saved.Bounds, v.bounds)
+			saved.CenterLat, saved.CenterLon = v.centerLat, v.centerLon
+		}
+		session.Viewports = append(session.Viewports, saved)
+	}
+	ws.mutex.RUnlock()
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+	if err := ws.redisClient.Set(context.Background(), signalSessionKey(client.SessionID), data, signalSessionTTL); err != nil {
+		ws.logger.Warn(fmt.Sprintf("시그널 WebSocket 세션 저장 실패: %v", err))
+	}
+}
+
+func newSignalSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 // handleClient handles incoming messages from client
 func (ws *WebSocketService) handleClient(client *SignalClient) {
 	defer func() {
+		ws.saveSession(client)
 		ws.removeClient(client)
+		client.presence.Close()
 		client.Conn.Close()
 	}()
 
@@ -108,10 +486,19 @@ func (ws *WebSocketService) handleClient(client *SignalClient) {
 			break
 		}
 
-		// 위치 업데이트 처리
-		if msgType, ok := msg["type"].(string); ok && msgType == "location_update" {
+		msgType, _ := msg["type"].(string)
+		switch msgType {
+		case "location_update":
 			ws.handleLocationUpdate(client, msg)
+		case "viewport_update":
+			ws.handleViewportUpdate(client, msg)
+		case "viewport_remove":
+			id, _ := msg["viewport_id"].(string)
+			ws.removeViewport(client, id)
+		default:
+			continue
 		}
+		ws.saveSession(client)
 	}
 }
 
@@ -132,7 +519,7 @@ func (ws *WebSocketService) writeHandler(client *SignalClient) {
 				return
 			}
 
-			if err := client.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			if err := client.Conn.WriteJSON(message); err != nil {
 				return
 			}
 
@@ -145,7 +532,7 @@ func (ws *WebSocketService) writeHandler(client *SignalClient) {
 	}
 }
 
-// handleLocationUpdate handles location updates from client
+// handleLocationUpdate handles location updates from client (기본 영역을 중심과 반경으로 교체)
 func (ws *WebSocketService) handleLocationUpdate(client *SignalClient, msg map[string]interface{}) {
 	lat, latOK := msg["latitude"].(float64)
 	lon, lonOK := msg["longitude"].(float64)
@@ -156,100 +543,298 @@ func (ws *WebSocketService) handleLocationUpdate(client *SignalClient, msg map[s
 		return
 	}
 
-	// 위치 경계 계산 (대략적인 lat/lon 변환)
-	latDelta := radius / 111320.0 // 1도 = 약 111.32km
-	lonDelta := radius / (111320.0 * 0.866) // 보정 계수
+	ws.setRadiusViewport(client, signalDefaultViewport, lat, lon, radius)
+}
 
-	client.Location = LocationBounds{
-		MinLat: lat - latDelta,
-		MaxLat: lat + latDelta,
-		MinLon: lon - lonDelta,
-		MaxLon: lon + lonDelta,
+// handleViewportUpdate 영역 ID별 구독 영역 추가/교체
+//
+// 중심과 반경(latitude, longitude, radius) 또는 지도 화면 경계(min_lat, max_lat, min_lon, max_lon)로 지정하며,
+// 화면 경계가 날짜 변경선을 넘으면 min_lon이 max_lon보다 크다.
+func (ws *WebSocketService) handleViewportUpdate(client *SignalClient, msg map[string]interface{}) {
+	id, _ := msg["viewport_id"].(string)
+	if id == "" || len(id) > 32 {
+		ws.sendError(client, "viewport_id가 필요합니다")
+		return
 	}
 
-	// 위치별 클라이언트 그룹에 추가
-	locationKey := ws.getLocationKey(lat, lon)
-	ws.addClientToLocation(client, locationKey)
+	bounds, centerLat, centerLon, ok := parseViewport(func(key string) (float64, bool) {
+		value, ok := msg[key].(float64)
+		return value, ok
+	})
+	if !ok {
+		ws.sendError(client, "잘못된 영역입니다")
+		return
+	}
 
-	ws.logger.Info(fmt.Sprintf("사용자 %d 위치 업데이트: %.6f, %.6f (반경: %.0fm)", client.UserID, lat, lon, radius))
+	ws.setViewport(client, id, bounds, centerLat, centerLon)
 }
 
-// BroadcastSignalUpdate broadcasts signal updates to relevant clients
-func (ws *WebSocketService) BroadcastSignalUpdate(signal *models.SignalWithDistance, updateType string) {
-	update := SignalUpdate{
-		Type:   updateType,
-		Signal: signal,
+// parseViewport 중심과 반경(latitude, longitude, radius) 또는 화면 경계(min_lat, max_lat, min_lon, max_lon)로
+// 지정한 구독 영역과 그 중심 (날짜 변경선을 넘는 영역은 둘로 나뉨)
+func parseViewport(value func(key string) (float64, bool)) ([]LocationBounds, float64, float64, bool) {
+	if radius, ok := value("radius"); ok {
+		lat, latOK := value("latitude")
+		lon, lonOK := value("longitude")
+		if !latOK || !lonOK || lat < -90 || lat > 90 || lon < -180 || lon > 180 ||
+			radius <= 0 || radius > signalMaxViewportRadius {
+			return nil, 0, 0, false
+		}
+		return radiusBounds(lat, lon, radius), lat, lon, true
 	}
 
-	data, err := json.Marshal(update)
-	if err != nil {
-		ws.logger.Error("SignalUpdate JSON 마샬링 실패", err)
+	minLat, ok1 := value("min_lat")
+	maxLat, ok2 := value("max_lat")
+	minLon, ok3 := value("min_lon")
+	maxLon, ok4 := value("max_lon")
+	if !ok1 || !ok2 || !ok3 || !ok4 || minLat > maxLat || minLat < -90 || maxLat > 90 ||
+		minLon < -180 || minLon > 180 || maxLon < -180 || maxLon > 180 {
+		return nil, 0, 0, false
+	}
+
+	bounds := LocationBounds{MinLat: minLat, MaxLat: maxLat, MinLon: minLon, MaxLon: maxLon}
+	centerLon := (minLon + maxLon) / 2
+	if minLon > maxLon {
+		centerLon = math.Remainder(centerLon+180, 360)
+	}
+	return splitAntimeridian(bounds), (minLat + maxLat) / 2, centerLon, true
+}
+
+// parseCategories 쉼표로 구분한 카테고리 목록 (비어 있으면 nil, 전체 수신)
+func parseCategories(value string) map[models.InterestCategory]struct{} {
+	var categories []models.InterestCategory
+	for _, category := range strings.Split(value, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, models.InterestCategory(category))
+		}
+	}
+	return categorySet(categories)
+}
+
+func categorySet(categories []models.InterestCategory) map[models.InterestCategory]struct{} {
+	if len(categories) == 0 {
+		return nil
+	}
+	set := make(map[models.InterestCategory]struct{}, len(categories))
+	for _, category := range categories {
+		set[category] = struct{}{}
+	}
+	return set
+}
+
+func (ws *WebSocketService) setRadiusViewport(client *SignalClient, id string, lat, lon, radius float64) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 || radius <= 0 || radius > signalMaxViewportRadius {
+		ws.sendError(client, "잘못된 영역입니다")
 		return
 	}
 
+	ws.setViewport(client, id, radiusBounds(lat, lon, radius), lat, lon)
+
+	ws.logger.Info(fmt.Sprintf("사용자 %d 위치 업데이트: %.6f, %.6f (반경: %.0fm)", client.UserID, lat, lon, radius))
+}
+
+// setViewport 영역 ID의 구독 영역을 교체 (연결당 최대 signalMaxViewports개)
+func (ws *WebSocketService) setViewport(client *SignalClient, id string, bounds []LocationBounds, centerLat, centerLon float64) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if _, exists := client.viewports[id]; !exists && len(client.viewports) >= signalMaxViewports {
+		ws.sendErrorLocked(client, fmt.Sprintf("영역은 최대 %d개까지 구독할 수 있습니다", signalMaxViewports))
+		return
+	}
+
+	ws.insertViewportLocked(client, id, bounds, centerLat, centerLon)
+}
+
+func (ws *WebSocketService) insertViewportLocked(client *SignalClient, id string, bounds []LocationBounds, centerLat, centerLon float64) {
+	for _, v := range client.viewports[id] {
+		ws.index.remove(v)
+	}
+
+	parts := make([]*signalViewport, 0, len(bounds))
+	for _, b := range bounds {
+		v := &signalViewport{client: client, id: id, bounds: b, centerLat: centerLat, centerLon: centerLon}
+		ws.index.insert(v)
+		parts = append(parts, v)
+	}
+	client.viewports[id] = parts
+}
+
+func (ws *WebSocketService) removeViewport(client *SignalClient, id string) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	for _, v := range client.viewports[id] {
+		ws.index.remove(v)
+	}
+	delete(client.viewports, id)
+}
+
+func (ws *WebSocketService) sendError(client *SignalClient, message string) {
 	ws.mutex.RLock()
 	defer ws.mutex.RUnlock()
 
-	// 모든 위치 그룹에서 해당 시그널과 관련된 클라이언트들에게 브로드캐스트
-	for _, clients := range ws.locationClients {
-		for _, client := range clients {
-			if ws.isSignalInBounds(signal.Signal, client.Location) {
-				select {
-				case client.Send <- data:
-				default:
-					// 채널이 막혀있으면 클라이언트 제거
-					ws.removeClient(client)
-				}
-			}
-		}
+	ws.sendErrorLocked(client, message)
+}
+
+// sendErrorLocked 오류 메시지 전송 (뮤텍스를 잡은 상태에서 호출)
+func (ws *WebSocketService) sendErrorLocked(client *SignalClient, message string) {
+	ws.sendLocked(client, &SignalUpdate{Type: signalUpdateError, Message: message})
+}
+
+// sendLocked 뮤텍스를 잡은 상태에서 전송하고 보낸 순번 기록 (버퍼가 가득 찬 클라이언트는 연결 종료, 연결 goroutine이 정리)
+func (ws *WebSocketService) sendLocked(client *SignalClient, update *SignalUpdate) bool {
+	if update.Seq > client.streamSeq {
+		client.streamSeq = update.Seq
 	}
+	if update.NotificationSeq > client.notifySeq {
+		client.notifySeq = update.NotificationSeq
+	}
+	update.eventID = formatSignalEventID(client.streamSeq, client.notifySeq)
+
+	select {
+	case client.Send <- update:
+		return true
+	default:
+		ws.logger.Warn(fmt.Sprintf("시그널 실시간 전송 지연으로 연결 종료: 사용자 %d", client.UserID))
+		client.disconnect()
+		return false
+	}
+}
+
+// deliverLocked 실시간 이벤트를 클라이언트 기준 메시지로 바꿔 전송 (재전송 중이면 보류, 이미 보낸 순번은 건너뜀)
+func (ws *WebSocketService) deliverLocked(client *SignalClient, seq int64, event *models.SignalEvent) bool {
+	if client.replaying {
+		client.pending = append(client.pending, pendingSignalUpdate{seq: seq, event: event})
+		return false
+	}
+	if seq != 0 && seq <= client.streamSeq {
+		return false
+	}
+
+	update := ws.updateForClient(event, client)
+	if update == nil {
+		return false
+	}
+	update.Seq = seq
+	return ws.sendLocked(client, update)
+}
 
-	ws.logger.Info(fmt.Sprintf("시그널 업데이트 브로드캐스트: %s (ID: %d)", updateType, signal.Signal.ID))
+// deliverNotificationLocked 알림 전송 (재전송 중이면 보류, 이미 보낸 순번은 건너뜀)
+func (ws *WebSocketService) deliverNotificationLocked(client *SignalClient, seq int64, notification *models.UserNotification) bool {
+	if client.replaying {
+		client.pending = append(client.pending, pendingSignalUpdate{seq: seq, notification: notification})
+		return false
+	}
+	if seq != 0 && seq <= client.notifySeq {
+		return false
+	}
+	return ws.sendLocked(client, notificationUpdate(seq, notification))
 }
 
-// Helper methods
-func (ws *WebSocketService) getLocationKey(lat, lon float64) string {
-	// 위치를 그리드로 반올림하여 키 생성 (1km 그리드)
-	gridLat := int(lat * 100) // 0.01도 = 약 1km
-	gridLon := int(lon * 100)
-	return fmt.Sprintf("grid_%d_%d", gridLat, gridLon)
+// notificationUpdate 대상 목록을 뺀 알림 메시지
+func notificationUpdate(seq int64, notification *models.UserNotification) *SignalUpdate {
+	forClient := *notification
+	forClient.UserIDs = nil
+	return &SignalUpdate{Type: signalUpdateNotification, NotificationSeq: seq, Notification: &forClient}
 }
 
-func (ws *WebSocketService) addClientToLocation(client *SignalClient, locationKey string) {
+// BroadcastSignalEvent 시그널 변경을 보고 있는 영역에 해당하는 클라이언트에게 전달
+//
+// 인덱스에서 시그널 위치(위치가 바뀌었으면 이전 위치도)를 포함하는 영역만 찾으므로 전체 클라이언트를 훑지 않는다.
+// 같은 이벤트라도 클라이언트마다 created/updated/removed가 다를 수 있다.
+// (예: 위치가 바뀐 시그널은 새 위치를 보는 클라이언트에게는 created, 이전 위치만 보던 클라이언트에게는 removed)
+func (ws *WebSocketService) BroadcastSignalEvent(seq int64, event *models.SignalEvent) {
+	delivered := 0
+
+	// 재전송 중인 클라이언트의 pending을 바꾸므로 쓰기 잠금 (브로드캐스트는 Run goroutine 하나에서만 실행)
 	ws.mutex.Lock()
-	defer ws.mutex.Unlock()
+	targets := make(map[*SignalClient]struct{})
+	collect := func(v *signalViewport) { targets[v.client] = struct{}{} }
+	ws.index.query(event.Signal.Latitude, event.Signal.Longitude, collect)
+	if event.PrevLatitude != nil && event.PrevLongitude != nil {
+		ws.index.query(*event.PrevLatitude, *event.PrevLongitude, collect)
+	}
 
-	// 기존 위치에서 제거
-	ws.removeClientFromAllLocations(client)
+	for client := range targets {
+		if ws.deliverLocked(client, seq, event) {
+			delivered++
+		}
+	}
+	ws.mutex.Unlock()
 
-	// 새 위치에 추가
-	ws.locationClients[locationKey] = append(ws.locationClients[locationKey], client)
+	if delivered > 0 {
+		ws.logger.Info(fmt.Sprintf("시그널 업데이트 브로드캐스트: %s (ID: %d, 클라이언트 %d명)", event.Kind, event.Signal.ID, delivered))
+	}
 }
 
-func (ws *WebSocketService) removeClient(client *SignalClient) {
+// BroadcastUserNotification 이 인스턴스에 연결된 대상 사용자에게 알림 전달
+func (ws *WebSocketService) BroadcastUserNotification(seq int64, notification *models.UserNotification) {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
-	ws.removeClientFromAllLocations(client)
-	close(client.Send)
+	for _, userID := range notification.UserIDs {
+		for client := range ws.users[userID] {
+			ws.deliverNotificationLocked(client, seq, notification)
+		}
+	}
 }
 
-func (ws *WebSocketService) removeClientFromAllLocations(client *SignalClient) {
-	for locationKey, clients := range ws.locationClients {
-		for i, c := range clients {
-			if c.ID == client.ID {
-				ws.locationClients[locationKey] = append(clients[:i], clients[i+1:]...)
-				break
-			}
+// updateForClient 클라이언트가 보고 있는 영역 기준으로 보낼 메시지 결정 (보낼 필요가 없으면 nil)
+func (ws *WebSocketService) updateForClient(event *models.SignalEvent, client *SignalClient) *SignalUpdate {
+	signal := &event.Signal
+	if !client.wantsCategory(signal.Category) {
+		return nil
+	}
+	viewport := client.viewportAt(signal.Latitude, signal.Longitude)
+	inBounds := viewport != nil
+	wasInBounds := event.PrevLatitude != nil && event.PrevLongitude != nil &&
+		client.viewportAt(*event.PrevLatitude, *event.PrevLongitude) != nil
+
+	// 거리는 시그널을 포함하는 영역의 중심 기준
+	update := &SignalUpdate{Signal: &models.SignalWithDistance{Signal: *signal}}
+	if inBounds {
+		update.Signal.Distance = utils.CalculateDistance(viewport.centerLat, viewport.centerLon, signal.Latitude, signal.Longitude)
+	}
+
+	switch {
+	case event.IsRemoval():
+		if !inBounds {
+			return nil
 		}
-		// 빈 위치 그룹 정리
-		if len(ws.locationClients[locationKey]) == 0 {
-			delete(ws.locationClients, locationKey)
+		update.Type = signalUpdateRemoved
+		update.Reason = string(event.Kind)
+	case inBounds:
+		update.Type = signalUpdateUpdated
+		// 새로 생겼거나 다른 곳에서 옮겨 온 시그널
+		if event.Kind == models.SignalEventCreated || (event.PrevLatitude != nil && !wasInBounds) {
+			update.Type = signalUpdateCreated
 		}
+	case wasInBounds:
+		update.Type = signalUpdateRemoved
+		update.Reason = "moved"
+	default:
+		return nil
 	}
+
+	return update
 }
 
-func (ws *WebSocketService) isSignalInBounds(signal models.Signal, bounds LocationBounds) bool {
-	return signal.Latitude >= bounds.MinLat && signal.Latitude <= bounds.MaxLat &&
-		   signal.Longitude >= bounds.MinLon && signal.Longitude <= bounds.MaxLon
-}
\ No newline at end of file
+func (ws *WebSocketService) removeClient(client *SignalClient) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	for _, parts := range client.viewports {
+		for _, v := range parts {
+			ws.index.remove(v)
+		}
+	}
+	client.viewports = nil
+	delete(ws.clients, client)
+	if conns := ws.users[client.UserID]; conns != nil {
+		delete(conns, client)
+		if len(conns) == 0 {
+			delete(ws.users, client.UserID)
+		}
+	}
+	client.closeOnce.Do(func() { close(client.Send) })
+}
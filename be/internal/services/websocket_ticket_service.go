@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"signal-be/internal/repositories"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/redis"
+)
+
+// WebSocket 연결 티켓 유효 시간
+const wsTicketTTL = 30 * time.Second
+
+type WebSocketTicketServiceInterface interface {
+	Issue(userID uint, email, username string, req *models.WSTicketRequest) (*models.WSTicketResponse, error)
+	Redeem(ticket, target string) (*models.WSTicketClaims, error)
+	CheckUser(userID uint) error
+}
+
+// WebSocketTicketService WebSocket 연결 티켓 발급/확인
+//
+// 브라우저와 일부 모바일 WebSocket 라이브러리는 Authorization 헤더를 보낼 수 없으므로,
+// 인증된 API로 연결 대상에 묶인 일회용 티켓을 받아 업그레이드 요청의 쿼리로 전달한다.
+type WebSocketTicketService struct {
+	redisClient *redis.Client
+	userRepo    repositories.UserRepositoryInterface
+	logger      *logger.Logger
+}
+
+func NewWebSocketTicketService(redisClient *redis.Client, userRepo repositories.UserRepositoryInterface, logger *logger.Logger) WebSocketTicketServiceInterface {
+	return &WebSocketTicketService{
+		redisClient: redisClient,
+		userRepo:    userRepo,
+		logger:      logger,
+	}
+}
+
+func wsTicketKey(ticket string) string {
+	return "ws:ticket:" + ticket
+}
+
+func (s *WebSocketTicketService) Issue(userID uint, email, username string, req *models.WSTicketRequest) (*models.WSTicketResponse, error) {
+	if req.Stream == models.WSStreamChat && req.RoomID == "" {
+		return nil, fmt.Errorf("채팅방 ID가 필요합니다")
+	}
+	if err := s.CheckUser(userID); err != nil {
+		return nil, err
+	}
+
+	ticket, err := randomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("티켓 생성 실패: %w", err)
+	}
+
+	data, err := json.Marshal(&models.WSTicketClaims{
+		UserID:   userID,
+		Email:    email,
+		Username: username,
+		Target:   models.WSTicketTarget(req.Stream, req.RoomID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if err := s.redisClient.Set(ctx, wsTicketKey(ticket), data, wsTicketTTL); err != nil {
+		s.logger.Error("WebSocket 티켓 저장 실패", err)
+		return nil, fmt.Errorf("티켓 발급에 실패했습니다")
+	}
+
+	return &models.WSTicketResponse{
+		Ticket:    ticket,
+		ExpiresAt: time.Now().Add(wsTicketTTL),
+	}, nil
+}
+
+// Redeem 티켓을 사용 처리하고 연결 대상이 일치하면 묶인 사용자 반환
+func (s *WebSocketTicketService) Redeem(ticket, target string) (*models.WSTicketClaims, error) {
+	data, found, err := s.redisClient.GetDel(context.Background(), wsTicketKey(ticket))
+	if err != nil {
+		s.logger.Error("WebSocket 티켓 조회 실패", err)
+		return nil, fmt.Errorf("티켓을 확인할 수 없습니다")
+	}
+	if !found {
+		return nil, fmt.Errorf("만료되었거나 이미 사용된 티켓입니다")
+	}
+
+	var claims models.WSTicketClaims
+	if err := json.Unmarshal([]byte(data), &claims); err != nil {
+		return nil, fmt.Errorf("유효하지 않은 티켓입니다")
+	}
+	if claims.Target != target {
+		return nil, fmt.Errorf("다른 연결 대상에 발급된 티켓입니다")
+	}
+
+	return &claims, nil
+}
+
+// CheckUser 연결 시점에 계정이 활성 상태이고 차단되지 않았는지 확인
+func (s *WebSocketTicketService) CheckUser(userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("사용자를 찾을 수 없습니다")
+	}
+	if !user.IsActive || user.IsBlocked {
+		return fmt.Errorf("이용이 제한된 계정입니다")
+	}
+	return nil
+}
+
+// newOriginChecker 허용 목록에 있는 Origin만 WebSocket 업그레이드 허용
+//
+// Origin 헤더가 없는 요청은 브라우저가 아닌 네이티브 클라이언트이므로 허용한다 (인증은 별도로 확인).
+func newOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if normalized, ok := normalizeOrigin(origin); ok {
+			allowed[normalized] = struct{}{}
+		}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		normalized, ok := normalizeOrigin(origin)
+		if !ok {
+			return false
+		}
+		_, ok = allowed[normalized]
+		return ok
+	}
+}
+
+func normalizeOrigin(origin string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", false
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), true
+}
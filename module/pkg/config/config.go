@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +18,9 @@ type Config struct {
 	Push     PushConfig
 	Location LocationConfig
 	OAuth    OAuthConfig
+	Storage  StorageConfig
+	Chat     ChatConfig
+	Worker   WorkerConfig
 }
 
 type DatabaseConfig struct {
@@ -32,9 +37,19 @@ type JWTConfig struct {
 }
 
 type ServerConfig struct {
-	Port        string
-	Mode        string
-	FrontendURL string
+	Port           string
+	Mode           string
+	FrontendURL    string
+	AllowedOrigins []string // CORS 및 WebSocket 연결을 허용할 Origin
+	AdminUserIDs   []uint   // 관리자 API(/admin)를 사용할 수 있는 사용자
+}
+
+// Origins 브라우저 요청을 허용할 Origin (ALLOWED_ORIGINS가 없으면 프론트엔드 주소와 로컬 개발 서버)
+func (c ServerConfig) Origins() []string {
+	if len(c.AllowedOrigins) > 0 {
+		return c.AllowedOrigins
+	}
+	return []string{c.FrontendURL, "http://localhost:3000"}
 }
 
 type RedisConfig struct {
@@ -56,6 +71,42 @@ type LocationConfig struct {
 	MaxRadius     float64 // 최대 검색 반경 (미터)
 }
 
+// StorageConfig 첨부파일 저장소 설정 (Driver: local 또는 s3)
+type StorageConfig struct {
+	Driver    string
+	LocalPath string
+
+	S3Endpoint     string // 예: https://s3.ap-northeast-2.amazonaws.com, http://minio:9000
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UsePathStyle bool // MinIO 등 S3 호환 저장소는 보통 path-style 사용
+}
+
+// ChatConfig 채팅 모더레이션 설정
+type ChatConfig struct {
+	ProfanityFilter    string   // mask(가림), reject(전송 거부), off
+	LinkFilter         string   // allow, mask, reject
+	AllowedLinkDomains []string // LinkFilter와 관계없이 허용할 도메인 (하위 도메인 포함)
+	BannedWords        []string // 기본 금칙어 목록에 추가할 단어
+
+	RateLimitCount  int           // RateLimitWindow 동안 한 사용자가 보낼 수 있는 메시지 수
+	RateLimitWindow time.Duration
+
+	// 신고가 접수된 채팅방은 파기할 때 대화를 암호화해 보관
+	ArchiveKey       string        // base64 인코딩된 32바이트 AES 키 (워커 실행에 필수)
+	ArchiveRetention time.Duration // 보관 기간
+}
+
+// WorkerConfig 백그라운드 워커 실행 설정 (작업 타입 이름으로 지정, 없는 타입은 기본값)
+type WorkerConfig struct {
+	Concurrency     map[string]int     // 작업 타입별 동시 처리 개수
+	RateLimits      map[string]float64 // 작업 타입별 초당 최대 처리 개수 (외부 API 할당량 등)
+	MaxConcurrency  int                // 전체 동시 처리 상한 (부족하면 우선순위가 높은 작업부터)
+	ShutdownTimeout time.Duration      // 종료 시 처리 중 작업을 기다리는 시간 (지나면 큐로 되돌림)
+}
+
 type OAuthConfig struct {
 	Google GoogleConfig
 }
@@ -86,9 +137,11 @@ func LoadConfig() *Config {
 			Secret: getEnv("JWT_SECRET", "signal-super-secret-jwt-key"),
 		},
 		Server: ServerConfig{
-			Port:        getEnv("PORT", "8080"),
-			Mode:        getEnv("GIN_MODE", "debug"),
-			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+			Port:           getEnv("PORT", "8080"),
+			Mode:           getEnv("GIN_MODE", "debug"),
+			FrontendURL:    getEnv("FRONTEND_URL", "http://localhost:3000"),
+			AllowedOrigins: getEnvAsList("ALLOWED_ORIGINS"),
+			AdminUserIDs:   getEnvAsUintList("ADMIN_USER_IDS"),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -113,6 +166,32 @@ func LoadConfig() *Config {
 				RedirectURL:  getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/v1/auth/google/callback"),
 			},
 		},
+		Storage: StorageConfig{
+			Driver:         getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:      getEnv("STORAGE_LOCAL_PATH", "./data/attachments"),
+			S3Endpoint:     getEnv("S3_ENDPOINT", ""),
+			S3Region:       getEnv("S3_REGION", "ap-northeast-2"),
+			S3Bucket:       getEnv("S3_BUCKET", ""),
+			S3AccessKey:    getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:    getEnv("S3_SECRET_KEY", ""),
+			S3UsePathStyle: getEnvAsBool("S3_USE_PATH_STYLE", false),
+		},
+		Chat: ChatConfig{
+			ProfanityFilter:    getEnv("CHAT_PROFANITY_FILTER", "mask"),
+			LinkFilter:         getEnv("CHAT_LINK_FILTER", "allow"),
+			AllowedLinkDomains: getEnvAsList("CHAT_ALLOWED_LINK_DOMAINS"),
+			BannedWords:        getEnvAsList("CHAT_BANNED_WORDS"),
+			RateLimitCount:     getEnvAsInt("CHAT_RATE_LIMIT_COUNT", 10),
+			RateLimitWindow:    time.Duration(getEnvAsInt("CHAT_RATE_LIMIT_WINDOW_SECONDS", 10)) * time.Second,
+			ArchiveKey:         getEnv("CHAT_ARCHIVE_KEY", ""),
+			ArchiveRetention:   time.Duration(getEnvAsInt("CHAT_ARCHIVE_RETENTION_DAYS", 90)) * 24 * time.Hour,
+		},
+		Worker: WorkerConfig{
+			Concurrency:     getEnvAsIntMap("WORKER_CONCURRENCY"),
+			RateLimits:      getEnvAsFloatMap("WORKER_RATE_LIMITS"),
+			MaxConcurrency:  getEnvAsInt("WORKER_MAX_CONCURRENCY", 16),
+			ShutdownTimeout: time.Duration(getEnvAsInt("WORKER_SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second,
+		},
 	}
 }
 
@@ -139,4 +218,65 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList 쉼표로 구분된 값 목록 (빈 항목 제외)
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsUintList 쉼표로 구분된 숫자 목록 (숫자가 아닌 항목 제외)
+func getEnvAsUintList(key string) []uint {
+	var values []uint
+	for _, value := range getEnvAsList(key) {
+		if number, err := strconv.ParseUint(value, 10, 64); err == nil {
+			values = append(values, uint(number))
+		}
+	}
+	return values
+}
+
+// getEnvAsIntMap 쉼표로 구분된 이름=숫자 목록 (예: send_email=2,send_push_notification=8)
+func getEnvAsIntMap(key string) map[string]int {
+	values := make(map[string]int)
+	for _, entry := range getEnvAsList(key) {
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		if number, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			values[strings.TrimSpace(name)] = number
+		}
+	}
+	return values
+}
+
+// getEnvAsFloatMap 쉼표로 구분된 이름=숫자 목록 (소수 허용)
+func getEnvAsFloatMap(key string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, entry := range getEnvAsList(key) {
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			values[strings.TrimSpace(name)] = number
+		}
+	}
+	return values
+}
@@ -60,6 +60,15 @@ func (d *Database) Migrate() error {
 		&models.SignalParticipant{},
 		&models.ChatRoom{},
 		&models.ChatMessage{},
+		&models.ChatReadState{},
+		&models.ChatRoomMute{},
+		&models.ChatParticipantMute{},
+		&models.ChatAttachment{},
+		&models.ChatPoll{},
+		&models.ChatPollOption{},
+		&models.ChatPollVote{},
+		&models.ChatExtendVote{},
+		&models.ChatArchive{},
 		&models.UserRating{},
 		&models.ReportUser{},
 		&models.PushToken{},
@@ -95,6 +104,21 @@ func (d *Database) createIndexes() error {
 		// 채팅 메시지 인덱스
 		`CREATE INDEX IF NOT EXISTS idx_chat_messages_room_time 
 		 ON chat_messages (chat_room_id, created_at DESC)`,
+
+		// 채팅 메시지 순번 (채팅방별 유일)
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_messages_room_seq
+		 ON chat_messages (chat_room_id, seq)
+		 WHERE seq > 0`,
+
+		// 채팅 메시지 검색 (pg_trgm)
+		`CREATE INDEX IF NOT EXISTS idx_chat_messages_content_trgm
+		 ON chat_messages USING GIN (content gin_trgm_ops)
+		 WHERE deleted_at IS NULL`,
+
+		// 낙관적 전송 중복 방지
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_messages_client_msg
+		 ON chat_messages (chat_room_id, user_id, client_msg_id)
+		 WHERE client_msg_id <> ''`,
 		
 		// 푸시 토큰 인덱스
 		`CREATE INDEX IF NOT EXISTS idx_push_tokens_active 
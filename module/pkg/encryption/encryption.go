@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"signal-module/pkg/config"
+)
+
+// ChatArchiveKey 채팅 보관용 AES-256 키
+//
+// 보관된 대화는 이 키로만 복호화할 수 있으므로 다른 비밀 값에서 유도하지 않고 반드시 따로 설정해야 한다.
+func ChatArchiveKey(cfg *config.ChatConfig) ([]byte, error) {
+	if cfg.ArchiveKey == "" {
+		return nil, fmt.Errorf("CHAT_ARCHIVE_KEY가 설정되지 않았습니다 (생성: openssl rand -base64 32)")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(cfg.ArchiveKey)
+	if err != nil {
+		return nil, fmt.Errorf("CHAT_ARCHIVE_KEY는 base64 형식이어야 합니다: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("CHAT_ARCHIVE_KEY는 32바이트여야 합니다 (현재 %d바이트)", len(key))
+	}
+	return key, nil
+}
+
+// KeyFingerprint 어떤 키로 암호화했는지 구분하기 위한 지문 (키 자체는 드러나지 않음)
+func KeyFingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Encrypt AES-GCM으로 암호화하고 nonce를 앞에 붙여 반환
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("nonce 생성 실패: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt Encrypt로 암호화한 값 복호화
+func Decrypt(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("암호문이 너무 짧습니다")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("복호화 실패: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("암호화 키가 올바르지 않습니다: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -154,6 +154,9 @@ type BuddyRelationship struct {
 	BuddyDisplayName    *string   `json:"buddy_display_name"`
 	UserMannerScore     float64   `json:"user_manner_score"`
 	BuddyMannerScore    float64   `json:"buddy_manner_score"`
+
+	// 상대방 접속 상태 (비공개 설정이면 비어 있음)
+	Presence *UserPresence `json:"presence,omitempty" gorm:"-"`
 }
 
 // PotentialBuddy 단골 후보자
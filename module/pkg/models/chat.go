@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,15 +16,35 @@ const (
 	ChatRoomClosed  ChatRoomStatus = "closed"  // 강제 종료
 )
 
+// ChatRoomLifetime 시그널 예정 시간 이후 채팅방 유지 시간
+const ChatRoomLifetime = 24 * time.Hour
+
+// 채팅방 연장 투표 (만료 전 참여자 과반이 찬성하면 24시간 연장)
+const (
+	ChatRoomExtension     = 24 * time.Hour
+	ChatRoomMaxExtensions = 2
+	ChatExtendVoteWindow  = 6 * time.Hour // 만료 6시간 전부터 투표 가능
+)
+
 type ChatRoom struct {
 	ID       uint           `json:"id" gorm:"primaryKey"`
 	SignalID uint           `json:"signal_id" gorm:"uniqueIndex;not null"`
 	Name     string         `json:"name" gorm:"size:100"`
 	Status   ChatRoomStatus `json:"status" gorm:"default:'active'"`
-	
-	// 자동 소멸 시간 (시그널 시작 24시간 후)
-	ExpiresAt *time.Time `json:"expires_at"`
-	
+
+	// 마지막으로 발급한 메시지 시퀀스 번호
+	LastSeq int64 `json:"last_seq" gorm:"not null;default:0"`
+
+	// 호스트가 고정한 만남 장소 (location 메시지)
+	MeetingPointMessageID *uint `json:"meeting_point_message_id"`
+
+	// 슬로우 모드: 참여자가 메시지를 보낸 뒤 다음 메시지까지 기다려야 하는 시간 (0이면 해제, 호스트 제외)
+	SlowModeSeconds int `json:"slow_mode_seconds" gorm:"not null;default:0"`
+
+	// 자동 소멸 시간 (시그널 시작 24시간 후, 연장 투표가 통과할 때마다 24시간 연장)
+	ExpiresAt      *time.Time `json:"expires_at"`
+	ExtensionCount int        `json:"extension_count" gorm:"not null;default:0"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
@@ -34,67 +56,804 @@ type ChatRoom struct {
 type MessageType string
 
 const (
-	MessageText   MessageType = "text"   // 텍스트 메시지
-	MessageImage  MessageType = "image"  // 이미지
-	MessageSystem MessageType = "system" // 시스템 메시지
-	MessageJoin   MessageType = "join"   // 참여 알림
-	MessageLeave  MessageType = "leave"  // 나가기 알림
+	MessageText     MessageType = "text"     // 텍스트 메시지
+	MessageImage    MessageType = "image"    // 이미지
+	MessageFile     MessageType = "file"     // 파일
+	MessageLocation MessageType = "location" // 만남 장소 (호스트만 보낼 수 있으며 채팅방에 고정됨)
+	MessagePoll     MessageType = "poll"     // 투표
+	MessageSystem   MessageType = "system"   // 시스템 메시지
+	MessageJoin     MessageType = "join"     // 참여 알림
+	MessageLeave    MessageType = "leave"    // 나가기 알림
 )
 
 type ChatMessage struct {
-	ID         uint        `json:"id" gorm:"primaryKey"`
-	ChatRoomID uint        `json:"chat_room_id" gorm:"not null"`
-	UserID     *uint       `json:"user_id"` // nil이면 시스템 메시지
-	Type       MessageType `json:"type" gorm:"default:'text'"`
-	Content    string      `json:"content" gorm:"size:1000;not null"`
-	ImageURL   string      `json:"image_url"`
-	
+	ID           uint        `json:"id" gorm:"primaryKey"`
+	ChatRoomID   uint        `json:"chat_room_id" gorm:"not null"`
+	UserID       *uint       `json:"user_id"`                       // nil이면 시스템 메시지
+	Seq          int64       `json:"seq" gorm:"not null;default:0"` // 채팅방 내 순번
+	ClientMsgID  string      `json:"client_msg_id" gorm:"size:64"`  // 클라이언트 낙관적 전송 식별자
+	Type         MessageType `json:"type" gorm:"default:'text'"`
+	Content      string      `json:"content" gorm:"size:1000;not null"`
+	ImageURL     string      `json:"image_url"`                        // 첨부 이미지 다운로드 경로 (서버가 설정)
+	ReplyToID    *uint       `json:"reply_to_id"`                      // 답장 대상 메시지
+	AttachmentID *uint       `json:"attachment_id" gorm:"uniqueIndex"` // 이미지/파일 첨부 (한 번만 전송 가능)
+
+	// 만남 장소 (location 메시지)
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	PlaceName string   `json:"place_name" gorm:"size:100"`
+	Address   string   `json:"address" gorm:"size:200"`
+
 	// 메시지 상태
 	IsEdited bool       `json:"is_edited" gorm:"default:false"`
 	EditedAt *time.Time `json:"edited_at"`
-	
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
-	ChatRoom ChatRoom `json:"-" gorm:"foreignKey:ChatRoomID"`
-	User     *User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	ChatRoom   ChatRoom        `json:"-" gorm:"foreignKey:ChatRoomID"`
+	User       *User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	ReplyTo    *ChatMessage    `json:"reply_to,omitempty" gorm:"foreignKey:ReplyToID"`
+	Attachment *ChatAttachment `json:"attachment,omitempty" gorm:"foreignKey:AttachmentID"`
+	Poll       *ChatPoll       `json:"poll,omitempty" gorm:"foreignKey:MessageID"`
+}
+
+type AttachmentStatus string
+
+const (
+	AttachmentPending AttachmentStatus = "pending" // 업로드 URL 발급, 업로드 대기
+	AttachmentReady   AttachmentStatus = "ready"   // 업로드 및 처리 완료
+)
+
+// ChatAttachment 채팅 첨부파일 (원본과 썸네일은 BlobStore에 저장)
+type ChatAttachment struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	ChatRoomID uint             `json:"chat_room_id" gorm:"not null;index"`
+	UploaderID uint             `json:"uploader_id" gorm:"not null"`
+	Status     AttachmentStatus `json:"status" gorm:"size:20;default:'pending'"`
+
+	FileName    string `json:"file_name" gorm:"size:255"`
+	ContentType string `json:"content_type" gorm:"size:100"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+
+	ObjectKey            string `json:"-" gorm:"size:255"`
+	ThumbnailKey         string `json:"-" gorm:"size:255"`
+	ThumbnailContentType string `json:"-" gorm:"size:100"`
+
+	// 서명된 업로드 URL 역할을 하는 일회용 토큰
+	UploadToken     string     `json:"-" gorm:"size:64;uniqueIndex"`
+	UploadExpiresAt *time.Time `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 첨부 가능한 형식과 최대 크기
+var AttachmentContentTypes = map[string]MessageType{
+	"image/jpeg":      MessageImage,
+	"image/png":       MessageImage,
+	"image/gif":       MessageImage,
+	"application/pdf": MessageFile,
+	"text/plain":      MessageFile,
+}
+
+const (
+	MaxImageAttachmentSize = 10 << 20 // 10MB
+	MaxFileAttachmentSize  = 20 << 20 // 20MB
+	AttachmentUploadWindow = 10 * time.Minute
+)
+
+// ToInfo 메시지 페이로드용 첨부 정보 (다운로드는 참여자 인증이 필요한 API 경로)
+func (a *ChatAttachment) ToInfo() *ChatAttachmentInfo {
+	info := &ChatAttachmentInfo{
+		ID:          a.ID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Width:       a.Width,
+		Height:      a.Height,
+		URL:         a.DownloadPath(),
+	}
+	if a.ThumbnailKey != "" {
+		info.ThumbnailURL = a.DownloadPath() + "/thumbnail"
+	}
+	return info
+}
+
+func (a *ChatAttachment) DownloadPath() string {
+	return fmt.Sprintf("/api/v1/chat/attachments/%d", a.ID)
+}
+
+type PollKind string
+
+const (
+	PollGeneral PollKind = "general" // 일반 투표
+	PollTime    PollKind = "time"    // 약속 시간 (선택지마다 scheduled_at)
+	PollPlace   PollKind = "place"   // 약속 장소 (선택지마다 location)
+)
+
+// ChatPoll 채팅방 투표 (poll 메시지와 1:1)
+type ChatPoll struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	ChatRoomID     uint       `json:"chat_room_id" gorm:"not null;index"`
+	MessageID      uint       `json:"message_id" gorm:"not null;uniqueIndex"`
+	CreatorID      uint       `json:"creator_id" gorm:"not null"`
+	Question       string     `json:"question" gorm:"size:200;not null"`
+	Kind           PollKind   `json:"kind" gorm:"size:20;default:'general'"`
+	MultipleChoice bool       `json:"multiple_choice" gorm:"default:false"`
+	Anonymous      bool       `json:"anonymous" gorm:"default:false"` // 누가 어디에 투표했는지 공개하지 않음
+	Deadline       *time.Time `json:"deadline"`
+	ClosedAt       *time.Time `json:"closed_at"`
+
+	// 호스트가 시그널에 반영한 선택지
+	AppliedOptionID *uint `json:"applied_option_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Options []ChatPollOption `json:"options" gorm:"foreignKey:PollID"`
+	Votes   []ChatPollVote   `json:"-" gorm:"foreignKey:PollID"`
+}
+
+type ChatPollOption struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	PollID   uint   `json:"poll_id" gorm:"not null;index"`
+	Position int    `json:"position" gorm:"not null"`
+	Text     string `json:"text" gorm:"size:100;not null"`
+
+	// 시간/장소 투표의 선택지 값 (시그널에 반영할 때 사용)
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	Latitude    *float64   `json:"latitude"`
+	Longitude   *float64   `json:"longitude"`
+	PlaceName   string     `json:"place_name" gorm:"size:100"`
+	Address     string     `json:"address" gorm:"size:200"`
+}
+
+// ChatPollVote 사용자의 선택 (다중 선택 투표는 선택지마다 한 행)
+type ChatPollVote struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PollID    uint      `json:"poll_id" gorm:"not null;index"`
+	OptionID  uint      `json:"option_id" gorm:"not null;uniqueIndex:idx_chat_poll_votes_option_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_chat_poll_votes_option_user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	ChatPollMinOptions = 2
+	ChatPollMaxOptions = 10
+)
+
+// IsClosed 마감되었거나 마감 시간이 지났는지
+func (p *ChatPoll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.Deadline != nil && !now.Before(*p.Deadline))
+}
+
+// ToInfo 현재 집계를 포함한 투표 정보 (Options, Votes가 preload 되어 있어야 함)
+func (p *ChatPoll) ToInfo() *ChatPollInfo {
+	info := &ChatPollInfo{
+		ID:              p.ID,
+		Question:        p.Question,
+		Kind:            p.Kind,
+		MultipleChoice:  p.MultipleChoice,
+		Anonymous:       p.Anonymous,
+		Deadline:        p.Deadline,
+		Closed:          p.IsClosed(time.Now()),
+		AppliedOptionID: p.AppliedOptionID,
+		Options:         make([]ChatPollOptionInfo, 0, len(p.Options)),
+	}
+
+	counts := make(map[uint]int)
+	voters := make(map[uint][]uint)
+	distinct := make(map[uint]struct{})
+	for _, vote := range p.Votes {
+		counts[vote.OptionID]++
+		voters[vote.OptionID] = append(voters[vote.OptionID], vote.UserID)
+		distinct[vote.UserID] = struct{}{}
+	}
+	info.TotalVoters = len(distinct)
+
+	for _, option := range p.Options {
+		optionInfo := ChatPollOptionInfo{
+			ID:          option.ID,
+			Text:        option.Text,
+			ScheduledAt: option.ScheduledAt,
+			Votes:       counts[option.ID],
+		}
+		if option.Latitude != nil && option.Longitude != nil {
+			optionInfo.Location = &ChatLocation{
+				Latitude:  *option.Latitude,
+				Longitude: *option.Longitude,
+				PlaceName: option.PlaceName,
+				Address:   option.Address,
+			}
+		}
+		if !p.Anonymous {
+			optionInfo.Voters = voters[option.ID]
+			if optionInfo.Voters == nil {
+				optionInfo.Voters = []uint{}
+			}
+		}
+		info.Options = append(info.Options, optionInfo)
+	}
+
+	return info
+}
+
+// ChatReadState 사용자별 채팅방 마지막 읽은 메시지
+type ChatReadState struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	ChatRoomID        uint      `json:"chat_room_id" gorm:"not null;uniqueIndex:idx_chat_read_states_room_user"`
+	UserID            uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_chat_read_states_room_user"`
+	LastReadMessageID uint      `json:"last_read_message_id"`
+	LastReadSeq       int64     `json:"last_read_seq" gorm:"not null;default:0"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ChatRoomMute 사용자별 채팅방 알림 끄기 (MutedUntil이 nil이면 직접 해제할 때까지)
+type ChatRoomMute struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ChatRoomID uint       `json:"chat_room_id" gorm:"not null;uniqueIndex:idx_chat_room_mutes_room_user"`
+	UserID     uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_chat_room_mutes_room_user"`
+	MutedUntil *time.Time `json:"muted_until"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ChatParticipantMute 호스트가 참여자의 채팅을 일정 시간 제한 (ChatRoomMute는 본인의 알림 끄기)
+type ChatParticipantMute struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ChatRoomID uint      `json:"chat_room_id" gorm:"not null;uniqueIndex:idx_chat_participant_mutes_room_user"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_chat_participant_mutes_room_user"`
+	MutedByID  uint      `json:"muted_by_id" gorm:"not null"`
+	MutedUntil time.Time `json:"muted_until" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ChatExtendVote 채팅방 연장 찬성 (Round는 투표 당시의 ExtensionCount, 연장될 때마다 새로 투표)
+type ChatExtendVote struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ChatRoomID uint      `json:"chat_room_id" gorm:"not null;uniqueIndex:idx_chat_extend_votes_room_round_user"`
+	Round      int       `json:"round" gorm:"not null;uniqueIndex:idx_chat_extend_votes_room_round_user"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_chat_extend_votes_room_round_user"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ChatArchive 신고가 접수된 채팅방을 파기할 때 남기는 암호화된 대화 사본
+//
+// 사용자에게는 보이지 않으며, 신고 처리를 위해 보관 기간 동안만 유지된다.
+// Sealed는 ChatTranscript JSON을 AES-256-GCM으로 암호화한 값이다.
+type ChatArchive struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ChatRoomID     uint      `json:"chat_room_id" gorm:"uniqueIndex;not null"`
+	SignalID       uint      `json:"signal_id" gorm:"index;not null"`
+	MessageCount   int       `json:"message_count"`
+	KeyFingerprint string    `json:"key_fingerprint" gorm:"size:16"` // 암호화에 사용한 키 (키 교체 대비)
+	Sealed         []byte    `json:"-" gorm:"not null"`
+	RetainUntil    time.Time `json:"retain_until" gorm:"index;not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// 메시지 수정 가능 시간
+const ChatMessageEditWindow = 15 * time.Minute
+
+// 실시간 위치 공유 가능 시간 (시그널 예정 시간 기준)
+const (
+	LiveLocationLeadTime  = time.Hour        // 예정 시간 1시간 전부터
+	LiveLocationTrailTime = 30 * time.Minute // 예정 시간 30분 후까지
+)
+
+// LiveLocationWindow 시그널 예정 시간 기준 실시간 위치 공유 가능 구간
+func LiveLocationWindow(scheduledAt time.Time) (time.Time, time.Time) {
+	return scheduledAt.Add(-LiveLocationLeadTime), scheduledAt.Add(LiveLocationTrailTime)
 }
 
 // DTO 구조체들
 type SendMessageRequest struct {
-	Type     MessageType `json:"type" binding:"required,oneof=text image"`
-	Content  string      `json:"content" binding:"required,max=1000"`
-	ImageURL string      `json:"image_url"`
+	ClientMsgID  string             `json:"client_msg_id" binding:"max=64"`
+	Type         MessageType        `json:"type" binding:"required,oneof=text image file location poll"`
+	Content      string             `json:"content" binding:"max=1000"`
+	AttachmentID *uint              `json:"attachment_id"` // image/file 메시지는 업로드 완료된 첨부 필요
+	Location     *ChatLocation      `json:"location"`      // location 메시지의 만남 장소
+	Poll         *CreatePollRequest `json:"poll"`          // poll 메시지의 투표
+	ReplyToID    *uint              `json:"reply_to_id"`
+}
+
+type CreatePollRequest struct {
+	Question       string              `json:"question"`
+	Kind           PollKind            `json:"kind"`
+	MultipleChoice bool                `json:"multiple_choice"`
+	Anonymous      bool                `json:"anonymous"`
+	Deadline       *time.Time          `json:"deadline"`
+	Options        []PollOptionRequest `json:"options"`
+}
+
+type PollOptionRequest struct {
+	Text        string        `json:"text"`
+	ScheduledAt *time.Time    `json:"scheduled_at"` // time 투표
+	Location    *ChatLocation `json:"location"`     // place 투표
+}
+
+// VotePollRequest 내 선택을 지정한 선택지로 교체 (빈 목록이면 투표 취소)
+type VotePollRequest struct {
+	OptionIDs []uint `json:"option_ids"`
+}
+
+type ApplyPollRequest struct {
+	OptionID uint `json:"option_id" binding:"required"`
+}
+
+type CreateAttachmentRequest struct {
+	FileName    string `json:"file_name" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+type AttachmentUploadResponse struct {
+	AttachmentID uint      `json:"attachment_id"`
+	UploadURL    string    `json:"upload_url"` // 이 경로로 파일 본문을 PUT
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type MarkReadRequest struct {
+	MessageID uint `json:"message_id" binding:"required"`
+}
+
+type MuteChatRoomRequest struct {
+	DurationMinutes int `json:"duration_minutes" binding:"min=0,max=10080"` // 0이면 직접 해제할 때까지
+}
+
+// ReportMessageRequest 채팅 메시지 신고 (작성자를 신고하고 메시지를 근거로 첨부)
+type ReportMessageRequest struct {
+	Reason  ReportReason `json:"reason" binding:"required,oneof=inappropriate spam fake harassment other"`
+	Comment string       `json:"comment" binding:"max=500"`
+}
+
+// MuteParticipantRequest 호스트가 참여자의 채팅을 제한할 시간
+type MuteParticipantRequest struct {
+	DurationMinutes int `json:"duration_minutes" binding:"required,min=1,max=1440"`
+}
+
+type SlowModeRequest struct {
+	Seconds int `json:"seconds" binding:"min=0,max=600"` // 0이면 해제
+}
+
+// ChatExtendStatus 채팅방 연장 투표 현황
+type ChatExtendStatus struct {
+	ExpiresAt      *time.Time `json:"expires_at"`
+	ExtensionCount int        `json:"extension_count"`
+	MaxExtensions  int        `json:"max_extensions"`
+	VotingOpensAt  *time.Time `json:"voting_opens_at"`
+	Votes          int        `json:"votes"`
+	Required       int        `json:"required"` // 참여자 과반
+	Participants   int        `json:"participants"`
+	Voted          bool       `json:"voted"`
+	Extended       bool       `json:"extended"` // 이번 요청으로 연장됨
+}
+
+// MessageWindowQuery 커서 기반 메시지 조회 (around: 메시지 ID 기준 앞뒤, before/after: seq 기준)
+type MessageWindowQuery struct {
+	Around uint  `form:"around"`
+	Before int64 `form:"before"`
+	After  int64 `form:"after"`
+	Limit  int   `form:"limit"`
+}
+
+// ChatMessageWindow 커서 기반 메시지 구간 (오래된 순)
+type ChatMessageWindow struct {
+	Messages []*ChatEnvelope `json:"messages"`
+	AnchorID uint            `json:"anchor_id,omitempty"` // around로 요청한 메시지
+	Before   *int64          `json:"before,omitempty"`    // 더 이전 메시지가 있으면 다음 요청의 before 값
+	After    *int64          `json:"after,omitempty"`     // 더 최근 메시지가 있으면 다음 요청의 after 값
+}
+
+type ChatSearchResult struct {
+	Message *ChatEnvelope   `json:"message"`
+	Score   float64         `json:"score"`   // 검색어와의 유사도 (0-1)
+	Context []*ChatEnvelope `json:"context"` // 일치한 메시지를 포함한 앞뒤 메시지 (오래된 순)
+}
+
+// ChatSearchResponse 최신순 검색 결과 (NextBefore가 있으면 before로 다음 페이지 요청)
+type ChatSearchResponse struct {
+	Query      string             `json:"query"`
+	Results    []ChatSearchResult `json:"results"`
+	NextBefore *int64             `json:"next_before,omitempty"`
+}
+
+// ChatTranscript 대화 기록 (내보내기와 파기 전 보관에 공통 사용)
+type ChatTranscript struct {
+	ChatRoomID  uint                    `json:"chat_room_id"`
+	SignalID    uint                    `json:"signal_id"`
+	Name        string                  `json:"name"`
+	GeneratedAt time.Time               `json:"generated_at"`
+	ReportIDs   []uint                  `json:"report_ids,omitempty"` // 보관 사유 (파기 시에만)
+	Messages    []ChatTranscriptMessage `json:"messages"`
+}
+
+type ChatTranscriptMessage struct {
+	ID        uint          `json:"id"`
+	Seq       int64         `json:"seq"`
+	UserID    *uint         `json:"user_id,omitempty"`
+	Username  string        `json:"username,omitempty"`
+	Type      MessageType   `json:"type"`
+	Content   string        `json:"content"`
+	ReplyToID *uint         `json:"reply_to_id,omitempty"`
+	FileName  string        `json:"file_name,omitempty"`
+	Location  *ChatLocation `json:"location,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	EditedAt  *time.Time    `json:"edited_at,omitempty"`
+	DeletedAt *time.Time    `json:"deleted_at,omitempty"`
+}
+
+type EditMessageRequest struct {
+	Content string `json:"content" binding:"required,max=1000"`
 }
 
 type ChatRoomInfo struct {
-	ID            uint                `json:"id"`
-	SignalID      uint                `json:"signal_id"`
-	Name          string              `json:"name"`
-	Status        ChatRoomStatus      `json:"status"`
-	ExpiresAt     *time.Time          `json:"expires_at"`
-	ParticipantCount int              `json:"participant_count"`
-	LastMessage   *ChatMessage        `json:"last_message,omitempty"`
-	CreatedAt     time.Time           `json:"created_at"`
-	UpdatedAt     time.Time           `json:"updated_at"`
-}
-
-type MessageWithUser struct {
-	ID         uint        `json:"id"`
-	ChatRoomID uint        `json:"chat_room_id"`
-	Type       MessageType `json:"type"`
-	Content    string      `json:"content"`
-	ImageURL   string      `json:"image_url"`
-	IsEdited   bool        `json:"is_edited"`
-	EditedAt   *time.Time  `json:"edited_at"`
-	CreatedAt  time.Time   `json:"created_at"`
-	
-	// 사용자 정보 (시스템 메시지가 아닐 때만)
-	User *struct {
-		ID          uint   `json:"id"`
-		Username    string `json:"username"`
-		DisplayName string `json:"display_name"`
-		Avatar      string `json:"avatar"`
-	} `json:"user,omitempty"`
-}
\ No newline at end of file
+	ID               uint           `json:"id"`
+	SignalID         uint           `json:"signal_id"`
+	Name             string         `json:"name"`
+	Status           ChatRoomStatus `json:"status"`
+	ExpiresAt        *time.Time     `json:"expires_at"`
+	ParticipantCount int            `json:"participant_count"`
+	UnreadCount      int64          `json:"unread_count" gorm:"-"`
+	IsMuted          bool           `json:"is_muted" gorm:"-"`
+	LastMessage      *ChatEnvelope  `json:"last_message,omitempty" gorm:"-"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// 채팅 wire 포맷
+//
+// REST 히스토리와 WebSocket이 같은 봉투(envelope)를 사용하므로 클라이언트는 하나의 파서로
+// 과거 메시지와 실시간 메시지를 처리하고, client_msg_id로 낙관적 전송을 중복 제거할 수 있다.
+
+// ChatEnvelopeVersion 현재 채팅 봉투 버전
+const ChatEnvelopeVersion = 1
+
+type ChatEventType string
+
+const (
+	ChatEventMessage      ChatEventType = "message"       // 채팅 메시지 (시스템 메시지 포함)
+	ChatEventEdit         ChatEventType = "edit"          // 메시지 수정 (server_id의 메시지를 교체)
+	ChatEventDelete       ChatEventType = "delete"        // 모두에게서 삭제 (server_id의 메시지를 삭제 표시로 교체)
+	ChatEventRead         ChatEventType = "read"          // 읽음 확인 (sender가 seq까지 읽음)
+	ChatEventTyping       ChatEventType = "typing"        // 입력 중 표시 (저장하지 않음)
+	ChatEventLiveLocation ChatEventType = "live_location" // 실시간 위치 공유 (저장하지 않음)
+	ChatEventPoll         ChatEventType = "poll"          // 투표 집계 변경 (server_id의 poll 메시지)
+	ChatEventError        ChatEventType = "error"         // 요청 처리 실패 (보낸 클라이언트에게만 전달)
+	ChatEventSession      ChatEventType = "session"       // 연결 직후 이어받기 결과 (접속한 클라이언트에게만 전달)
+)
+
+type ChatEnvelope struct {
+	Version     int             `json:"v"`
+	Event       ChatEventType   `json:"event"`
+	RoomID      uint            `json:"room_id,omitempty"`
+	ClientMsgID string          `json:"client_msg_id,omitempty"`
+	ServerID    uint            `json:"server_id,omitempty"` // chat_messages.id
+	Seq         int64           `json:"seq,omitempty"`
+	Sender      *ChatSender     `json:"sender,omitempty"` // nil이면 시스템
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	SentAt      time.Time       `json:"sent_at"`
+
+	// 채팅방 실시간 스트림 순번 (WebSocket으로 전파된 모든 이벤트에 붙음, 재연결 시 resume_from으로 전달)
+	StreamSeq int64 `json:"stream_seq,omitempty"`
+}
+
+type ChatSender struct {
+	ID          uint   `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Avatar      string `json:"avatar"`
+}
+
+// ChatMessagePayload message/edit/delete 이벤트의 페이로드
+type ChatMessagePayload struct {
+	Type       MessageType         `json:"type"`
+	Content    string              `json:"content"`
+	ImageURL   string              `json:"image_url,omitempty"`
+	Attachment *ChatAttachmentInfo `json:"attachment,omitempty"`
+	Location   *ChatLocation       `json:"location,omitempty"`
+	Poll       *ChatPollInfo       `json:"poll,omitempty"`
+	ReplyTo    *ChatReplyPreview   `json:"reply_to,omitempty"`
+	IsEdited   bool                `json:"is_edited"`
+	EditedAt   *time.Time          `json:"edited_at,omitempty"`
+	IsDeleted  bool                `json:"is_deleted"`
+	DeletedAt  *time.Time          `json:"deleted_at,omitempty"`
+	ReadBy     int                 `json:"read_by"` // 발신자를 제외하고 읽은 참여자 수 (edit/delete 이벤트에서는 계산하지 않음)
+}
+
+// ChatReadPayload read 이벤트의 페이로드
+//
+// 클라이언트는 PreviousSeq < seq <= LastReadSeq 인 메시지 중 읽은 사용자가 보내지 않은 메시지의 read_by를 1 증가시킨다.
+type ChatReadPayload struct {
+	LastReadMessageID uint  `json:"last_read_message_id"`
+	LastReadSeq       int64 `json:"last_read_seq"`
+	PreviousSeq       int64 `json:"previous_seq"`
+}
+
+// ChatLocation 만남 장소 좌표와 이름
+type ChatLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	PlaceName string  `json:"place_name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+}
+
+// ChatLiveLocationPayload live_location 이벤트의 페이로드 (저장하지 않음)
+//
+// 클라이언트는 공유를 시작하거나 위치가 바뀔 때 sharing=true와 좌표를, 공유를 끝낼 때 sharing=false를 보낸다.
+// 서버는 만남 장소(없으면 시그널 장소)까지의 거리를 계산해 채워서 전파한다.
+type ChatLiveLocationPayload struct {
+	Sharing        bool       `json:"sharing"`
+	Latitude       float64    `json:"latitude,omitempty"`
+	Longitude      float64    `json:"longitude,omitempty"`
+	Accuracy       float64    `json:"accuracy,omitempty"` // 미터
+	DistanceMeters *float64   `json:"distance_meters,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"` // 공유 가능 시간 종료
+}
+
+// ChatLiveLocation 현재 위치를 공유 중인 참여자 (만남 장소에서 가까운 순)
+type ChatLiveLocation struct {
+	Sender *ChatSender `json:"sender"`
+	ChatLiveLocationPayload
+}
+
+type ChatLiveLocationsResponse struct {
+	MeetingPoint *ChatLocation      `json:"meeting_point"`
+	StartsAt     time.Time          `json:"starts_at"`
+	EndsAt       time.Time          `json:"ends_at"`
+	Locations    []ChatLiveLocation `json:"locations"`
+}
+
+// ChatSessionPayload session 이벤트의 페이로드 (봉투의 stream_seq는 클라이언트가 따라잡은 순번)
+//
+// ResyncRequired면 끊긴 동안의 이벤트가 재전송 버퍼에서 이미 밀려났으므로 REST로 메시지를 다시 불러와야 한다.
+type ChatSessionPayload struct {
+	Resumed        bool `json:"resumed"`
+	ResyncRequired bool `json:"resync_required"`
+	Replayed       int  `json:"replayed"`
+}
+
+// ChatTypingPayload typing 이벤트의 페이로드
+type ChatTypingPayload struct {
+	IsTyping bool `json:"is_typing"`
+}
+
+// ChatAttachmentInfo 첨부파일 정보 (보낼 때는 id만 지정)
+type ChatAttachmentInfo struct {
+	ID           uint   `json:"id"`
+	FileName     string `json:"file_name,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	URL          string `json:"url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// ChatPollInfo poll 메시지와 poll 이벤트의 투표 정보
+type ChatPollInfo struct {
+	ID              uint                 `json:"id"`
+	Question        string               `json:"question"`
+	Kind            PollKind             `json:"kind"`
+	MultipleChoice  bool                 `json:"multiple_choice"`
+	Anonymous       bool                 `json:"anonymous"`
+	Deadline        *time.Time           `json:"deadline,omitempty"`
+	Closed          bool                 `json:"closed"`
+	AppliedOptionID *uint                `json:"applied_option_id,omitempty"`
+	TotalVoters     int                  `json:"total_voters"`
+	Options         []ChatPollOptionInfo `json:"options"`
+	MyOptionIDs     []uint               `json:"my_option_ids,omitempty"` // 요청자 본인의 선택 (REST 응답에서만)
+}
+
+type ChatPollOptionInfo struct {
+	ID          uint          `json:"id"`
+	Text        string        `json:"text"`
+	ScheduledAt *time.Time    `json:"scheduled_at,omitempty"`
+	Location    *ChatLocation `json:"location,omitempty"`
+	Votes       int           `json:"votes"`
+	Voters      []uint        `json:"voters,omitempty"` // 익명 투표에서는 생략
+}
+
+// ChatReplyPreview 답장 대상 메시지 인용
+type ChatReplyPreview struct {
+	ServerID  uint        `json:"server_id"`
+	Seq       int64       `json:"seq"`
+	Sender    *ChatSender `json:"sender,omitempty"`
+	Type      MessageType `json:"type"`
+	Content   string      `json:"content"` // 최대 100자로 잘림
+	IsDeleted bool        `json:"is_deleted"`
+}
+
+// ChatErrorPayload error 이벤트의 페이로드
+type ChatErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewChatEnvelope 페이로드를 직렬화하여 봉투 생성
+func NewChatEnvelope(event ChatEventType, roomID uint, payload interface{}) *ChatEnvelope {
+	envelope := &ChatEnvelope{
+		Version: ChatEnvelopeVersion,
+		Event:   event,
+		RoomID:  roomID,
+		SentAt:  time.Now(),
+	}
+	envelope.SetPayload(payload)
+	return envelope
+}
+
+// SetPayload 페이로드 설정 (페이로드는 항상 JSON 직렬화 가능한 구조체)
+func (e *ChatEnvelope) SetPayload(payload interface{}) {
+	if payload == nil {
+		e.Payload = nil
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		data = nil
+	}
+	e.Payload = data
+}
+
+// DecodePayload 페이로드를 지정한 구조체로 역직렬화
+func (e *ChatEnvelope) DecodePayload(v interface{}) error {
+	if len(e.Payload) == 0 {
+		return json.Unmarshal([]byte("{}"), v)
+	}
+	return json.Unmarshal(e.Payload, v)
+}
+
+// NewChatSender 사용자 정보로 발신자 프로필 생성
+func NewChatSender(user *User) *ChatSender {
+	if user == nil {
+		return nil
+	}
+	sender := &ChatSender{
+		ID:       user.ID,
+		Username: user.Username,
+	}
+	if user.Profile != nil {
+		sender.DisplayName = user.Profile.DisplayName
+		sender.Avatar = user.Profile.Avatar
+	}
+	return sender
+}
+
+// ToEnvelope 저장된 메시지를 wire 포맷으로 변환 (User.Profile이 preload 되어 있어야 발신자 프로필이 채워짐)
+func (m *ChatMessage) ToEnvelope() *ChatEnvelope {
+	return m.ToEventEnvelope(ChatEventMessage)
+}
+
+// ToEnvelopeWithReads 읽음 상태 목록으로 read_by를 계산하여 봉투 생성 (히스토리용)
+func (m *ChatMessage) ToEnvelopeWithReads(states []ChatReadState) *ChatEnvelope {
+	return m.toEnvelope(ChatEventMessage, m.readBy(states))
+}
+
+// ToEventEnvelope 지정한 이벤트로 메시지 봉투 생성 (삭제된 메시지는 내용을 비운 삭제 표시로 변환)
+func (m *ChatMessage) ToEventEnvelope(event ChatEventType) *ChatEnvelope {
+	return m.toEnvelope(event, 0)
+}
+
+func (m *ChatMessage) readBy(states []ChatReadState) int {
+	count := 0
+	for _, state := range states {
+		if m.UserID != nil && state.UserID == *m.UserID {
+			continue
+		}
+		if state.LastReadSeq >= m.Seq {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *ChatMessage) toEnvelope(event ChatEventType, readBy int) *ChatEnvelope {
+	payload := &ChatMessagePayload{
+		Type:     m.Type,
+		Content:  m.Content,
+		ImageURL: m.ImageURL,
+		IsEdited: m.IsEdited,
+		EditedAt: m.EditedAt,
+		ReadBy:   readBy,
+	}
+	if m.Attachment != nil {
+		payload.Attachment = m.Attachment.ToInfo()
+	}
+	payload.Location = m.Location()
+	if m.Poll != nil {
+		payload.Poll = m.Poll.ToInfo()
+	}
+	if m.ReplyTo != nil {
+		payload.ReplyTo = m.ReplyTo.toReplyPreview()
+	}
+	if m.DeletedAt.Valid {
+		deletedAt := m.DeletedAt.Time
+		payload.Content = ""
+		payload.ImageURL = ""
+		payload.Attachment = nil
+		payload.Location = nil
+		payload.Poll = nil
+		payload.ReplyTo = nil
+		payload.IsDeleted = true
+		payload.DeletedAt = &deletedAt
+	}
+
+	envelope := NewChatEnvelope(event, m.ChatRoomID, payload)
+	envelope.ClientMsgID = m.ClientMsgID
+	envelope.ServerID = m.ID
+	envelope.Seq = m.Seq
+	envelope.Sender = NewChatSender(m.User)
+	envelope.SentAt = m.CreatedAt
+	return envelope
+}
+
+// Location location 메시지의 만남 장소 (좌표가 없으면 nil)
+func (m *ChatMessage) Location() *ChatLocation {
+	if m.Latitude == nil || m.Longitude == nil {
+		return nil
+	}
+	return &ChatLocation{
+		Latitude:  *m.Latitude,
+		Longitude: *m.Longitude,
+		PlaceName: m.PlaceName,
+		Address:   m.Address,
+	}
+}
+
+// ToTranscript 대화 기록 항목 (첨부는 파일 이름만, 투표는 질문만 남김)
+func (m *ChatMessage) ToTranscript() ChatTranscriptMessage {
+	entry := ChatTranscriptMessage{
+		ID:        m.ID,
+		Seq:       m.Seq,
+		UserID:    m.UserID,
+		Type:      m.Type,
+		Content:   m.Content,
+		ReplyToID: m.ReplyToID,
+		Location:  m.Location(),
+		CreatedAt: m.CreatedAt,
+		EditedAt:  m.EditedAt,
+	}
+	if m.User != nil {
+		entry.Username = m.User.Username
+	}
+	if m.Attachment != nil {
+		entry.FileName = m.Attachment.FileName
+	}
+	if m.Poll != nil && entry.Content == "" {
+		entry.Content = m.Poll.Question
+	}
+	if m.DeletedAt.Valid {
+		deletedAt := m.DeletedAt.Time
+		entry.DeletedAt = &deletedAt
+	}
+	return entry
+}
+
+func (m *ChatMessage) toReplyPreview() *ChatReplyPreview {
+	preview := &ChatReplyPreview{
+		ServerID:  m.ID,
+		Seq:       m.Seq,
+		Sender:    NewChatSender(m.User),
+		Type:      m.Type,
+		IsDeleted: m.DeletedAt.Valid,
+	}
+	if !preview.IsDeleted {
+		preview.Content = truncateRunes(m.Content, 100)
+	}
+	return preview
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
@@ -105,6 +105,17 @@ type CreateSignalRequest struct {
 	GenderPreference    string `json:"gender_preference" binding:"oneof=any male female"`
 }
 
+// UpdateSignalRequest 시그널 수정 (지정한 항목만 변경, 좌표는 위도/경도를 함께 지정)
+type UpdateSignalRequest struct {
+	Title       *string    `json:"title" binding:"omitempty,min=5,max=100"`
+	Description *string    `json:"description" binding:"omitempty,max=500"`
+	Latitude    *float64   `json:"latitude"`
+	Longitude   *float64   `json:"longitude"`
+	Address     *string    `json:"address" binding:"omitempty,max=200"`
+	PlaceName   *string    `json:"place_name" binding:"omitempty,max=100"`
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
 type JoinSignalRequest struct {
 	Message string `json:"message" binding:"max=200"`
 }
@@ -125,6 +136,41 @@ type SignalWithDistance struct {
 	Distance float64 `json:"distance"` // 미터 단위
 }
 
+// SignalEventKind 시그널 변경 종류 (지도 WebSocket이 클라이언트별로 created/updated/removed로 변환)
+type SignalEventKind string
+
+const (
+	SignalEventCreated   SignalEventKind = "created"
+	SignalEventUpdated   SignalEventKind = "updated"   // 제목/설명/시간/위치 수정
+	SignalEventSeats     SignalEventKind = "seats"     // 참여 인원 변경 (정원 마감/해제 포함)
+	SignalEventCancelled SignalEventKind = "cancelled"
+	SignalEventExpired   SignalEventKind = "expired"
+)
+
+// SignalEvent 인스턴스 간 Redis pub/sub으로 전달되는 시그널 변경 이벤트
+type SignalEvent struct {
+	Kind       SignalEventKind `json:"kind"`
+	Signal     Signal          `json:"signal"`
+	OccurredAt time.Time       `json:"occurred_at"`
+
+	// 위치가 바뀐 경우 이전 좌표 (이전 위치만 보고 있던 클라이언트에게는 removed)
+	PrevLatitude  *float64 `json:"prev_latitude,omitempty"`
+	PrevLongitude *float64 `json:"prev_longitude,omitempty"`
+}
+
+// NewSignalEvent 참여자/채팅방 정보를 뺀 시그널로 이벤트 생성
+func NewSignalEvent(kind SignalEventKind, signal *Signal) *SignalEvent {
+	event := &SignalEvent{Kind: kind, Signal: *signal, OccurredAt: time.Now()}
+	event.Signal.Participants = nil
+	event.Signal.ChatRoom = nil
+	return event
+}
+
+// IsRemoval 지도에서 시그널을 내려야 하는 이벤트
+func (e *SignalEvent) IsRemoval() bool {
+	return e.Kind == SignalEventCancelled || e.Kind == SignalEventExpired
+}
+
 // PostGIS 헬퍼 메서드들
 func (s *Signal) SetLocationFromCoordinates() {
 	s.Location = fmt.Sprintf("POINT(%f %f)", s.Longitude, s.Latitude)
@@ -24,6 +24,9 @@ type User struct {
 	Location  *UserLocation   `json:"location,omitempty" gorm:"foreignKey:UserID"`
 	Interests []UserInterest  `json:"interests,omitempty" gorm:"foreignKey:UserID"`
 	PushTokens []PushToken    `json:"-" gorm:"foreignKey:UserID"`
+
+	// 접속 상태 (참여자/단골 목록 조회 시 채움, 비공개 설정이면 비어 있음)
+	Presence *UserPresence `json:"presence,omitempty" gorm:"-"`
 }
 
 type UserProfile struct {
@@ -43,6 +46,7 @@ type UserProfile struct {
 	PushNotifications     bool `json:"push_notifications" gorm:"default:true"`
 	LocationSharing       bool `json:"location_sharing" gorm:"default:true"`
 	ProfilePublic         bool `json:"profile_public" gorm:"default:true"`
+	PresenceVisible       bool `json:"presence_visible" gorm:"default:true"` // 다른 사용자에게 접속 상태와 마지막 접속 시각 공개
 	
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -119,6 +123,37 @@ type UserRating struct {
 	Signal Signal `json:"-" gorm:"foreignKey:SignalID"`
 }
 
+// 매너 점수 계산 기준 (최근 평가의 평균에서 노쇼 1회당 감점)
+const (
+	MannerScoreWindow   = 30 * 24 * time.Hour
+	MannerNoShowPenalty = 0.5
+	MinMannerScore      = 1.0
+)
+
+// CalculateMannerScore 최근 평가들로 매너 점수와 노쇼 횟수 계산 (평가가 없으면 ok=false)
+func CalculateMannerScore(ratings []UserRating) (score float64, noShowCount int, ok bool) {
+	if len(ratings) == 0 {
+		return 0, 0, false
+	}
+
+	totalScore := 0
+	for _, rating := range ratings {
+		totalScore += rating.Score
+		if rating.IsNoShow {
+			noShowCount++
+		}
+	}
+
+	score = float64(totalScore) / float64(len(ratings))
+	if noShowCount > 0 {
+		score -= float64(noShowCount) * MannerNoShowPenalty
+		if score < MinMannerScore {
+			score = MinMannerScore
+		}
+	}
+	return score, noShowCount, true
+}
+
 type ReportReason string
 
 const (
@@ -134,6 +169,8 @@ type ReportUser struct {
 	ReporterID uint        `json:"reporter_id" gorm:"not null"`
 	ReportedID uint        `json:"reported_id" gorm:"not null"`
 	SignalID   *uint       `json:"signal_id"`
+	ChatMessageID *uint    `json:"chat_message_id" gorm:"index"` // 채팅 메시지 신고
+	MessageContent string  `json:"message_content" gorm:"size:1000"` // 신고 시점의 메시지 내용 (채팅방 소멸 후 확인용)
 	Reason     ReportReason `json:"reason" gorm:"not null"`
 	Comment    string       `json:"comment" gorm:"size:500"`
 	Status     string       `json:"status" gorm:"default:'pending'"` // pending, resolved, dismissed
@@ -162,6 +199,8 @@ type UpdateProfileRequest struct {
 	Bio         string `json:"bio" binding:"max=500"`
 	Age         int    `json:"age" binding:"min=14,max=100"`
 	Gender      string `json:"gender" binding:"oneof=male female other"`
+
+	PresenceVisible *bool `json:"presence_visible"` // 생략하면 유지
 }
 
 type UpdateLocationRequest struct {
@@ -174,4 +213,11 @@ type UserClaims struct {
 	UserID   uint   `json:"user_id"`
 	Email    string `json:"email"`
 	Username string `json:"username"`
-}
\ No newline at end of file
+}
+
+// UserPresence 사용자 접속 상태
+type UserPresence struct {
+	IsOnline   bool       `json:"is_online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"` // 오프라인일 때 마지막 접속 시각
+	Devices    int        `json:"devices,omitempty"`      // 접속 중인 기기 수 (본인에게만)
+}
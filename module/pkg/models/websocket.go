@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// WebSocket 연결 티켓 대상 스트림
+const (
+	WSStreamSignals = "signals" // /signals/ws
+	WSStreamChat    = "chat"    // /chat/ws/:room_id
+)
+
+// WSTicketRequest WebSocket 연결 티켓 발급 요청
+type WSTicketRequest struct {
+	Stream string `json:"stream" binding:"required,oneof=signals chat"`
+	RoomID string `json:"room_id"` // chat 스트림의 채팅방 (예: signal_12)
+}
+
+type WSTicketResponse struct {
+	Ticket    string    `json:"ticket"` // 업그레이드 요청에 ?ticket=으로 전달 (한 번만 사용 가능)
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// WSTicketClaims 티켓에 묶인 사용자와 연결 대상
+type WSTicketClaims struct {
+	UserID   uint   `json:"user_id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Target   string `json:"target"` // signals 또는 chat:<room_id>
+}
+
+// WSTicketTarget 티켓이 허용하는 연결 대상 식별자
+func WSTicketTarget(stream, roomID string) string {
+	if stream == WSStreamChat {
+		return WSStreamChat + ":" + roomID
+	}
+	return stream
+}
+
+// UserNotification 사용자별 실시간 알림 (푸시 알림과 함께 발행되어 지도 WebSocket/SSE로도 전달)
+type UserNotification struct {
+	UserIDs   []uint            `json:"user_ids,omitempty"` // 발행 시 대상 (클라이언트에게는 보내지 않음)
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// IsFor 대상 사용자인지 확인
+func (n *UserNotification) IsFor(userID uint) bool {
+	for _, id := range n.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+package queue
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+// 데드 레터 큐
+//
+// 재시도를 다 쓰거나 읽을 수 없는 작업은 queue:<type>:dead 목록에 마지막 실패 기록과 함께 보관한다.
+// 임대 만료로 옮겨진 작업은 원본을 그대로 두고 실패 기록만 queue:<type>:dead:failures 해시에 작업 ID별로 남긴다.
+// 운영자는 signal-queue CLI나 관리자 API로 목록을 보고, 원인을 고친 뒤 다시 실행(replay)하거나 지운다(purge).
+// 예전 버전이 모든 타입을 함께 넣던 failed_jobs는 MigrateLegacyFailedJobs로 타입별 큐로 옮긴다.
+const (
+	legacyFailedKey = "failed_jobs"
+
+	// 타입을 알 수 없는 작업의 데드 레터 큐 이름
+	unknownJobType JobType = "unknown"
+)
+
+// ErrJobNotFound 데드 레터 큐에 해당 작업이 없음
+var ErrJobNotFound = errors.New("작업을 찾을 수 없습니다")
+
+func deadKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:dead", jobType)
+}
+
+func deadFailuresKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:dead:failures", jobType)
+}
+
+// deadFailure 작업 원본 밖에 보관한 실패 기록 (작업 원본의 실패 기록보다 우선)
+type deadFailure struct {
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// ParseJobType 알려진 작업 타입인지 확인 (타입을 알 수 없는 작업의 큐 "unknown" 포함)
+func ParseJobType(value string) (JobType, bool) {
+	for _, jobType := range JobTypes {
+		if string(jobType) == value {
+			return jobType, true
+		}
+	}
+	if value == string(unknownJobType) {
+		return unknownJobType, true
+	}
+	return "", false
+}
+
+// decodeDeadLetter 데드 레터 항목 읽기 (읽을 수 없는 항목은 원본을 페이로드에 담고 내용 기반 ID 부여)
+//
+// failures는 queue:<type>:dead:failures 해시 내용으로, 작업 ID의 실패 기록이 있으면 반영한다.
+func decodeDeadLetter(jobType JobType, data string, failures map[string]string) *Job {
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err == nil && job.ID != "" {
+		var failure deadFailure
+		if record, ok := failures[job.ID]; ok && json.Unmarshal([]byte(record), &failure) == nil {
+			job.LastError = failure.LastError
+			job.ErrorStack = ""
+			job.FailedAt = &failure.FailedAt
+		}
+		return &job
+	}
+
+	sum := sha1.Sum([]byte(data))
+	raw, _ := json.Marshal(data)
+	return &Job{
+		ID:        "invalid-" + hex.EncodeToString(sum[:6]),
+		Type:      jobType,
+		Payload:   raw,
+		LastError: "작업 데이터를 읽을 수 없습니다",
+	}
+}
+
+// DeadLetters 데드 레터 큐의 작업 목록 (최근 실패 순)과 전체 개수
+func (q *Queue) DeadLetters(ctx context.Context, jobType JobType, offset, limit int64) ([]*Job, int64, error) {
+	pipe := q.client.GetClient().Pipeline()
+	items := pipe.LRange(ctx, deadKey(jobType), offset, offset+limit-1)
+	total := pipe.LLen(ctx, deadKey(jobType))
+	failures := pipe.HGetAll(ctx, deadFailuresKey(jobType))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]*Job, 0, len(items.Val()))
+	for _, data := range items.Val() {
+		jobs = append(jobs, decodeDeadLetter(jobType, data, failures.Val()))
+	}
+	return jobs, total.Val(), nil
+}
+
+// findDeadLetter 작업 ID로 데드 레터 항목과 원본 찾기
+func (q *Queue) findDeadLetter(ctx context.Context, client redisClient.Cmdable, jobType JobType, jobID string) (*Job, string, error) {
+	items, err := client.LRange(ctx, deadKey(jobType), 0, -1).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	failures, err := client.HGetAll(ctx, deadFailuresKey(jobType)).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, data := range items {
+		if job := decodeDeadLetter(jobType, data, failures); job.ID == jobID {
+			return job, data, nil
+		}
+	}
+	return nil, "", ErrJobNotFound
+}
+
+// DeadLetter 데드 레터 작업 하나 조회
+func (q *Queue) DeadLetter(ctx context.Context, jobType JobType, jobID string) (*Job, error) {
+	job, _, err := q.findDeadLetter(ctx, q.client.GetClient(), jobType, jobID)
+	return job, err
+}
+
+// ReplayDeadLetter 데드 레터 작업을 시도 횟수를 초기화해 다시 큐에 넣음 (실패 기록은 참고용으로 유지)
+//
+// 완료 기록(job_done)도 지우므로, 이전 전달이 완료 처리된 작업이어도 다시 처리된다.
+func (q *Queue) ReplayDeadLetter(ctx context.Context, jobType JobType, jobID string) error {
+	key := deadKey(jobType)
+	return q.client.GetClient().Watch(ctx, func(tx *redisClient.Tx) error {
+		job, data, err := q.findDeadLetter(ctx, tx, jobType, jobID)
+		if err != nil {
+			return err
+		}
+		return q.replayIn(ctx, tx, key, job, data)
+	}, key)
+}
+
+// ReplayDeadLetters 데드 레터 큐의 작업을 모두 다시 큐에 넣고 개수 반환 (읽을 수 없는 항목은 남김)
+func (q *Queue) ReplayDeadLetters(ctx context.Context, jobType JobType) (int, error) {
+	key := deadKey(jobType)
+	replayed := 0
+	err := q.client.GetClient().Watch(ctx, func(tx *redisClient.Tx) error {
+		items, err := tx.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return err
+		}
+		failures, err := tx.HGetAll(ctx, deadFailuresKey(jobType)).Result()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+			for _, data := range items {
+				job := decodeDeadLetter(jobType, data, failures)
+				if job.CreatedAt.IsZero() {
+					continue
+				}
+				if err := q.requeueIn(ctx, pipe, key, job, data); err != nil {
+					return err
+				}
+				replayed++
+			}
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return 0, err
+	}
+	return replayed, nil
+}
+
+func (q *Queue) replayIn(ctx context.Context, tx *redisClient.Tx, key string, job *Job, data string) error {
+	if job.CreatedAt.IsZero() {
+		return fmt.Errorf("읽을 수 없는 작업은 다시 실행할 수 없습니다")
+	}
+	_, err := tx.TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		return q.requeueIn(ctx, pipe, key, job, data)
+	})
+	return err
+}
+
+// requeueIn 트랜잭션에 데드 레터 항목 제거와 작업 재등록 추가
+func (q *Queue) requeueIn(ctx context.Context, pipe redisClient.Pipeliner, key string, job *Job, data string) error {
+	job.Attempts = 0
+	replay, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("작업 직렬화 실패: %w", err)
+	}
+
+	pipe.LRem(ctx, key, 1, data)
+	pipe.HDel(ctx, deadFailuresKey(job.Type), job.ID)
+	pipe.Del(ctx, jobDoneKey(job.ID))
+	if job.UniqueKey != "" {
+		q.enqueueUnique(ctx, pipe, job, replay, time.Time{}, false)
+	} else {
+		pipe.LPush(ctx, queueKey(job.Type), replay)
+	}
+	return nil
+}
+
+// PurgeDeadLetter 데드 레터 작업 하나 삭제
+func (q *Queue) PurgeDeadLetter(ctx context.Context, jobType JobType, jobID string) error {
+	_, data, err := q.findDeadLetter(ctx, q.client.GetClient(), jobType, jobID)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.GetClient().TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		pipe.LRem(ctx, deadKey(jobType), 1, data)
+		pipe.HDel(ctx, deadFailuresKey(jobType), jobID)
+		return nil
+	})
+	return err
+}
+
+// PurgeDeadLetters 데드 레터 큐 비우기 (삭제한 개수 반환)
+func (q *Queue) PurgeDeadLetters(ctx context.Context, jobType JobType) (int64, error) {
+	var count *redisClient.IntCmd
+	_, err := q.client.GetClient().TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		count = pipe.LLen(ctx, deadKey(jobType))
+		pipe.Del(ctx, deadKey(jobType), deadFailuresKey(jobType))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+// migrateLegacyFailedScript failed_jobs의 마지막 작업이 아직 data이면 데드 레터 큐로 이동
+//
+// KEYS: failed_jobs, 작업 타입의 dead
+// ARGV: 작업 데이터
+var migrateLegacyFailedScript = redisClient.NewScript(`
+if redis.call('LINDEX', KEYS[1], -1) ~= ARGV[1] then
+	return 0
+end
+redis.call('RPOP', KEYS[1])
+redis.call('LPUSH', KEYS[2], ARGV[1])
+return 1
+`)
+
+// legacyFailedJobType failed_jobs 항목의 작업 타입 (읽을 수 없거나 알 수 없는 타입이면 unknown)
+func legacyFailedJobType(data string) JobType {
+	var job struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return unknownJobType
+	}
+	if jobType, ok := ParseJobType(job.Type); ok {
+		return jobType
+	}
+	return unknownJobType
+}
+
+// MigrateLegacyFailedJobs 예전 버전의 failed_jobs를 타입별 데드 레터 큐로 옮기고 개수 반환
+//
+// 작업 타입은 Go에서 읽고 옮길 큐를 KEYS로 넘긴다. 다른 워커가 같은 항목을 먼저 옮겼으면 다음 항목으로 넘어간다.
+func (q *Queue) MigrateLegacyFailedJobs(ctx context.Context) (int, error) {
+	client := q.client.GetClient()
+	moved := 0
+	for {
+		data, err := client.LIndex(ctx, legacyFailedKey, -1).Result()
+		if errors.Is(err, redisClient.Nil) {
+			return moved, nil
+		}
+		if err != nil {
+			return moved, err
+		}
+
+		ok, err := migrateLegacyFailedScript.Run(ctx, client,
+			[]string{legacyFailedKey, deadKey(legacyFailedJobType(data))},
+			data,
+		).Int()
+		if err != nil {
+			return moved, err
+		}
+		moved += ok
+	}
+}
+
+// AllQueueStats 모든 작업 타입의 큐 상태
+func (q *Queue) AllQueueStats(ctx context.Context) (map[JobType]map[string]int64, error) {
+	stats := make(map[JobType]map[string]int64, len(JobTypes))
+	for _, jobType := range JobTypes {
+		typeStats, err := q.GetQueueStats(ctx, jobType)
+		if err != nil {
+			return nil, fmt.Errorf("%s 큐 상태 조회 실패: %w", jobType, err)
+		}
+		stats[jobType] = typeStats
+	}
+	return stats, nil
+}
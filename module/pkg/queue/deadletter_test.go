@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"signal-module/pkg/redis"
+)
+
+// expireUntilDead 작업을 가져오고 임대를 만료시키기를 times번 반복 (워커가 계속 멈추는 작업)
+func expireUntilDead(t *testing.T, q *Queue, rdb *redis.Client, jobType JobType, times int) {
+	t.Helper()
+
+	for i := 0; i < times; i++ {
+		popTestJob(t, q, jobType)
+		expireLeases(t, rdb, jobType)
+		if _, err := q.RequeueExpired(context.Background(), jobType); err != nil {
+			t.Fatalf("회수 실패: %v", err)
+		}
+	}
+}
+
+func TestReplayExpiredDeadLetter(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 1)
+	expireUntilDead(t, q, rdb, JobSendPushNotification, 1)
+
+	if err := q.ReplayDeadLetter(ctx, JobSendPushNotification, "job-1"); err != nil {
+		t.Fatalf("다시 실행 실패: %v", err)
+	}
+	if n, _ := rdb.GetClient().HLen(ctx, deadFailuresKey(JobSendPushNotification)).Result(); n != 0 {
+		t.Fatalf("다시 실행 후 남은 실패 기록: %d개", n)
+	}
+
+	// 시도 횟수는 초기화하고 실패 기록은 참고용으로 유지
+	job := popTestJob(t, q, JobSendPushNotification)
+	if job.ID != "job-1" || job.Attempts != 0 || job.LastError == "" {
+		t.Fatalf("다시 실행한 작업: %s, 시도 %d, 실패 기록 %q", job.ID, job.Attempts, job.LastError)
+	}
+	if payload, err := DecodePayload[PushNotificationPayload](job); err != nil || payload.UserIDs == nil {
+		t.Fatalf("다시 실행한 작업 페이로드: %+v, %v", payload, err)
+	}
+}
+
+func TestFailMovesToDeadLetter(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 2)
+
+	// 임대 만료 한 번 뒤 처리 실패로 시도 횟수를 다 씀
+	expireUntilDead(t, q, rdb, JobSendPushNotification, 1)
+	job := popTestJob(t, q, JobSendPushNotification)
+	if err := q.Fail(ctx, job, errors.New("발송 실패"), Backoff{}); err != nil {
+		t.Fatalf("Fail 실패: %v", err)
+	}
+
+	stats, _ := q.GetQueueStats(ctx, JobSendPushNotification)
+	if stats["processing"] != 0 || stats["delayed"] != 0 || stats["dead"] != 1 {
+		t.Fatalf("데드 레터 이동 후 큐 상태: %v", stats)
+	}
+	dead, err := q.DeadLetter(ctx, JobSendPushNotification, "job-1")
+	if err != nil || dead.LastError != "발송 실패" {
+		t.Fatalf("데드 레터: %+v, %v", dead, err)
+	}
+
+	if err := q.PurgeDeadLetter(ctx, JobSendPushNotification, "job-1"); err != nil {
+		t.Fatalf("삭제 실패: %v", err)
+	}
+	if _, err := q.DeadLetter(ctx, JobSendPushNotification, "job-1"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("삭제한 작업 조회: %v", err)
+	}
+}
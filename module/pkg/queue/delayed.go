@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+// 지연 작업
+//
+// queue:<type>:delayed 정렬 집합에 실행 시각(밀리초)을 점수로 보관하고, 시각이 된 작업을 ProcessDelayedJobs가
+// queue:<type>으로 옮긴다. 예전 버전이 모든 타입을 함께 넣던 delayed_jobs는 비워질 때까지 함께 처리하며,
+// 초 단위 점수로 넣은 작업은 옮기기 전에 밀리초로 변환한다.
+const (
+	legacyDelayedKey = "delayed_jobs"
+
+	// 스크립트 한 번에 옮기는 최대 작업 수 (Redis를 오래 막지 않도록)
+	promoteBatchSize = 100
+	// 이보다 작은 점수는 초 단위로 기록된 것으로 본다 (밀리초 기준 1973년)
+	legacyScoreLimit = 100_000_000_000
+)
+
+func delayedKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:delayed", jobType)
+}
+
+func delayedScore(at time.Time) float64 {
+	return float64(at.UnixMilli())
+}
+
+// promoteDelayedScript 실행 시각이 된 지연 작업을 꺼내 작업 타입의 큐에 넣음
+//
+// KEYS: delayed, dead(읽을 수 없는 작업), unique_delayed, unique, 작업 타입별 queue...
+// ARGV: 현재 시각(ms), 최대 개수, 초 단위 점수 경계, KEYS[5]부터의 queue에 대응하는 작업 타입...
+//
+// ZRANGEBYSCORE와 ZREM, LPUSH가 한 스크립트에서 실행되므로 여러 워커가 동시에 호출해도
+// 같은 작업이 두 번 옮겨지지 않는다. 읽을 수 없거나 넘겨받은 타입이 아닌 작업은 데드 레터 큐로 보낸다.
+// 고유 키 작업은 더 이상 예약 중이 아니므로 unique_jobs:delayed에서 지우고,
+// 데드 레터 큐로 보내는 작업이 키의 현재 작업이면 unique_jobs에서도 지운다.
+// 모든 키를 KEYS로 넘겨받으므로 스크립트 안에서 키 이름을 만들지 않는다.
+var promoteDelayedScript = redisClient.NewScript(`
+local queues = {}
+for i = 4, #ARGV do
+	queues[ARGV[i]] = KEYS[i + 1]
+end
+
+local legacy = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[3], 'WITHSCORES', 'LIMIT', 0, tonumber(ARGV[2]))
+for i = 1, #legacy, 2 do
+	redis.call('ZADD', KEYS[1], tonumber(legacy[i + 1]) * 1000, legacy[i])
+end
+
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, data in ipairs(due) do
+	redis.call('ZREM', KEYS[1], data)
+	local ok, job = pcall(cjson.decode, data)
+	local unique = ok and type(job) == 'table' and type(job['unique_key']) == 'string'
+	if unique and redis.call('HGET', KEYS[3], job['unique_key']) == data then
+		redis.call('HDEL', KEYS[3], job['unique_key'])
+	end
+	if ok and type(job) == 'table' and type(job['type']) == 'string' and queues[job['type']] then
+		redis.call('LPUSH', queues[job['type']], data)
+	else
+		if unique and redis.call('HGET', KEYS[4], job['unique_key']) == job['id'] then
+			redis.call('HDEL', KEYS[4], job['unique_key'])
+		end
+		redis.call('LPUSH', KEYS[2], data)
+	end
+end
+return #due
+`)
+
+// typedKeys 작업 타입별 키와 타입 이름 (스크립트가 작업의 타입으로 KEYS에서 키를 고를 수 있도록 같은 순서로 넘김)
+func typedKeys(jobTypes []JobType, key func(JobType) string) ([]string, []interface{}) {
+	keys := make([]string, 0, len(jobTypes))
+	names := make([]interface{}, 0, len(jobTypes))
+	for _, jobType := range jobTypes {
+		keys = append(keys, key(jobType))
+		names = append(names, string(jobType))
+	}
+	return keys, names
+}
@@ -0,0 +1,185 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"signal-module/pkg/redis"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+// scheduleTestJobs 실행 시각이 at인 고유 키 없는 작업 n개 예약 (ID는 prefix-0부터)
+func scheduleTestJobs(t *testing.T, q *Queue, prefix string, n int, at time.Time) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		job, err := NewJob(PushNotificationPayload{UserIDs: []uint{}, Title: prefix})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job.ID = fmt.Sprintf("%s-%d", prefix, i)
+		if err := q.Schedule(context.Background(), job, at); err != nil {
+			t.Fatalf("예약 실패: %v", err)
+		}
+	}
+}
+
+// queuedJobIDs jobType 대기열에 있는 작업 ID별 개수
+func queuedJobIDs(t *testing.T, rdb *redis.Client, jobType JobType) map[string]int {
+	t.Helper()
+
+	entries, err := rdb.GetClient().LRange(context.Background(), queueKey(jobType), 0, -1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]int, len(entries))
+	for _, data := range entries {
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			t.Fatalf("대기열의 작업을 읽을 수 없습니다: %v", err)
+		}
+		ids[job.ID]++
+	}
+	return ids
+}
+
+func TestProcessDelayedJobsConcurrentPromotesOnce(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	const jobs = 300
+
+	scheduleTestJobs(t, q, "job", jobs, time.Now().Add(-time.Minute))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- q.ProcessDelayedJobs(ctx)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("지연 작업 이동 실패: %v", err)
+		}
+	}
+
+	ids := queuedJobIDs(t, rdb, JobSendPushNotification)
+	if len(ids) != jobs {
+		t.Fatalf("옮겨진 작업 %d개 (기대: %d)", len(ids), jobs)
+	}
+	for id, count := range ids {
+		if count != 1 {
+			t.Fatalf("작업 %s가 %d번 옮겨졌습니다", id, count)
+		}
+	}
+	if n, _ := rdb.GetClient().ZCard(ctx, delayedKey(JobSendPushNotification)).Result(); n != 0 {
+		t.Fatalf("남은 지연 작업 %d개", n)
+	}
+}
+
+func TestProcessDelayedJobsPromotesBeyondBatch(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	due := promoteBatchSize*2 + promoteBatchSize/2
+
+	scheduleTestJobs(t, q, "due", due, time.Now().Add(-time.Minute))
+	scheduleTestJobs(t, q, "later", 1, time.Now().Add(time.Hour))
+
+	if err := q.ProcessDelayedJobs(ctx); err != nil {
+		t.Fatalf("지연 작업 이동 실패: %v", err)
+	}
+
+	if n, _ := rdb.GetClient().LLen(ctx, queueKey(JobSendPushNotification)).Result(); n != int64(due) {
+		t.Fatalf("한 번의 호출로 옮겨진 작업 %d개 (기대: %d)", n, due)
+	}
+	remaining, err := rdb.GetClient().ZRange(ctx, delayedKey(JobSendPushNotification), 0, -1).Result()
+	if err != nil || len(remaining) != 1 {
+		t.Fatalf("남은 지연 작업: %v, %v", remaining, err)
+	}
+}
+
+func TestProcessDelayedJobsConvertsLegacySeconds(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+
+	legacyJob := func(id string) string {
+		job, err := NewJob(PushNotificationPayload{UserIDs: []uint{}, Title: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job.ID = id
+		job.MaxRetries = 3
+		data, err := json.Marshal(job)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// 예전 버전은 delayed_jobs에 초 단위 점수로 넣었음
+	past := legacyJob("past-seconds")
+	future := legacyJob("future-seconds")
+	pastMillis := legacyJob("past-millis")
+	futureAt := time.Now().Add(time.Hour).Unix()
+	rdb.GetClient().ZAdd(ctx, legacyDelayedKey,
+		redisClient.Z{Score: float64(time.Now().Add(-time.Minute).Unix()), Member: past},
+		redisClient.Z{Score: float64(futureAt), Member: future},
+		redisClient.Z{Score: delayedScore(time.Now().Add(-time.Minute)), Member: pastMillis},
+	)
+
+	if err := q.ProcessDelayedJobs(ctx); err != nil {
+		t.Fatalf("지연 작업 이동 실패: %v", err)
+	}
+
+	ids := queuedJobIDs(t, rdb, JobSendPushNotification)
+	if len(ids) != 2 || ids["past-seconds"] != 1 || ids["past-millis"] != 1 {
+		t.Fatalf("옮겨진 작업: %v", ids)
+	}
+
+	// 아직 시각이 되지 않은 작업은 밀리초 점수로 바뀌어 남음
+	remaining, err := rdb.GetClient().ZRangeWithScores(ctx, legacyDelayedKey, 0, -1).Result()
+	if err != nil || len(remaining) != 1 || remaining[0].Member != future {
+		t.Fatalf("남은 예전 지연 작업: %v, %v", remaining, err)
+	}
+	if remaining[0].Score != float64(futureAt*1000) {
+		t.Fatalf("변환된 점수 %.0f (기대: %d)", remaining[0].Score, futureAt*1000)
+	}
+}
+
+func TestProcessDelayedJobsClearsUniqueDelayed(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	payload := ExpireSignalPayload{SignalID: 1}
+
+	if err := EnqueueAt(ctx, q, payload, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("예약 실패: %v", err)
+	}
+	if n, _ := rdb.GetClient().HLen(ctx, uniqueDelayedJobsKey).Result(); n != 1 {
+		t.Fatalf("예약 원본 %d개", n)
+	}
+
+	if err := q.ProcessDelayedJobs(ctx); err != nil {
+		t.Fatalf("지연 작업 이동 실패: %v", err)
+	}
+
+	if n, _ := rdb.GetClient().HLen(ctx, uniqueDelayedJobsKey).Result(); n != 0 {
+		t.Fatalf("이동 후 남은 예약 원본 %d개", n)
+	}
+	// 예약 중이 아니어도 현재 작업 기록은 유지되어 처리 대상
+	job := popTestJob(t, q, JobExpireSignal)
+	if owner := uniqueOwner(t, q, payload.UniqueKey()); owner != job.ID {
+		t.Fatalf("현재 작업 %s (기대: %s)", owner, job.ID)
+	}
+	if process, err := q.ShouldProcess(ctx, job); err != nil || !process {
+		t.Fatalf("옮겨진 작업 처리 여부: %v, %v", process, err)
+	}
+}
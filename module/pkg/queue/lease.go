@@ -0,0 +1,338 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+// 처리 중 작업 추적
+//
+// Pop은 queue:<type>에서 queue:<type>:processing 목록으로 작업을 원자적으로 옮기고(BLMOVE)
+// 곧바로 전달마다 새로 만든 토큰으로 바꾼다. 원본은 queue:<type>:deliveries 해시에 토큰별로 보관하고
+// queue:<type>:leases 정렬 집합에 토큰의 임대 만료 시각(밀리초)을 기록한다. 같은 작업이 회수되어
+// 다른 워커에게 다시 전달되어도 토큰이 다르므로, 임대를 잃은 워커의 Ack, Fail, Release는
+// 새 전달을 건드리지 않는다. 워커는 처리하는 동안
+// KeepAlive로 임대를 연장하고, 끝나면 Ack(성공) 또는 Retry(실패)로 처리 중 목록에서 제거한다.
+// 종료하는 워커는 끝내지 못한 작업을 Release로 시도 횟수 변화 없이 대기열에 되돌린다.
+// 워커가 멈춰 임대가 만료된 작업은 RequeueExpired가 대기열 맨 앞으로 되돌리며, 회수 횟수는
+// queue:<type>:redeliveries 해시에 기록해 시도 횟수에 포함한다 (계속 워커를 멈추게 하는 작업은 데드 레터 큐로).
+const (
+	// 하트비트 없이 처리 중으로 인정하는 시간
+	DefaultVisibilityTimeout = 1 * time.Minute
+	// 임대 연장 간격
+	leaseHeartbeatInterval = DefaultVisibilityTimeout / 3
+)
+
+// ErrLeaseLost 임대가 이미 만료되어 다른 워커에게 넘어갔을 수 있음
+var ErrLeaseLost = errors.New("작업 임대가 만료되었습니다")
+
+// ErrNoJob 기다리는 동안 가져올 작업이 없었음
+var ErrNoJob = errors.New("가져올 작업이 없습니다")
+
+// ErrInvalidJob 가져온 작업을 읽을 수 없어 데드 레터 큐로 보냄
+var ErrInvalidJob = errors.New("작업 데이터를 읽을 수 없습니다")
+
+func queueKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s", jobType)
+}
+
+func processingKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:processing", jobType)
+}
+
+func leasesKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:leases", jobType)
+}
+
+func redeliveriesKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:redeliveries", jobType)
+}
+
+func deliveriesKey(jobType JobType) string {
+	return fmt.Sprintf("queue:%s:deliveries", jobType)
+}
+
+// newDeliveryToken 전달 하나를 구분하는 토큰 (처리 중 목록의 작업 원본과 겹치지 않도록 접두어를 붙임)
+func newDeliveryToken() (string, error) {
+	var buf [12]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return "delivery:" + hex.EncodeToString(buf[:]), nil
+}
+
+// deliveryData Pop으로 가져온 전달 토큰 (완료, 재시도, 반환 후에는 빈 문자열)
+func (job *Job) deliveryData() string {
+	job.deliveryMu.Lock()
+	defer job.deliveryMu.Unlock()
+	return job.delivery
+}
+
+func (job *Job) setDelivery(data string) {
+	job.deliveryMu.Lock()
+	defer job.deliveryMu.Unlock()
+	job.delivery = data
+}
+
+func leaseDeadline() float64 {
+	return float64(time.Now().Add(DefaultVisibilityTimeout).UnixMilli())
+}
+
+// claimScript BLMOVE로 처리 중 목록에 옮긴 원본을 전달 토큰으로 바꾸고 임대 시작
+//
+// KEYS: processing, leases, deliveries, redeliveries
+// ARGV: 작업 데이터, 전달 토큰, 임대 만료 시각(ms), 작업 ID
+//
+// 지금까지 회수된 횟수를 반환한다. 그 사이 원본이 회수되어 처리 중 목록에 없으면 -1을 반환한다.
+var claimScript = redisClient.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return -1
+end
+redis.call('LPUSH', KEYS[1], ARGV[2])
+redis.call('HSET', KEYS[3], ARGV[2], ARGV[1])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[2])
+return tonumber(redis.call('HGET', KEYS[4], ARGV[4]) or '0')
+`)
+
+// lease 가져온 작업에 전달 토큰을 주고 임대를 시작한 뒤 지금까지 회수된 횟수를 반환
+func (q *Queue) lease(ctx context.Context, job *Job, data string) (int, error) {
+	token, err := newDeliveryToken()
+	if err != nil {
+		return 0, err
+	}
+
+	redeliveries, err := claimScript.Run(ctx, q.client.GetClient(),
+		[]string{processingKey(job.Type), leasesKey(job.Type), deliveriesKey(job.Type), redeliveriesKey(job.Type)},
+		data, token, leaseDeadline(), job.ID,
+	).Int()
+	if err != nil {
+		return 0, err
+	}
+	if redeliveries < 0 {
+		return 0, ErrLeaseLost
+	}
+
+	job.setDelivery(token)
+	return redeliveries, nil
+}
+
+// Heartbeat 처리 중인 작업의 임대를 가시성 제한 시간만큼 연장
+func (q *Queue) Heartbeat(ctx context.Context, job *Job) error {
+	delivery := job.deliveryData()
+	if delivery == "" {
+		return nil
+	}
+
+	changed, err := q.client.GetClient().ZAddArgs(ctx, leasesKey(job.Type), redisClient.ZAddArgs{
+		XX:      true,
+		Ch:      true,
+		Members: []redisClient.Z{{Score: leaseDeadline(), Member: delivery}},
+	}).Result()
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// KeepAlive 반환된 stop을 호출할 때까지 주기적으로 임대 연장 (연장에 실패하면 onError 호출)
+func (q *Queue) KeepAlive(ctx context.Context, job *Job, onError func(error)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(leaseHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := q.Heartbeat(ctx, job); err != nil && ctx.Err() == nil {
+					onError(fmt.Errorf("작업 %s 임대 연장 실패: %w", job.ID, err))
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// Ack 처리가 끝난 작업을 처리 중 목록에서 제거하고 완료 기록
+func (q *Queue) Ack(ctx context.Context, job *Job) error {
+	_, err := q.client.GetClient().TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		q.completeIn(ctx, pipe, job)
+		q.ackIn(ctx, pipe, job)
+		return nil
+	})
+	if err == nil {
+		job.setDelivery("")
+	}
+	return err
+}
+
+// releaseScript 처리 중 작업을 대기열 맨 앞(다음 Pop 대상)으로 되돌림
+//
+// KEYS: processing, leases, queue, deliveries
+// ARGV: 전달 토큰
+//
+// 이미 회수되어 처리 중 목록에 없으면 중복으로 넣지 않고 0을 반환한다.
+var releaseScript = redisClient.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 0 then
+	return 0
+end
+local data = redis.call('HGET', KEYS[4], ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[4], ARGV[1])
+if data then
+	redis.call('RPUSH', KEYS[3], data)
+end
+return 1
+`)
+
+// Release 끝내지 못한 작업을 시도 횟수를 늘리지 않고 대기열로 되돌림 (워커 종료 시)
+func (q *Queue) Release(ctx context.Context, job *Job) error {
+	delivery := job.deliveryData()
+	if delivery == "" {
+		return nil
+	}
+
+	err := releaseScript.Run(ctx, q.client.GetClient(),
+		[]string{processingKey(job.Type), leasesKey(job.Type), queueKey(job.Type), deliveriesKey(job.Type)},
+		delivery,
+	).Err()
+	if err != nil {
+		return fmt.Errorf("작업 %s 반환 실패: %w", job.ID, err)
+	}
+	job.setDelivery("")
+	return nil
+}
+
+// ackIn 트랜잭션에 처리 완료 명령 추가 (Pop으로 가져온 작업이 아니면 무시)
+//
+// 토큰으로 찾으므로 임대를 잃은 뒤 호출해도 같은 작업의 새 전달은 처리 중 목록에 남는다.
+func (q *Queue) ackIn(ctx context.Context, pipe redisClient.Pipeliner, job *Job) {
+	delivery := job.deliveryData()
+	if delivery == "" {
+		return
+	}
+	pipe.LRem(ctx, processingKey(job.Type), 1, delivery)
+	pipe.ZRem(ctx, leasesKey(job.Type), delivery)
+	pipe.HDel(ctx, deliveriesKey(job.Type), delivery)
+	pipe.HDel(ctx, redeliveriesKey(job.Type), job.ID)
+}
+
+// ownsDelivery 아직 이 전달의 임대를 가지고 있는지 확인 (Pop으로 가져온 작업이 아니면 true)
+func (q *Queue) ownsDelivery(ctx context.Context, job *Job) (bool, error) {
+	delivery := job.deliveryData()
+	if delivery == "" {
+		return true, nil
+	}
+	return q.client.GetClient().HExists(ctx, deliveriesKey(job.Type), delivery).Result()
+}
+
+// discardDelivery 읽을 수 없는 작업을 처리 중 목록에서 데드 레터 큐로 이동
+func (q *Queue) discardDelivery(ctx context.Context, jobType JobType, data string) error {
+	_, err := q.client.GetClient().TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		pipe.LRem(ctx, processingKey(jobType), 1, data)
+		pipe.LPush(ctx, deadKey(jobType), data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s 작업을 데드 레터 큐로 이동 실패: %w", jobType, err)
+	}
+	return nil
+}
+
+// requeueExpiredScript 임대가 만료된 처리 중 작업을 대기열 맨 앞(다음 Pop 대상)으로 되돌림
+//
+// KEYS: processing, leases, queue, redeliveries, dead, deliveries, dead_failures, unique
+// ARGV: 현재 시각(ms), 임대 기록이 없는 작업에 줄 만료 시각(ms), 실패 메시지, 실패 시각(RFC3339)
+//
+// 처리 중 목록의 항목은 전달 토큰이고, BLMOVE 직후 토큰으로 바꾸기 전에 워커가 멈췄으면 작업 원본이다.
+// 원본은 임대 기록이 없으므로 이번 회수에서 만료 시각을 부여하고 다음 회수 때 판단한다.
+// 시도 횟수를 다 쓴 작업은 cjson으로 다시 만들면 빈 배열과 큰 정수가 바뀌므로 원본 그대로 데드 레터 큐에 넣고,
+// 실패 기록은 dead_failures 해시에 따로 남긴다. 고유 키의 현재 작업이면 같은 키로 다시 넣을 수 있도록 기록을 지운다.
+var requeueExpiredScript = redisClient.NewScript(`
+local requeued = 0
+for _, entry in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	local deadline = redis.call('ZSCORE', KEYS[2], entry)
+	if not deadline then
+		redis.call('ZADD', KEYS[2], ARGV[2], entry)
+	elseif tonumber(deadline) <= tonumber(ARGV[1]) then
+		redis.call('LREM', KEYS[1], 1, entry)
+		redis.call('ZREM', KEYS[2], entry)
+		local data = redis.call('HGET', KEYS[6], entry)
+		if data then
+			redis.call('HDEL', KEYS[6], entry)
+		else
+			data = entry
+		end
+
+		local ok, job = pcall(cjson.decode, data)
+		if ok and type(job) == 'table' and job['id'] then
+			local redeliveries = redis.call('HINCRBY', KEYS[4], job['id'], 1)
+			local attempts = (tonumber(job['attempts']) or 0) + redeliveries
+			local maxRetries = tonumber(job['max_retries']) or 0
+			if maxRetries > 0 and attempts >= maxRetries then
+				redis.call('HDEL', KEYS[4], job['id'])
+				redis.call('HSET', KEYS[7], job['id'], cjson.encode({last_error = ARGV[3], failed_at = ARGV[4]}))
+				if type(job['unique_key']) == 'string' and redis.call('HGET', KEYS[8], job['unique_key']) == job['id'] then
+					redis.call('HDEL', KEYS[8], job['unique_key'])
+				end
+				redis.call('LPUSH', KEYS[5], data)
+			else
+				redis.call('RPUSH', KEYS[3], data)
+			end
+		else
+			redis.call('LPUSH', KEYS[5], data)
+		end
+		requeued = requeued + 1
+	end
+end
+return requeued
+`)
+
+// RequeueExpired 임대가 만료된 jobType 작업을 다시 대기열에 넣고 회수한 개수 반환
+func (q *Queue) RequeueExpired(ctx context.Context, jobType JobType) (int, error) {
+	keys := []string{
+		processingKey(jobType),
+		leasesKey(jobType),
+		queueKey(jobType),
+		redeliveriesKey(jobType),
+		deadKey(jobType),
+		deliveriesKey(jobType),
+		deadFailuresKey(jobType),
+		uniqueJobsKey,
+	}
+	now := time.Now()
+
+	return requeueExpiredScript.Run(ctx, q.client.GetClient(), keys,
+		now.UnixMilli(),
+		now.Add(DefaultVisibilityTimeout).UnixMilli(),
+		"처리 중 워커가 응답하지 않아 임대가 만료되었습니다",
+		now.Format(time.RFC3339Nano),
+	).Int()
+}
+
+// RequeueAllExpired 모든 작업 타입에 대해 RequeueExpired 실행
+func (q *Queue) RequeueAllExpired(ctx context.Context) (int, error) {
+	total := 0
+	for _, jobType := range JobTypes {
+		count, err := q.RequeueExpired(ctx, jobType)
+		if err != nil {
+			return total, fmt.Errorf("%s 처리 중 작업 회수 실패: %w", jobType, err)
+		}
+		total += count
+	}
+	return total, nil
+}
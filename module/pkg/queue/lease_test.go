@@ -0,0 +1,229 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+func TestPopLeasesDelivery(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 3)
+
+	job := popTestJob(t, q, JobSendPushNotification)
+	token := job.deliveryData()
+	if token == "" {
+		t.Fatal("전달 토큰이 없습니다")
+	}
+
+	processing, _ := rdb.GetClient().LRange(ctx, processingKey(job.Type), 0, -1).Result()
+	if len(processing) != 1 || processing[0] != token {
+		t.Fatalf("처리 중 목록: %v", processing)
+	}
+	if _, err := rdb.GetClient().ZScore(ctx, leasesKey(job.Type), token).Result(); err != nil {
+		t.Fatalf("임대 기록 없음: %v", err)
+	}
+
+	if err := q.Ack(ctx, job); err != nil {
+		t.Fatalf("Ack 실패: %v", err)
+	}
+	stats, _ := q.GetQueueStats(ctx, job.Type)
+	if stats["pending"] != 0 || stats["processing"] != 0 {
+		t.Fatalf("Ack 후 큐 상태: %v", stats)
+	}
+	if n, _ := rdb.GetClient().HLen(ctx, deliveriesKey(job.Type)).Result(); n != 0 {
+		t.Fatalf("Ack 후 남은 전달 원본: %d개", n)
+	}
+}
+
+func TestRequeueExpiredRecoversCrashedWorker(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 5)
+
+	// 처리하던 워커가 멈춤 (Ack 없이 임대 만료)
+	crashed := popTestJob(t, q, JobSendPushNotification)
+	if count, err := q.RequeueExpired(ctx, JobSendPushNotification); err != nil || count != 0 {
+		t.Fatalf("임대 중 회수: %d, %v", count, err)
+	}
+	expireLeases(t, rdb, JobSendPushNotification)
+	if count, err := q.RequeueExpired(ctx, JobSendPushNotification); err != nil || count != 1 {
+		t.Fatalf("만료 후 회수: %d, %v", count, err)
+	}
+
+	// 회수 횟수가 시도 횟수에 포함되어 다시 전달됨
+	job := popTestJob(t, q, JobSendPushNotification)
+	if job.ID != crashed.ID || job.Attempts != 1 {
+		t.Fatalf("다시 전달된 작업: %s, 시도 %d", job.ID, job.Attempts)
+	}
+	if job.deliveryData() == crashed.deliveryData() {
+		t.Fatal("다시 전달된 작업의 토큰이 같습니다")
+	}
+
+	expireLeases(t, rdb, JobSendPushNotification)
+	q.RequeueExpired(ctx, JobSendPushNotification)
+	if job := popTestJob(t, q, JobSendPushNotification); job.Attempts != 2 {
+		t.Fatalf("두 번째 회수 후 시도 횟수: %d", job.Attempts)
+	}
+}
+
+func TestRequeueExpiredClaimsUnleasedEntry(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	job := pushTestJob(t, q, "job-1", 3)
+
+	// BLMOVE 직후 토큰으로 바꾸기 전에 멈춘 워커 (처리 중 목록에 원본만 남음)
+	data, err := rdb.GetClient().LMove(ctx, queueKey(job.Type), processingKey(job.Type), "RIGHT", "LEFT").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count, _ := q.RequeueExpired(ctx, job.Type); count != 0 {
+		t.Fatalf("임대 기록 없는 작업을 바로 회수했습니다: %d", count)
+	}
+	if _, err := rdb.GetClient().ZScore(ctx, leasesKey(job.Type), data).Result(); err != nil {
+		t.Fatalf("임대 만료 시각을 부여하지 않았습니다: %v", err)
+	}
+
+	expireLeases(t, rdb, job.Type)
+	if count, _ := q.RequeueExpired(ctx, job.Type); count != 1 {
+		t.Fatalf("만료 후 회수: %d", count)
+	}
+	if popped := popTestJob(t, q, job.Type); popped.ID != job.ID {
+		t.Fatalf("회수된 작업: %s", popped.ID)
+	}
+}
+
+func TestHeartbeatExtendsLease(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 3)
+	job := popTestJob(t, q, JobSendPushNotification)
+
+	// 만료 직전 임대를 연장하면 회수되지 않음
+	soon := float64(time.Now().Add(time.Second).UnixMilli())
+	rdb.GetClient().ZAdd(ctx, leasesKey(job.Type), redisClient.Z{Score: soon, Member: job.deliveryData()})
+	if err := q.Heartbeat(ctx, job); err != nil {
+		t.Fatalf("Heartbeat 실패: %v", err)
+	}
+
+	deadline, _ := rdb.GetClient().ZScore(ctx, leasesKey(job.Type), job.deliveryData()).Result()
+	if min := float64(time.Now().Add(DefaultVisibilityTimeout - 5*time.Second).UnixMilli()); deadline < min {
+		t.Fatalf("연장된 만료 시각 %.0f < %.0f", deadline, min)
+	}
+	if count, _ := q.RequeueExpired(ctx, job.Type); count != 0 {
+		t.Fatalf("연장한 작업을 회수했습니다: %d", count)
+	}
+}
+
+func TestLeaseLostDoesNotTouchRedelivery(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 5)
+
+	// 첫 번째 워커의 임대가 만료되어 두 번째 워커에게 다시 전달됨
+	stale := popTestJob(t, q, JobSendPushNotification)
+	expireLeases(t, rdb, JobSendPushNotification)
+	q.RequeueExpired(ctx, JobSendPushNotification)
+	current := popTestJob(t, q, JobSendPushNotification)
+
+	if err := q.Heartbeat(ctx, stale); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("임대를 잃은 워커의 Heartbeat: %v", err)
+	}
+
+	// 임대를 잃은 워커가 Ack, Release, Fail을 호출해도 새 전달은 처리 중으로 남음
+	staleToken := stale.deliveryData()
+	if err := q.Ack(ctx, stale); err != nil {
+		t.Fatalf("임대를 잃은 워커의 Ack: %v", err)
+	}
+	stale.setDelivery(staleToken)
+	if err := q.Release(ctx, stale); err != nil {
+		t.Fatalf("임대를 잃은 워커의 Release: %v", err)
+	}
+	stale.setDelivery(staleToken)
+	if err := q.Fail(ctx, stale, errors.New("실패"), Backoff{}); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("임대를 잃은 워커의 Fail: %v", err)
+	}
+
+	stats, _ := q.GetQueueStats(ctx, JobSendPushNotification)
+	if stats["processing"] != 1 || stats["pending"] != 0 || stats["delayed"] != 0 {
+		t.Fatalf("임대를 잃은 워커 처리 후 큐 상태: %v", stats)
+	}
+
+	// 두 번째 워커도 멈추면 회수되어 작업이 사라지지 않음
+	expireLeases(t, rdb, JobSendPushNotification)
+	if count, _ := q.RequeueExpired(ctx, JobSendPushNotification); count != 1 {
+		t.Fatalf("두 번째 워커 작업 회수: %d", count)
+	}
+	if job := popTestJob(t, q, JobSendPushNotification); job.ID != current.ID {
+		t.Fatalf("회수된 작업: %s", job.ID)
+	}
+}
+
+func TestReleaseKeepsAttempts(t *testing.T) {
+	q, _ := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 3)
+	pushTestJob(t, q, "job-2", 3)
+
+	job := popTestJob(t, q, JobSendPushNotification)
+	if err := q.Release(ctx, job); err != nil {
+		t.Fatalf("Release 실패: %v", err)
+	}
+	if job.deliveryData() != "" {
+		t.Fatal("Release 후 전달 토큰이 남아 있습니다")
+	}
+
+	// 되돌린 작업이 다음 Pop 대상이고 시도 횟수는 그대로
+	again := popTestJob(t, q, JobSendPushNotification)
+	if again.ID != job.ID || again.Attempts != 0 {
+		t.Fatalf("되돌린 작업 대신: %s, 시도 %d", again.ID, again.Attempts)
+	}
+}
+
+func TestRequeueExpiredDeadLettersAtMaxRetries(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	pushTestJob(t, q, "job-1", 2)
+
+	for i := 0; i < 2; i++ {
+		popTestJob(t, q, JobSendPushNotification)
+		expireLeases(t, rdb, JobSendPushNotification)
+		if count, _ := q.RequeueExpired(ctx, JobSendPushNotification); count != 1 {
+			t.Fatalf("%d번째 회수: %d", i+1, count)
+		}
+	}
+
+	stats, _ := q.GetQueueStats(ctx, JobSendPushNotification)
+	if stats["pending"] != 0 || stats["processing"] != 0 || stats["dead"] != 1 {
+		t.Fatalf("시도 횟수를 다 쓴 뒤 큐 상태: %v", stats)
+	}
+	if n, _ := rdb.GetClient().HLen(ctx, redeliveriesKey(JobSendPushNotification)).Result(); n != 0 {
+		t.Fatalf("회수 횟수 기록이 남아 있습니다: %d", n)
+	}
+
+	dead, err := q.DeadLetter(ctx, JobSendPushNotification, "job-1")
+	if err != nil {
+		t.Fatalf("데드 레터 조회 실패: %v", err)
+	}
+	if dead.LastError == "" || dead.FailedAt == nil {
+		t.Fatalf("실패 기록 없음: %+v", dead)
+	}
+	if _, err := DecodePayload[PushNotificationPayload](dead); err != nil {
+		t.Fatalf("데드 레터 페이로드 읽기 실패: %v", err)
+	}
+
+	// 작업 원본은 바꾸지 않고 실패 기록은 따로 보관
+	raw, _ := rdb.GetClient().LIndex(ctx, deadKey(JobSendPushNotification), 0).Result()
+	if !strings.Contains(raw, `"user_ids":[]`) || strings.Contains(raw, "last_error") {
+		t.Fatalf("데드 레터 원본이 바뀌었습니다: %s", raw)
+	}
+	if n, _ := rdb.GetClient().HLen(ctx, deadFailuresKey(JobSendPushNotification)).Result(); n != 1 {
+		t.Fatalf("실패 기록 %d개", n)
+	}
+}
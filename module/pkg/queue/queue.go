@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
 	"signal-module/pkg/redis"
@@ -31,14 +31,36 @@ const (
 	JobCleanupData         JobType = "cleanup_data"
 )
 
+// 모든 작업 타입 (처리 중 작업 회수 대상)
+var JobTypes = []JobType{
+	JobSendPushNotification,
+	JobExpireSignal,
+	JobExpireChatRoom,
+	JobSendEmail,
+	JobUpdateMannerScore,
+	JobCleanupData,
+}
+
 // 기본 작업 구조체
 type Job struct {
 	ID        string                 `json:"id"`
 	Type      JobType                `json:"type"`
-	Payload   map[string]interface{} `json:"payload"`
+	Version   int                    `json:"version,omitempty"` // 페이로드 스키마 버전 (없으면 타입 없던 시절의 작업)
+	Payload   json.RawMessage        `json:"payload"`
 	CreatedAt time.Time              `json:"created_at"`
 	Attempts  int                    `json:"attempts"`
 	MaxRetries int                   `json:"max_retries"`
+	UniqueKey string                 `json:"unique_key,omitempty"` // 같은 키로 다시 넣으면 이전 작업을 대체
+
+	// 마지막 실패 기록
+	LastError  string     `json:"last_error,omitempty"`
+	ErrorStack string     `json:"error_stack,omitempty"` // 처리 중 패닉이 난 경우의 스택
+	FailedAt   *time.Time `json:"failed_at,omitempty"`
+
+	// Pop이 만든 전달 토큰 (처리 중 목록과 임대에서 이번 전달을 찾는 데 사용)
+	// 처리 함수, 임대 연장 고루틴, 종료 시 반환이 동시에 접근하므로 deliveryMu로 보호한다.
+	deliveryMu sync.Mutex
+	delivery   string
 }
 
 // 푸시 알림 작업 페이로드
@@ -59,6 +81,22 @@ type ExpireChatRoomPayload struct {
 	ChatRoomID uint `json:"chat_room_id"`
 }
 
+// 매너 점수 재계산 작업 페이로드 (평가를 받은 사용자 한 명)
+type UpdateMannerScorePayload struct {
+	UserID uint `json:"user_id"`
+}
+
+// 데이터 정리 작업 범위
+const (
+	CleanupChatArchives  = "chat_archives"  // 보관 기간이 지난 대화 기록
+	CleanupActiveSignals = "active_signals" // 지도 인덱스에 남은 종료된 시그널
+)
+
+// 데이터 정리 작업 페이로드
+type CleanupDataPayload struct {
+	Scope string `json:"scope"`
+}
+
 // 이메일 발송 작업 페이로드
 type EmailPayload struct {
 	To       string            `json:"to"`
@@ -84,27 +122,40 @@ func (q *Queue) Push(ctx context.Context, job *Job) error {
 		return fmt.Errorf("작업 직렬화 실패: %w", err)
 	}
 
-	queueKey := fmt.Sprintf("queue:%s", job.Type)
-	return q.client.LPush(ctx, queueKey, data)
+	if job.UniqueKey != "" {
+		return q.enqueueUnique(ctx, q.client.GetClient(), job, data, time.Time{}, false).Err()
+	}
+	return q.client.LPush(ctx, queueKey(job.Type), data)
 }
 
 // 작업 큐에서 가져오기 (블로킹)
+//
+// timeout 동안 작업이 없으면 ErrNoJob을 반환한다.
+// 가져온 작업은 처리 중 목록으로 옮겨지고 가시성 제한 시간 동안 임대된다.
+// 처리가 끝나면 Ack, 실패하면 Retry를 호출해야 하며, 둘 다 호출되지 않은 채 임대가
+// 만료되면(워커 비정상 종료 등) RequeueExpired가 작업을 다시 대기열에 넣는다.
 func (q *Queue) Pop(ctx context.Context, jobType JobType, timeout time.Duration) (*Job, error) {
-	queueKey := fmt.Sprintf("queue:%s", jobType)
-	
-	result, err := q.client.BRPop(ctx, timeout, queueKey)
+	data, err := q.client.GetClient().BLMove(ctx, queueKey(jobType), processingKey(jobType), "RIGHT", "LEFT", timeout).Result()
+	if err == redisClient.Nil {
+		return nil, ErrNoJob
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if len(result) != 2 {
-		return nil, fmt.Errorf("잘못된 큐 응답")
-	}
-
 	var job Job
-	if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
-		return nil, fmt.Errorf("작업 역직렬화 실패: %w", err)
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		// 처리할 수 없는 작업은 처리 중 목록에 남기지 않고 데드 레터 큐로 이동
+		if discardErr := q.discardDelivery(ctx, jobType, data); discardErr != nil {
+			return nil, fmt.Errorf("%w: %v (%v)", ErrInvalidJob, err, discardErr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJob, err)
 	}
+	redeliveries, err := q.lease(ctx, &job, data)
+	if err != nil {
+		return nil, fmt.Errorf("작업 임대 실패: %w", err)
+	}
+	job.Attempts += redeliveries
 
 	return &job, nil
 }
@@ -117,150 +168,110 @@ func (q *Queue) Schedule(ctx context.Context, job *Job, executeAt time.Time) err
 	if job.CreatedAt.IsZero() {
 		job.CreatedAt = time.Now()
 	}
+	if job.MaxRetries == 0 {
+		job.MaxRetries = 3
+	}
 
 	data, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("작업 직렬화 실패: %w", err)
 	}
 
-	delayedKey := "delayed_jobs"
-	score := float64(executeAt.Unix())
-	
-	return q.client.GetClient().ZAdd(ctx, delayedKey, redisClient.Z{
-		Score:  score,
+	if job.UniqueKey != "" {
+		return q.enqueueUnique(ctx, q.client.GetClient(), job, data, executeAt, false).Err()
+	}
+	return q.client.GetClient().ZAdd(ctx, delayedKey(job.Type), redisClient.Z{
+		Score:  delayedScore(executeAt),
 		Member: data,
 	}).Err()
 }
 
-// 실행 예정인 지연 작업들을 가져와서 일반 큐로 이동
+// 실행 예정인 지연 작업들을 일반 큐로 이동
+//
+// 여러 워커가 동시에 호출해도 작업마다 한 워커만 옮기도록 스크립트로 꺼내고 넣는다.
 func (q *Queue) ProcessDelayedJobs(ctx context.Context) error {
-	delayedKey := "delayed_jobs"
-	now := float64(time.Now().Unix())
-
-	// 현재 시간보다 이른 작업들을 가져옴
-	results, err := q.client.GetClient().ZRangeByScore(ctx, delayedKey, &redisClient.ZRangeBy{
-		Min: "-inf",
-		Max: fmt.Sprintf("%f", now),
-	}).Result()
-
-	if err != nil {
-		return fmt.Errorf("지연 작업 조회 실패: %w", err)
+	for _, jobType := range JobTypes {
+		if err := q.promoteDelayed(ctx, delayedKey(jobType), deadKey(jobType), []JobType{jobType}); err != nil {
+			return fmt.Errorf("%s 지연 작업 이동 실패: %w", jobType, err)
+		}
 	}
+	// 예전 delayed_jobs에는 모든 타입이 섞여 있음
+	if err := q.promoteDelayed(ctx, legacyDelayedKey, deadKey(unknownJobType), JobTypes); err != nil {
+		return fmt.Errorf("지연 작업 이동 실패: %w", err)
+	}
+	return nil
+}
 
-	for _, jobData := range results {
-		var job Job
-		if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-			log.Printf("지연 작업 역직렬화 실패: %v", err)
-			continue
-		}
+// promoteDelayed key에서 실행 시각이 된 jobTypes 작업을 각 타입의 큐로 이동 (그 밖의 작업은 dead로)
+func (q *Queue) promoteDelayed(ctx context.Context, key, dead string, jobTypes []JobType) error {
+	queueKeys, names := typedKeys(jobTypes, queueKey)
+	keys := append([]string{key, dead, uniqueDelayedJobsKey, uniqueJobsKey}, queueKeys...)
+	args := append([]interface{}{delayedScore(time.Now()), promoteBatchSize, legacyScoreLimit}, names...)
 
-		// 일반 큐로 이동
-		if err := q.Push(ctx, &job); err != nil {
-			log.Printf("지연 작업을 일반 큐로 이동 실패: %v", err)
-			continue
+	for {
+		promoted, err := promoteDelayedScript.Run(ctx, q.client.GetClient(), keys, args...).Int()
+		if err != nil {
+			return err
 		}
-
-		// 지연 큐에서 제거
-		if err := q.client.GetClient().ZRem(ctx, delayedKey, jobData).Err(); err != nil {
-			log.Printf("지연 큐에서 작업 제거 실패: %v", err)
+		if promoted < promoteBatchSize {
+			return nil
 		}
 	}
-
-	return nil
 }
 
-// 실패한 작업을 재시도 큐로 이동
+// 실패한 작업을 재시도 큐로 이동 (retryAfter부터 시도할 때마다 간격을 늘림, Pop으로 가져온 작업이면 처리 중 목록에서도 제거)
 func (q *Queue) Retry(ctx context.Context, job *Job, retryAfter time.Duration) error {
-	job.Attempts++
-	
-	if job.Attempts >= job.MaxRetries {
-		return q.moveToFailedQueue(ctx, job)
-	}
-
-	retryAt := time.Now().Add(retryAfter)
-	return q.Schedule(ctx, job, retryAt)
-}
-
-// 실패한 작업을 실패 큐로 이동
-func (q *Queue) moveToFailedQueue(ctx context.Context, job *Job) error {
-	data, err := json.Marshal(job)
-	if err != nil {
-		return fmt.Errorf("실패 작업 직렬화 실패: %w", err)
-	}
-
-	failedKey := "failed_jobs"
-	return q.client.LPush(ctx, failedKey, data)
+	return q.Fail(ctx, job, nil, Backoff{Base: retryAfter})
 }
 
-// 큐 상태 조회
+// 작업 타입별 큐 상태 조회 (대기, 처리 중, 예약, 데드 레터)
 func (q *Queue) GetQueueStats(ctx context.Context, jobType JobType) (map[string]int64, error) {
-	stats := make(map[string]int64)
-	
-	queueKey := fmt.Sprintf("queue:%s", jobType)
-	queueLen, err := q.client.LLen(ctx, queueKey)
-	if err != nil {
-		return nil, err
-	}
-	stats["pending"] = queueLen
-
-	delayedLen, err := q.client.GetClient().ZCard(ctx, "delayed_jobs").Result()
-	if err != nil {
+	pipe := q.client.GetClient().Pipeline()
+	pending := pipe.LLen(ctx, queueKey(jobType))
+	processing := pipe.LLen(ctx, processingKey(jobType))
+	delayed := pipe.ZCard(ctx, delayedKey(jobType))
+	dead := pipe.LLen(ctx, deadKey(jobType))
+	if _, err := pipe.Exec(ctx); err != nil {
 		return nil, err
 	}
-	stats["delayed"] = delayedLen
 
-	failedLen, err := q.client.LLen(ctx, "failed_jobs")
-	if err != nil {
-		return nil, err
-	}
-	stats["failed"] = failedLen
-
-	return stats, nil
+	return map[string]int64{
+		"pending":    pending.Val(),
+		"processing": processing.Val(),
+		"delayed":    delayed.Val(),
+		"dead":       dead.Val(),
+	}, nil
 }
 
 // 편의 메서드들
 
 // 푸시 알림 작업 추가
 func (q *Queue) PushNotification(ctx context.Context, userIDs []uint, title, body string, data map[string]string) error {
-	payload := map[string]interface{}{
-		"user_ids": userIDs,
-		"title":    title,
-		"body":     body,
-		"data":     data,
-	}
-
-	job := &Job{
-		Type:    JobSendPushNotification,
-		Payload: payload,
-	}
-
-	return q.Push(ctx, job)
+	return Enqueue(ctx, q, PushNotificationPayload{
+		UserIDs: userIDs,
+		Title:   title,
+		Body:    body,
+		Data:    data,
+	})
 }
 
-// 시그널 만료 작업 스케줄링
+// 시그널 만료 작업 스케줄링 (같은 시그널의 이전 예약은 대체)
 func (q *Queue) ScheduleSignalExpiration(ctx context.Context, signalID uint, expiresAt time.Time) error {
-	payload := map[string]interface{}{
-		"signal_id": signalID,
-	}
+	return EnqueueAt(ctx, q, ExpireSignalPayload{SignalID: signalID}, expiresAt)
+}
 
-	job := &Job{
-		Type:    JobExpireSignal,
-		Payload: payload,
-	}
+// 시그널 만료 작업 취소 (취소된 시그널)
+func (q *Queue) CancelSignalExpiration(ctx context.Context, signalID uint) error {
+	_, err := q.Cancel(ctx, ExpireSignalPayload{SignalID: signalID}.UniqueKey())
+	return err
+}
 
-	return q.Schedule(ctx, job, expiresAt)
+// 매너 점수 재계산 작업 추가 (이미 대기 중인 같은 사용자의 작업은 대체)
+func (q *Queue) UpdateMannerScore(ctx context.Context, userID uint) error {
+	return Enqueue(ctx, q, UpdateMannerScorePayload{UserID: userID})
 }
 
 // 채팅방 만료 작업 스케줄링
 func (q *Queue) ScheduleChatRoomExpiration(ctx context.Context, chatRoomID uint, expiresAt time.Time) error {
-	payload := map[string]interface{}{
-		"chat_room_id": chatRoomID,
-	}
-
-	job := &Job{
-		Type:    JobExpireChatRoom,
-		Payload: payload,
-	}
-
-	return q.Schedule(ctx, job, expiresAt)
-}
\ No newline at end of file
+	return EnqueueAt(ctx, q, ExpireChatRoomPayload{ChatRoomID: chatRoomID}, expiresAt)
+}
@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"signal-module/pkg/config"
+	"signal-module/pkg/redis"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+// 큐 테스트는 로컬 Redis의 테스트 전용 DB(REDIS_TEST_DB, 기본 15)를 비우고 실행한다.
+// 큐 키 이름이 고정되어 있으므로 실행 중인 워커와 겹치지 않도록 다른 DB를 쓴다.
+
+// newTestQueue 로컬 Redis 테스트 DB의 큐 (연결할 수 없으면 테스트 건너뜀)
+func newTestQueue(t *testing.T) (*Queue, *redis.Client) {
+	t.Helper()
+
+	cfg := &config.RedisConfig{Host: "localhost", Port: "6379", DB: 15}
+	if host := os.Getenv("REDIS_HOST"); host != "" {
+		cfg.Host = host
+	}
+	if port := os.Getenv("REDIS_PORT"); port != "" {
+		cfg.Port = port
+	}
+	if db, err := strconv.Atoi(os.Getenv("REDIS_TEST_DB")); err == nil {
+		cfg.DB = db
+	}
+
+	rdb, err := redis.New(cfg)
+	if err != nil {
+		t.Skipf("Redis를 사용할 수 없어 건너뜁니다: %v", err)
+	}
+	flush := func() {
+		if err := rdb.GetClient().FlushDB(context.Background()).Err(); err != nil {
+			t.Fatalf("테스트 DB 비우기 실패: %v", err)
+		}
+	}
+	flush()
+	t.Cleanup(func() {
+		flush()
+		rdb.Close()
+	})
+	return New(rdb), rdb
+}
+
+// pushTestJob 고유 키 없는 작업 추가
+func pushTestJob(t *testing.T, q *Queue, id string, maxRetries int) *Job {
+	t.Helper()
+
+	job, err := NewJob(PushNotificationPayload{UserIDs: []uint{}, Title: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.ID = id
+	job.MaxRetries = maxRetries
+	if err := q.Push(context.Background(), job); err != nil {
+		t.Fatalf("작업 추가 실패: %v", err)
+	}
+	return job
+}
+
+// popTestJob jobType 작업 하나를 가져옴 (없으면 실패)
+func popTestJob(t *testing.T, q *Queue, jobType JobType) *Job {
+	t.Helper()
+
+	job, err := q.Pop(context.Background(), jobType, time.Second)
+	if err != nil {
+		t.Fatalf("작업 가져오기 실패: %v", err)
+	}
+	return job
+}
+
+// expireLeases jobType의 모든 임대를 만료된 것으로 바꿈
+func expireLeases(t *testing.T, rdb *redis.Client, jobType JobType) {
+	t.Helper()
+
+	ctx := context.Background()
+	members, err := rdb.GetClient().ZRange(ctx, leasesKey(jobType), 0, -1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, member := range members {
+		rdb.GetClient().ZAdd(ctx, leasesKey(jobType), redisClient.Z{Score: 0, Member: member})
+	}
+}
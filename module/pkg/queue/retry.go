@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultBackoffBase = 30 * time.Second
+	defaultBackoffMax  = 1 * time.Hour
+)
+
+// Backoff 재시도 간격 (Base부터 시도할 때마다 두 배, 최대 Max)
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay attempt번째 실패 뒤 재시도까지 대기 시간
+//
+// 같은 순간 실패한 작업들이 한꺼번에 다시 몰리지 않도록 간격의 절반은 무작위로 정한다.
+func (b Backoff) Delay(attempt int) time.Duration {
+	base, max := b.Base, b.Max
+	if base <= 0 {
+		base = defaultBackoffBase
+	}
+	if max <= 0 {
+		max = defaultBackoffMax
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// PanicError 처리 함수가 패닉으로 끝난 경우 (스택은 실패 기록에 남김)
+type PanicError struct {
+	Value interface{}
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("작업 처리 중 패닉: %v", e.Value)
+}
+
+// Fail 실패 원인을 기록하고 backoff 간격 뒤 재시도 예약 (시도 횟수를 다 쓰면 데드 레터 큐로)
+//
+// 임대를 잃어 다른 워커에게 다시 전달된 작업이면 중복으로 예약하지 않고 ErrLeaseLost를 반환한다.
+func (q *Queue) Fail(ctx context.Context, job *Job, cause error, backoff Backoff) error {
+	owned, err := q.ownsDelivery(ctx, job)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		job.setDelivery("")
+		return ErrLeaseLost
+	}
+
+	job.Attempts++
+
+	now := time.Now()
+	if cause != nil {
+		job.LastError = cause.Error()
+		job.ErrorStack = ""
+		var panicErr *PanicError
+		if errors.As(cause, &panicErr) {
+			job.ErrorStack = panicErr.Stack
+		}
+		job.FailedAt = &now
+	}
+
+	if job.Attempts >= job.MaxRetries {
+		return q.moveToDeadLetter(ctx, job)
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("작업 직렬화 실패: %w", err)
+	}
+
+	retryAt := now.Add(backoff.Delay(job.Attempts))
+	_, err = q.client.GetClient().TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		if job.UniqueKey != "" {
+			// 그 사이 취소되거나 교체된 작업은 다시 예약하지 않음
+			q.enqueueUnique(ctx, pipe, job, data, retryAt, true)
+		} else {
+			pipe.ZAdd(ctx, delayedKey(job.Type), redisClient.Z{
+				Score:  delayedScore(retryAt),
+				Member: data,
+			})
+		}
+		q.ackIn(ctx, pipe, job)
+		return nil
+	})
+	if err == nil {
+		job.setDelivery("")
+	}
+	return err
+}
+
+// moveToDeadLetter 재시도를 다 쓴 작업을 작업 타입의 데드 레터 큐로 이동
+func (q *Queue) moveToDeadLetter(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("실패 작업 직렬화 실패: %w", err)
+	}
+
+	_, err = q.client.GetClient().TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		pipe.LPush(ctx, deadKey(job.Type), data)
+		// 작업 원본에 실패 기록이 있으므로 이전 임대 만료 기록은 지움
+		pipe.HDel(ctx, deadFailuresKey(job.Type), job.ID)
+		q.releaseIn(ctx, pipe, job)
+		q.ackIn(ctx, pipe, job)
+		return nil
+	})
+	if err == nil {
+		job.setDelivery("")
+	}
+	return err
+}
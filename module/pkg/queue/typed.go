@@ -0,0 +1,191 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Payload 작업 페이로드 (구조체마다 작업 타입과 스키마 버전이 정해져 있음)
+//
+// 필드 의미가 바뀌면 PayloadVersion을 올리고 PayloadUpgrader로 이전 버전을 변환한다.
+// 버전 0은 타입 없는 map으로 넣던 시절의 작업으로, 필드 이름이 같으므로 버전 1로 읽는다.
+type Payload interface {
+	JobType() JobType
+	PayloadVersion() int
+}
+
+// UniquePayload 같은 대상에 작업이 하나만 있어야 하는 페이로드 (새로 넣으면 이전 작업을 대체)
+type UniquePayload interface {
+	UniqueKey() string
+}
+
+// PayloadUpgrader 이전 버전 페이로드를 현재 버전 JSON으로 변환 (선택)
+type PayloadUpgrader interface {
+	UpgradePayload(version int, raw json.RawMessage) (json.RawMessage, error)
+}
+
+func (PushNotificationPayload) JobType() JobType    { return JobSendPushNotification }
+func (PushNotificationPayload) PayloadVersion() int { return 1 }
+
+func (ExpireSignalPayload) JobType() JobType    { return JobExpireSignal }
+func (ExpireSignalPayload) PayloadVersion() int { return 1 }
+func (p ExpireSignalPayload) UniqueKey() string {
+	return fmt.Sprintf("%s:%d", JobExpireSignal, p.SignalID)
+}
+
+func (ExpireChatRoomPayload) JobType() JobType    { return JobExpireChatRoom }
+func (ExpireChatRoomPayload) PayloadVersion() int { return 1 }
+func (p ExpireChatRoomPayload) UniqueKey() string {
+	return fmt.Sprintf("%s:%d", JobExpireChatRoom, p.ChatRoomID)
+}
+
+func (EmailPayload) JobType() JobType    { return JobSendEmail }
+func (EmailPayload) PayloadVersion() int { return 1 }
+
+func (UpdateMannerScorePayload) JobType() JobType    { return JobUpdateMannerScore }
+func (UpdateMannerScorePayload) PayloadVersion() int { return 1 }
+func (p UpdateMannerScorePayload) UniqueKey() string {
+	return fmt.Sprintf("%s:%d", JobUpdateMannerScore, p.UserID)
+}
+
+func (CleanupDataPayload) JobType() JobType    { return JobCleanupData }
+func (CleanupDataPayload) PayloadVersion() int { return 1 }
+func (p CleanupDataPayload) UniqueKey() string {
+	return fmt.Sprintf("%s:%s", JobCleanupData, p.Scope)
+}
+
+// NewJob 페이로드로 작업 생성
+func NewJob[T Payload](payload T) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s 페이로드 직렬화 실패: %w", payload.JobType(), err)
+	}
+
+	job := &Job{
+		Type:    payload.JobType(),
+		Version: payload.PayloadVersion(),
+		Payload: data,
+	}
+	if unique, ok := any(payload).(UniquePayload); ok {
+		job.UniqueKey = unique.UniqueKey()
+	}
+	return job, nil
+}
+
+// Enqueue 페이로드를 작업 타입의 큐에 추가
+func Enqueue[T Payload](ctx context.Context, q *Queue, payload T) error {
+	job, err := NewJob(payload)
+	if err != nil {
+		return err
+	}
+	return q.Push(ctx, job)
+}
+
+// EnqueueAt 페이로드를 executeAt에 실행되도록 예약
+func EnqueueAt[T Payload](ctx context.Context, q *Queue, payload T, executeAt time.Time) error {
+	job, err := NewJob(payload)
+	if err != nil {
+		return err
+	}
+	return q.Schedule(ctx, job, executeAt)
+}
+
+// DecodePayload 작업 페이로드를 T로 읽기 (이전 버전은 변환, 이 워커가 모르는 새 버전은 오류)
+func DecodePayload[T Payload](job *Job) (T, error) {
+	var payload T
+	if job.Type != payload.JobType() {
+		return payload, fmt.Errorf("작업 타입 불일치: %s (기대: %s)", job.Type, payload.JobType())
+	}
+
+	raw := job.Payload
+	version := job.Version
+	if version == 0 {
+		version = 1
+	}
+	if version > payload.PayloadVersion() {
+		return payload, fmt.Errorf("지원하지 않는 %s 페이로드 버전: %d", job.Type, job.Version)
+	}
+	if version < payload.PayloadVersion() {
+		upgrader, ok := any(payload).(PayloadUpgrader)
+		if !ok {
+			return payload, fmt.Errorf("%s 페이로드 버전 %d를 변환할 수 없습니다", job.Type, job.Version)
+		}
+		upgraded, err := upgrader.UpgradePayload(version, raw)
+		if err != nil {
+			return payload, fmt.Errorf("%s 페이로드 변환 실패: %w", job.Type, err)
+		}
+		raw = upgraded
+	}
+
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return payload, fmt.Errorf("%s 페이로드 역직렬화 실패: %w", job.Type, err)
+	}
+	return payload, nil
+}
+
+// Handler 작업 타입 하나를 처리하는 등록 항목
+type Handler struct {
+	Type    JobType
+	Name    string  // 로그에 쓰는 이름
+	Backoff Backoff // 실패 시 재시도 간격
+
+	process func(ctx context.Context, job *Job) error
+}
+
+// Process 작업 페이로드를 읽어 등록된 함수로 처리 (패닉은 스택을 담은 PanicError로 반환)
+func (h *Handler) Process(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = &PanicError{Value: recovered, Stack: string(debug.Stack())}
+		}
+	}()
+	return h.process(ctx, job)
+}
+
+// Registry 작업 타입별 처리 함수 목록 (워커는 등록된 타입마다 처리 루프를 띄움)
+type Registry struct {
+	handlers []*Handler
+	byType   map[JobType]*Handler
+}
+
+func NewRegistry() *Registry {
+	return &Registry{byType: make(map[JobType]*Handler)}
+}
+
+// Handle T 페이로드 작업의 처리 함수 등록 (같은 타입을 다시 등록하면 교체)
+func Handle[T Payload](r *Registry, name string, backoff Backoff, fn func(ctx context.Context, payload T) error) {
+	var zero T
+	handler := &Handler{
+		Type:    zero.JobType(),
+		Name:    name,
+		Backoff: backoff,
+		process: func(ctx context.Context, job *Job) error {
+			payload, err := DecodePayload[T](job)
+			if err != nil {
+				return err
+			}
+			return fn(ctx, payload)
+		},
+	}
+
+	if existing, ok := r.byType[handler.Type]; ok {
+		*existing = *handler
+		return
+	}
+	r.byType[handler.Type] = handler
+	r.handlers = append(r.handlers, handler)
+}
+
+// Handlers 등록 순서대로 처리 함수 목록
+func (r *Registry) Handlers() []*Handler {
+	return r.handlers
+}
+
+// Lookup 작업 타입의 처리 함수
+func (r *Registry) Lookup(jobType JobType) (*Handler, bool) {
+	handler, ok := r.byType[jobType]
+	return handler, ok
+}
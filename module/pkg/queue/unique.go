@@ -0,0 +1,155 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+// 고유 키와 중복 처리 방지
+//
+// UniqueKey가 있는 작업은 unique_jobs 해시에 키별 현재 작업 ID를 기록한다. 같은 키로 다시 넣으면
+// 예약 중이던 이전 작업을 지우고 현재 작업을 교체하며, 이미 큐에 들어간 이전 작업은 ShouldProcess가
+// 걸러낸다. Cancel은 현재 작업 기록을 지워 예약 중이든 대기 중이든 실행되지 않게 한다.
+// 예약 중인 작업의 원본은 unique_jobs:delayed 해시에 보관해 지연 큐에서 찾아 지울 수 있게 한다.
+//
+// 완료된 작업 ID는 job_done:<id>로 일정 시간 기록해, 임대 만료로 다시 전달된 작업이 두 번 처리되지 않게 한다.
+const (
+	uniqueJobsKey        = "unique_jobs"
+	uniqueDelayedJobsKey = "unique_jobs:delayed"
+
+	// 완료 기록 보관 시간 (재전달은 보통 임대 만료 직후라 하루면 충분)
+	jobDoneTTL = 24 * time.Hour
+)
+
+func jobDoneKey(jobID string) string {
+	return "job_done:" + jobID
+}
+
+// enqueueUniqueScript 고유 키 작업을 큐에 넣거나 예약하고 같은 키의 이전 예약 작업 제거
+//
+// KEYS: unique, unique_delayed, delayed, queue, legacy_delayed
+// ARGV: 고유 키, 작업 ID, 작업 데이터, 실행 시각(ms, 즉시 실행이면 빈 문자열), 재시도 여부("1")
+//
+// 재시도는 그 사이 취소되거나 다른 작업으로 교체되었으면 다시 넣지 않고 0을 반환한다.
+var enqueueUniqueScript = redisClient.NewScript(`
+if ARGV[5] == '1' and redis.call('HGET', KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+
+local previous = redis.call('HGET', KEYS[2], ARGV[1])
+if previous then
+	redis.call('ZREM', KEYS[3], previous)
+	redis.call('ZREM', KEYS[5], previous)
+	redis.call('HDEL', KEYS[2], ARGV[1])
+end
+
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+if ARGV[4] == '' then
+	redis.call('LPUSH', KEYS[4], ARGV[3])
+else
+	redis.call('ZADD', KEYS[3], ARGV[4], ARGV[3])
+	redis.call('HSET', KEYS[2], ARGV[1], ARGV[3])
+end
+return 1
+`)
+
+// cancelUniqueScript 고유 키의 현재 작업 취소 (예약 중이면 지연 큐에서도 제거)
+//
+// KEYS: unique, unique_delayed, legacy_delayed, 작업 타입별 delayed...
+// ARGV: 고유 키, KEYS[4]부터의 delayed에 대응하는 작업 타입...
+var cancelUniqueScript = redisClient.NewScript(`
+local cancelled = redis.call('HDEL', KEYS[1], ARGV[1])
+local previous = redis.call('HGET', KEYS[2], ARGV[1])
+if previous then
+	local ok, job = pcall(cjson.decode, previous)
+	if ok and type(job) == 'table' then
+		for i = 2, #ARGV do
+			if ARGV[i] == job['type'] then
+				redis.call('ZREM', KEYS[i + 2], previous)
+			end
+		end
+	end
+	redis.call('ZREM', KEYS[3], previous)
+	redis.call('HDEL', KEYS[2], ARGV[1])
+end
+return cancelled
+`)
+
+// releaseUniqueScript 끝난 작업이 아직 키의 현재 작업이면 기록 제거
+//
+// KEYS: unique
+// ARGV: 고유 키, 작업 ID
+var releaseUniqueScript = redisClient.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// enqueueUnique 고유 키 작업 추가 (executeAt이 0이면 즉시 실행)
+func (q *Queue) enqueueUnique(ctx context.Context, scripter redisClient.Scripter, job *Job, data []byte, executeAt time.Time, retry bool) *redisClient.Cmd {
+	score := ""
+	if !executeAt.IsZero() {
+		score = fmt.Sprintf("%.0f", delayedScore(executeAt))
+	}
+	retryFlag := "0"
+	if retry {
+		retryFlag = "1"
+	}
+
+	return enqueueUniqueScript.Eval(ctx, scripter,
+		[]string{uniqueJobsKey, uniqueDelayedJobsKey, delayedKey(job.Type), queueKey(job.Type), legacyDelayedKey},
+		job.UniqueKey, job.ID, data, score, retryFlag,
+	)
+}
+
+// Cancel 고유 키로 넣은 작업 취소 (취소할 작업이 있었는지 반환)
+func (q *Queue) Cancel(ctx context.Context, uniqueKey string) (bool, error) {
+	delayedKeys, names := typedKeys(JobTypes, delayedKey)
+	cancelled, err := cancelUniqueScript.Run(ctx, q.client.GetClient(),
+		append([]string{uniqueJobsKey, uniqueDelayedJobsKey, legacyDelayedKey}, delayedKeys...),
+		append([]interface{}{uniqueKey}, names...)...,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("작업 %s 취소 실패: %w", uniqueKey, err)
+	}
+	return cancelled > 0, nil
+}
+
+// ShouldProcess 이미 처리했거나 취소/교체된 작업이면 false (호출한 쪽은 처리 없이 Ack)
+func (q *Queue) ShouldProcess(ctx context.Context, job *Job) (bool, error) {
+	var owner *redisClient.StringCmd
+	pipe := q.client.GetClient().Pipeline()
+	done := pipe.Exists(ctx, jobDoneKey(job.ID))
+	if job.UniqueKey != "" {
+		owner = pipe.HGet(ctx, uniqueJobsKey, job.UniqueKey)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redisClient.Nil {
+		return false, err
+	}
+
+	if done.Val() > 0 {
+		return false, nil
+	}
+	if owner != nil && owner.Val() != job.ID {
+		return false, nil
+	}
+	return true, nil
+}
+
+// completeIn 트랜잭션에 완료 기록과 고유 키 해제 추가
+func (q *Queue) completeIn(ctx context.Context, pipe redisClient.Pipeliner, job *Job) {
+	pipe.Set(ctx, jobDoneKey(job.ID), 1, jobDoneTTL)
+	q.releaseIn(ctx, pipe, job)
+}
+
+// releaseIn 트랜잭션에 고유 키 해제 추가 (고유 키가 없으면 무시)
+func (q *Queue) releaseIn(ctx context.Context, pipe redisClient.Pipeliner, job *Job) {
+	if job.UniqueKey == "" {
+		return
+	}
+	releaseUniqueScript.Eval(ctx, pipe, []string{uniqueJobsKey}, job.UniqueKey, job.ID)
+}
@@ -0,0 +1,178 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// uniqueOwner 고유 키의 현재 작업 ID (없으면 빈 문자열)
+func uniqueOwner(t *testing.T, q *Queue, uniqueKey string) string {
+	t.Helper()
+
+	owner, _ := q.client.GetClient().HGet(context.Background(), uniqueJobsKey, uniqueKey).Result()
+	return owner
+}
+
+func TestCancelScheduledUniqueJob(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	payload := ExpireSignalPayload{SignalID: 1}
+
+	if err := EnqueueAt(ctx, q, payload, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("예약 실패: %v", err)
+	}
+	if n, _ := rdb.GetClient().ZCard(ctx, delayedKey(JobExpireSignal)).Result(); n != 1 {
+		t.Fatalf("예약된 작업 %d개", n)
+	}
+
+	cancelled, err := q.Cancel(ctx, payload.UniqueKey())
+	if err != nil || !cancelled {
+		t.Fatalf("취소: %v, %v", cancelled, err)
+	}
+	if n, _ := rdb.GetClient().ZCard(ctx, delayedKey(JobExpireSignal)).Result(); n != 0 {
+		t.Fatalf("취소 후 남은 예약 %d개", n)
+	}
+	if n, _ := rdb.GetClient().HLen(ctx, uniqueDelayedJobsKey).Result(); n != 0 {
+		t.Fatalf("취소 후 남은 예약 원본 %d개", n)
+	}
+	if owner := uniqueOwner(t, q, payload.UniqueKey()); owner != "" {
+		t.Fatalf("취소 후 남은 현재 작업: %s", owner)
+	}
+
+	if cancelled, _ := q.Cancel(ctx, payload.UniqueKey()); cancelled {
+		t.Fatal("이미 취소한 작업을 다시 취소했습니다")
+	}
+}
+
+func TestCancelQueuedUniqueJob(t *testing.T) {
+	q, _ := newTestQueue(t)
+	ctx := context.Background()
+	payload := UpdateMannerScorePayload{UserID: 1}
+
+	if err := Enqueue(ctx, q, payload); err != nil {
+		t.Fatalf("추가 실패: %v", err)
+	}
+	if cancelled, err := q.Cancel(ctx, payload.UniqueKey()); err != nil || !cancelled {
+		t.Fatalf("취소: %v, %v", cancelled, err)
+	}
+
+	// 이미 대기열에 들어간 작업은 가져와도 처리하지 않음
+	job := popTestJob(t, q, JobUpdateMannerScore)
+	if process, err := q.ShouldProcess(ctx, job); err != nil || process {
+		t.Fatalf("취소된 작업 처리 여부: %v, %v", process, err)
+	}
+}
+
+func TestRescheduleReplacesPreviousJob(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	payload := ExpireSignalPayload{SignalID: 1}
+
+	first := time.Now().Add(time.Hour)
+	second := time.Now().Add(2 * time.Hour)
+	if err := EnqueueAt(ctx, q, payload, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnqueueAt(ctx, q, payload, second); err != nil {
+		t.Fatal(err)
+	}
+
+	// 이전 예약은 지워지고 새 예약만 남음
+	scheduled, err := rdb.GetClient().ZRangeWithScores(ctx, delayedKey(JobExpireSignal), 0, -1).Result()
+	if err != nil || len(scheduled) != 1 {
+		t.Fatalf("예약된 작업: %v, %v", scheduled, err)
+	}
+	if scheduled[0].Score != delayedScore(second) {
+		t.Fatalf("예약 시각 %.0f (기대: %.0f)", scheduled[0].Score, delayedScore(second))
+	}
+
+	job := decodeDeadLetter(JobExpireSignal, scheduled[0].Member.(string), nil)
+	if owner := uniqueOwner(t, q, payload.UniqueKey()); owner != job.ID {
+		t.Fatalf("현재 작업 %s (기대: %s)", owner, job.ID)
+	}
+	if original, _ := rdb.GetClient().HGet(ctx, uniqueDelayedJobsKey, payload.UniqueKey()).Result(); original != scheduled[0].Member {
+		t.Fatalf("예약 원본이 새 예약과 다릅니다: %s", original)
+	}
+}
+
+func TestShouldProcess(t *testing.T) {
+	q, _ := newTestQueue(t)
+	ctx := context.Background()
+	payload := UpdateMannerScorePayload{UserID: 1}
+
+	// 같은 키로 두 번 넣으면 이전 작업은 처리하지 않음
+	if err := Enqueue(ctx, q, payload); err != nil {
+		t.Fatal(err)
+	}
+	if err := Enqueue(ctx, q, payload); err != nil {
+		t.Fatal(err)
+	}
+	replaced := popTestJob(t, q, JobUpdateMannerScore)
+	current := popTestJob(t, q, JobUpdateMannerScore)
+
+	if process, _ := q.ShouldProcess(ctx, replaced); process {
+		t.Fatal("교체된 작업을 처리합니다")
+	}
+	if process, _ := q.ShouldProcess(ctx, current); !process {
+		t.Fatal("현재 작업을 처리하지 않습니다")
+	}
+
+	// 완료한 작업이 다시 전달되어도 처리하지 않음
+	if err := q.Ack(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+	if process, _ := q.ShouldProcess(ctx, current); process {
+		t.Fatal("완료한 작업을 다시 처리합니다")
+	}
+
+	// 고유 키 없는 작업은 완료 기록만 확인
+	plain := pushTestJob(t, q, "job-1", 3)
+	if process, _ := q.ShouldProcess(ctx, plain); !process {
+		t.Fatal("고유 키 없는 작업을 처리하지 않습니다")
+	}
+}
+
+func TestExpiredDeadLetterReleasesUniqueOwner(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	payload := UpdateMannerScorePayload{UserID: 1}
+
+	if err := Enqueue(ctx, q, payload); err != nil {
+		t.Fatal(err)
+	}
+	expireUntilDead(t, q, rdb, JobUpdateMannerScore, 3)
+
+	if stats, _ := q.GetQueueStats(ctx, JobUpdateMannerScore); stats["dead"] != 1 {
+		t.Fatalf("데드 레터 큐: %v", stats)
+	}
+	if owner := uniqueOwner(t, q, payload.UniqueKey()); owner != "" {
+		t.Fatalf("데드 레터 이동 후 남은 현재 작업: %s", owner)
+	}
+}
+
+func TestReplayClearsJobDone(t *testing.T) {
+	q, rdb := newTestQueue(t)
+	ctx := context.Background()
+	payload := UpdateMannerScorePayload{UserID: 1}
+
+	if err := Enqueue(ctx, q, payload); err != nil {
+		t.Fatal(err)
+	}
+	expireUntilDead(t, q, rdb, JobUpdateMannerScore, 3)
+	dead, _, err := q.DeadLetters(ctx, JobUpdateMannerScore, 0, 1)
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("데드 레터: %v, %v", dead, err)
+	}
+
+	// 임대를 잃은 워커가 나중에 완료 처리한 작업
+	rdb.GetClient().Set(ctx, jobDoneKey(dead[0].ID), 1, jobDoneTTL)
+
+	if err := q.ReplayDeadLetter(ctx, JobUpdateMannerScore, dead[0].ID); err != nil {
+		t.Fatalf("다시 실행 실패: %v", err)
+	}
+	job := popTestJob(t, q, JobUpdateMannerScore)
+	if process, err := q.ShouldProcess(ctx, job); err != nil || !process {
+		t.Fatalf("다시 실행한 작업 처리 여부: %v, %v", process, err)
+	}
+}
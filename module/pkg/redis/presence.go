@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 사용자 접속 상태
+//
+// presence:user:<id> 정렬 집합에 연결마다 "<기기ID>|<연결ID>"를 만료 시각(밀리초) 점수로 보관하고
+// 연결이 살아 있는 동안 하트비트로 갱신한다. 연결 하나가 끊겨도 다른 기기나 연결이 남아 있으면 온라인이며,
+// 인스턴스가 비정상 종료되어 정리되지 못한 연결은 만료 시각이 지나면 무시된다.
+// 마지막 접속 시각은 presence:last_seen 해시에 사용자별로 기록한다.
+const presenceLastSeenKey = "presence:last_seen"
+
+func presenceUserKey(userID uint) string {
+	return "presence:user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+func presenceMember(deviceID, connectionID string) string {
+	return deviceID + "|" + connectionID
+}
+
+// PresenceState 사용자 접속 상태
+type PresenceState struct {
+	Online   bool
+	Devices  int       // 접속 중인 기기 수
+	LastSeen time.Time // 마지막 접속 시각 (기록이 없으면 0)
+}
+
+// TouchPresence 연결의 만료 시각을 now+ttl로 갱신하고 마지막 접속 시각 기록 (연결 시작과 하트비트)
+func (c *Client) TouchPresence(ctx context.Context, userID uint, deviceID, connectionID string, ttl time.Duration) error {
+	now := time.Now()
+	key := presenceUserKey(userID)
+
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Add(ttl).UnixMilli()), Member: presenceMember(deviceID, connectionID)})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.UnixMilli(), 10))
+		pipe.Expire(ctx, key, ttl)
+		pipe.HSet(ctx, presenceLastSeenKey, strconv.FormatUint(uint64(userID), 10), now.UnixMilli())
+		return nil
+	})
+	return err
+}
+
+// RemovePresence 끊긴 연결 제거 (마지막 접속 시각은 끊긴 시각)
+func (c *Client) RemovePresence(ctx context.Context, userID uint, deviceID, connectionID string) error {
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, presenceUserKey(userID), presenceMember(deviceID, connectionID))
+		pipe.HSet(ctx, presenceLastSeenKey, strconv.FormatUint(uint64(userID), 10), time.Now().UnixMilli())
+		return nil
+	})
+	return err
+}
+
+// IsUserOnline 만료되지 않은 연결이 하나라도 있으면 온라인
+func (c *Client) IsUserOnline(ctx context.Context, userID uint) (bool, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	count, err := c.rdb.ZCount(ctx, presenceUserKey(userID), now, "+inf").Result()
+	return count > 0, err
+}
+
+// GetPresence 여러 사용자의 접속 상태를 한 번에 조회
+func (c *Client) GetPresence(ctx context.Context, userIDs []uint) (map[uint]PresenceState, error) {
+	states := make(map[uint]PresenceState, len(userIDs))
+	if len(userIDs) == 0 {
+		return states, nil
+	}
+
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	fields := make([]string, len(userIDs))
+	members := make([]*redis.StringSliceCmd, len(userIDs))
+
+	pipe := c.rdb.Pipeline()
+	for i, userID := range userIDs {
+		fields[i] = strconv.FormatUint(uint64(userID), 10)
+		members[i] = pipe.ZRangeByScore(ctx, presenceUserKey(userID), &redis.ZRangeBy{Min: now, Max: "+inf"})
+	}
+	lastSeen := pipe.HMGet(ctx, presenceLastSeenKey, fields...)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	seen := lastSeen.Val()
+	for i, userID := range userIDs {
+		devices := make(map[string]struct{})
+		for _, member := range members[i].Val() {
+			deviceID, _, _ := strings.Cut(member, "|")
+			devices[deviceID] = struct{}{}
+		}
+
+		state := PresenceState{Online: len(devices) > 0, Devices: len(devices)}
+		if i < len(seen) {
+			if value, ok := seen[i].(string); ok {
+				if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+					state.LastSeen = time.UnixMilli(millis)
+				}
+			}
+		}
+		states[userID] = state
+	}
+
+	return states, nil
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -33,6 +34,11 @@ func New(cfg *config.RedisConfig) (*Client, error) {
 	return &Client{rdb: rdb}, nil
 }
 
+// NewFromClient 이미 만든 go-redis 클라이언트를 감싸기 (연결을 확인하지 않음)
+func NewFromClient(rdb *redis.Client) *Client {
+	return &Client{rdb: rdb}
+}
+
 func (c *Client) GetClient() *redis.Client {
 	return c.rdb
 }
@@ -46,6 +52,15 @@ func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	return c.rdb.Get(ctx, key).Result()
 }
 
+// GetDel 값을 읽으면서 삭제 (일회용 토큰 등, 키가 없으면 found=false)
+func (c *Client) GetDel(ctx context.Context, key string) (value string, found bool, err error) {
+	value, err = c.rdb.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	return value, err == nil, err
+}
+
 func (c *Client) Delete(ctx context.Context, keys ...string) error {
 	return c.rdb.Del(ctx, keys...).Err()
 }
@@ -54,6 +69,11 @@ func (c *Client) Exists(ctx context.Context, keys ...string) (int64, error) {
 	return c.rdb.Exists(ctx, keys...).Result()
 }
 
+// SetNX 키가 없을 때만 값을 설정 (분산 락 등)
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return c.rdb.SetNX(ctx, key, value, expiration).Result()
+}
+
 // 지리적 위치 관련 메서드들 (GEO 명령어)
 func (c *Client) GeoAdd(ctx context.Context, key string, locations ...*redis.GeoLocation) error {
 	return c.rdb.GeoAdd(ctx, key, locations...).Err()
@@ -109,6 +129,37 @@ func (c *Client) HDel(ctx context.Context, key string, fields ...string) error {
 	return c.rdb.HDel(ctx, key, fields...).Err()
 }
 
+func (c *Client) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	return c.rdb.HIncrBy(ctx, key, field, incr).Result()
+}
+
+// HGetAllAndDelete 해시를 읽고 삭제 (MULTI로 원자적 처리)
+func (c *Client) HGetAllAndDelete(ctx context.Context, key string) (map[string]string, error) {
+	var values *redis.MapStringStringCmd
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values.Val(), nil
+}
+
+// Sorted Set 관련 메서드들
+func (c *Client) ZAddNX(ctx context.Context, key string, score float64, member interface{}) error {
+	return c.rdb.ZAddNX(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+func (c *Client) ZRangeByScore(ctx context.Context, key, min, max string, count int64) ([]string, error) {
+	return c.rdb.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max, Count: count}).Result()
+}
+
+func (c *Client) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return c.rdb.ZRem(ctx, key, members...).Result()
+}
+
 // List 관련 메서드들 (작업 큐 등)
 func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) error {
 	return c.rdb.LPush(ctx, key, values...).Err()
@@ -135,6 +186,25 @@ func (c *Client) Subscribe(ctx context.Context, channels ...string) *redis.PubSu
 	return c.rdb.Subscribe(ctx, channels...)
 }
 
+// SubscribeConfirmed 채널을 구독하고 Redis의 구독 확인을 timeout까지 기다림 (확인 뒤에 발행된 메시지는 놓치지 않음)
+//
+// 확인에 실패해도 PubSub을 반환하며, Channel()이 재연결하면서 다시 구독한다.
+func (c *Client) SubscribeConfirmed(ctx context.Context, channel string, timeout time.Duration) (*redis.PubSub, error) {
+	pubsub := c.rdb.Subscribe(ctx, channel)
+
+	receiveCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		msg, err := pubsub.Receive(receiveCtx)
+		if err != nil {
+			return pubsub, err
+		}
+		if _, ok := msg.(*redis.Subscription); ok {
+			return pubsub, nil
+		}
+	}
+}
+
 // 만료 시간 관련
 func (c *Client) Expire(ctx context.Context, key string, expiration time.Duration) error {
 	return c.rdb.Expire(ctx, key, expiration).Err()
@@ -144,6 +214,20 @@ func (c *Client) TTL(ctx context.Context, key string) (time.Duration, error) {
 	return c.rdb.TTL(ctx, key).Result()
 }
 
+// IncrWithExpire 카운터를 증가시키고, 처음 생성된 키에만 만료 시간 설정 (고정 구간 카운터)
+func (c *Client) IncrWithExpire(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var count *redis.IntCmd
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
 // Signal 특화 메서드들
 func (c *Client) AddActiveSignal(ctx context.Context, signalID uint, latitude, longitude float64) error {
 	return c.GeoAdd(ctx, "active_signals", &redis.GeoLocation{
@@ -157,21 +241,67 @@ func (c *Client) RemoveActiveSignal(ctx context.Context, signalID uint) error {
 	return c.GeoRemove(ctx, "active_signals", fmt.Sprintf("signal:%d", signalID))
 }
 
+// ActiveSignalIDs 지도 인덱스에 등록된 시그널 ID 목록
+func (c *Client) ActiveSignalIDs(ctx context.Context) ([]uint, error) {
+	members, err := c.rdb.ZRange(ctx, "active_signals", 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		var id uint
+		if _, err := fmt.Sscanf(member, "signal:%d", &id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 func (c *Client) FindNearbySignals(ctx context.Context, longitude, latitude, radius float64) ([]redis.GeoLocation, error) {
 	return c.GeoRadius(ctx, "active_signals", longitude, latitude, radius)
 }
 
-// 사용자 온라인 상태 관리
-func (c *Client) SetUserOnline(ctx context.Context, userID uint) error {
-	return c.SAdd(ctx, "online_users", userID)
+// 시그널 변경 이벤트 채널 (모든 API 인스턴스의 지도 WebSocket이 구독)
+const SignalEventsChannel = "signal:events"
+
+// 재연결한 지도 클라이언트에게 다시 보낼 수 있는 시그널 이벤트 범위
+const (
+	SignalEventBufferSize = 5000
+	SignalEventBufferTTL  = 5 * time.Minute
+)
+
+// PublishSignalEvent 순번을 붙여 발행 (구독자는 SequencedMessage로 수신)
+func (c *Client) PublishSignalEvent(ctx context.Context, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("시그널 이벤트 직렬화 실패: %w", err)
+	}
+	_, err = c.PublishSequenced(ctx, SignalEventsChannel, data, SignalEventBufferSize, SignalEventBufferTTL)
+	return err
 }
 
-func (c *Client) SetUserOffline(ctx context.Context, userID uint) error {
-	return c.SRem(ctx, "online_users", userID)
+// 사용자별 실시간 알림 채널 (푸시 알림과 함께 발행, 지도 WebSocket/SSE가 대상 사용자에게 전달)
+const UserNotificationsChannel = "user:notifications"
+
+const (
+	UserNotificationBufferSize = 5000
+	UserNotificationBufferTTL  = 5 * time.Minute
+)
+
+// PublishUserNotification 순번을 붙여 발행 (구독자는 SequencedMessage로 수신)
+func (c *Client) PublishUserNotification(ctx context.Context, notification interface{}) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("사용자 알림 직렬화 실패: %w", err)
+	}
+	_, err = c.PublishSequenced(ctx, UserNotificationsChannel, data, UserNotificationBufferSize, UserNotificationBufferTTL)
+	return err
 }
 
-func (c *Client) IsUserOnline(ctx context.Context, userID uint) (bool, error) {
-	return c.SIsMember(ctx, "online_users", userID)
+// SubscribeRealtimeEvents 시그널 변경 이벤트와 사용자 알림을 함께 구독 (메시지의 Channel로 구분)
+func (c *Client) SubscribeRealtimeEvents(ctx context.Context) *redis.PubSub {
+	return c.Subscribe(ctx, SignalEventsChannel, UserNotificationsChannel)
 }
 
 func (c *Client) Close() error {
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 순번 키 만료 시간 (발행할 때마다 갱신, 만료 후 순번이 다시 시작되면 재연결한 클라이언트는 전체 다시 불러오기)
+const streamSeqTTL = 24 * time.Hour
+
+// SequencedMessage 순번이 붙은 채 발행된 메시지 ({"seq":N,"event":<원본>})
+type SequencedMessage struct {
+	Seq   int64           `json:"seq"`
+	Event json.RawMessage `json:"event"`
+}
+
+// StreamReplay 재연결한 클라이언트에게 다시 보낼 메시지
+type StreamReplay struct {
+	Messages []SequencedMessage
+	Latest   int64 // 현재 마지막 순번
+	Complete bool  // false면 요청한 순번 이후 일부가 이미 버퍼에서 밀려남
+}
+
+// 순번 발급, 재전송 버퍼 추가, 발행을 한 번에 처리해 모든 구독자가 순번 순서대로 받도록 함
+//
+// KEYS[1] 순번, KEYS[2] 재전송 버퍼 / ARGV: 채널, 원본 JSON, 버퍼 크기, 버퍼 만료(초), 순번 만료(초)
+var publishSequencedScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+local msg = '{"seq":' .. seq .. ',"event":' .. ARGV[2] .. '}'
+redis.call('ZADD', KEYS[2], seq, msg)
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -(tonumber(ARGV[3]) + 1))
+redis.call('EXPIRE', KEYS[2], ARGV[4])
+redis.call('PUBLISH', ARGV[1], msg)
+return seq
+`)
+
+// 현재 순번, 버퍼의 가장 오래된 순번, 요청한 순번 이후 메시지를 한 번에 조회
+var replayScript = redis.NewScript(`
+local latest = redis.call('GET', KEYS[1]) or '0'
+local oldest = redis.call('ZRANGE', KEYS[2], 0, 0, 'WITHSCORES')
+local msgs = redis.call('ZRANGEBYSCORE', KEYS[2], '(' .. ARGV[1], '+inf')
+return {latest, oldest[2] or '', msgs}
+`)
+
+func streamSeqKey(channel string) string    { return channel + ":seq" }
+func streamReplayKey(channel string) string { return channel + ":replay" }
+
+// PublishSequenced 채널에 순번을 붙여 발행하고 최근 bufferSize개를 ttl 동안 재전송용으로 보관
+func (c *Client) PublishSequenced(ctx context.Context, channel string, payload []byte, bufferSize int64, ttl time.Duration) (int64, error) {
+	keys := []string{streamSeqKey(channel), streamReplayKey(channel)}
+	return publishSequencedScript.Run(ctx, c.rdb, keys,
+		channel, string(payload), bufferSize, int64(ttl.Seconds()), int64(streamSeqTTL.Seconds())).Int64()
+}
+
+// StreamLatest 채널의 마지막 순번 (발행된 적이 없으면 0)
+func (c *Client) StreamLatest(ctx context.Context, channel string) (int64, error) {
+	latest, err := c.rdb.Get(ctx, streamSeqKey(channel)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return latest, err
+}
+
+// ReplayStream after 이후에 발행된 메시지 (순번 순)
+func (c *Client) ReplayStream(ctx context.Context, channel string, after int64) (*StreamReplay, error) {
+	keys := []string{streamSeqKey(channel), streamReplayKey(channel)}
+	result, err := replayScript.Run(ctx, c.rdb, keys, after).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 3 {
+		return nil, fmt.Errorf("재전송 버퍼 조회 결과 형식 오류")
+	}
+
+	latest, _ := strconv.ParseInt(fmt.Sprint(result[0]), 10, 64)
+	replay := &StreamReplay{Latest: latest}
+
+	if raw, ok := result[2].([]interface{}); ok {
+		for _, item := range raw {
+			var message SequencedMessage
+			if err := json.Unmarshal([]byte(fmt.Sprint(item)), &message); err != nil {
+				return nil, fmt.Errorf("재전송 메시지 역직렬화 실패: %w", err)
+			}
+			replay.Messages = append(replay.Messages, message)
+		}
+	}
+
+	// 빠진 구간 없이 이어지는지 확인 (순번이 초기화되어 after가 더 크면 이어받을 수 없음)
+	switch {
+	case after == latest:
+		replay.Complete = true
+	case after < latest:
+		oldest, err := strconv.ParseInt(fmt.Sprint(result[1]), 10, 64)
+		replay.Complete = err == nil && oldest <= after+1
+	}
+
+	return replay, nil
+}
+
+// DeleteStream 채널의 순번과 재전송 버퍼 삭제
+func (c *Client) DeleteStream(ctx context.Context, channel string) error {
+	return c.rdb.Del(ctx, streamSeqKey(channel), streamReplayKey(channel)).Err()
+}
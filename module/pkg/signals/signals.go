@@ -0,0 +1,43 @@
+package signals
+
+import (
+	"context"
+	"fmt"
+
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/redis"
+
+	"gorm.io/gorm"
+)
+
+// openStatuses 만료 처리 대상 상태 (모집 중, 정원 마감)
+var openStatuses = []models.SignalStatus{models.SignalActive, models.SignalFull}
+
+// CloseExpired 만료된 시그널을 닫고 지도 인덱스에서 제거한 뒤 구독자에게 만료 이벤트 발행
+//
+// 워커의 만료 작업과 스케줄러의 주기 정리가 함께 사용한다. 상태 조건을 걸어 변경하므로
+// 둘이 동시에 처리해도 한쪽만 이벤트를 발행하며, 이미 닫힌 시그널이면 아무것도 하지 않는다.
+func CloseExpired(ctx context.Context, db *gorm.DB, redisClient *redis.Client, signal *models.Signal, appLogger *logger.Logger) error {
+	result := db.WithContext(ctx).Model(&models.Signal{}).
+		Where("id = ? AND status IN ?", signal.ID, openStatuses).
+		Update("status", models.SignalClosed)
+	if result.Error != nil {
+		return fmt.Errorf("시그널 상태 업데이트 실패: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	// Redis에서 활성 시그널 제거 후 지도 구독자에게 전파
+	if err := redisClient.RemoveActiveSignal(ctx, signal.ID); err != nil {
+		appLogger.Warn(fmt.Sprintf("Redis 시그널 %d 제거 실패: %v", signal.ID, err))
+	}
+	signal.Status = models.SignalClosed
+	if err := redisClient.PublishSignalEvent(ctx, models.NewSignalEvent(models.SignalEventExpired, signal)); err != nil {
+		appLogger.Warn(fmt.Sprintf("시그널 %d 만료 이벤트 발행 실패: %v", signal.ID, err))
+	}
+
+	appLogger.LogSignalExpired(ctx, signal.ID)
+	return nil
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"signal-module/pkg/config"
+)
+
+var (
+	// ErrNotFound 객체가 존재하지 않음
+	ErrNotFound = errors.New("객체를 찾을 수 없습니다")
+	// ErrPresignNotSupported 서명된 다운로드 URL을 만들 수 없는 저장소 (API가 직접 전달해야 함)
+	ErrPresignNotSupported = errors.New("서명된 URL을 지원하지 않는 저장소입니다")
+)
+
+// BlobStore 첨부파일 저장소
+//
+// 키는 "chat/12/abcd.jpg" 처럼 슬래시로 구분된 상대 경로이며 ".."를 포함할 수 없다.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// New 설정에 맞는 저장소 생성
+func New(cfg *config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.LocalPath)
+	case "s3":
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("지원하지 않는 저장소 드라이버: %s", cfg.Driver)
+	}
+}
+
+// validateKey 저장소 밖을 가리키는 키 거부
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("잘못된 객체 키: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("잘못된 객체 키: %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // GIF 디코더 등록
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	MaxImagePixels    = 40_000_000 // 디코딩 허용 최대 픽셀 수 (압축 폭탄 방지)
+	ThumbnailMaxSize  = 320        // 썸네일 긴 변 최대 길이
+	imageJPEGQuality  = 90
+	thumbnailJPEGQual = 80
+)
+
+// ProcessedImage 메타데이터를 제거하고 다시 인코딩한 이미지와 썸네일
+type ProcessedImage struct {
+	ContentType          string
+	Data                 []byte
+	Width                int
+	Height               int
+	Thumbnail            []byte
+	ThumbnailContentType string
+}
+
+// ProcessImage 이미지를 다시 인코딩하여 EXIF(GPS 포함) 등 메타데이터를 제거하고 썸네일 생성
+//
+// JPEG은 EXIF 방향 정보를 픽셀에 반영한 뒤 저장하므로 메타데이터를 지워도 회전 상태가 유지된다.
+// GIF는 EXIF가 없으므로 원본을 그대로 두고 첫 프레임으로 썸네일만 만든다.
+func ProcessImage(data []byte, contentType string) (*ProcessedImage, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("이미지를 읽을 수 없습니다: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, fmt.Errorf("이미지 크기가 너무 큽니다 (%dx%d)", cfg.Width, cfg.Height)
+	}
+	if "image/"+format != contentType {
+		return nil, fmt.Errorf("파일 형식이 일치하지 않습니다 (%s)", format)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("이미지 디코딩 실패: %w", err)
+	}
+
+	img := toRGBA(src)
+	result := &ProcessedImage{ContentType: contentType}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		img = applyOrientation(img, jpegOrientation(data))
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: imageJPEGQuality}); err != nil {
+			return nil, fmt.Errorf("이미지 인코딩 실패: %w", err)
+		}
+		result.Data = buf.Bytes()
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("이미지 인코딩 실패: %w", err)
+		}
+		result.Data = buf.Bytes()
+	case "gif":
+		result.Data = data
+	default:
+		return nil, fmt.Errorf("지원하지 않는 이미지 형식입니다: %s", format)
+	}
+
+	bounds := img.Bounds()
+	result.Width, result.Height = bounds.Dx(), bounds.Dy()
+
+	thumb := resizeToFit(img, ThumbnailMaxSize)
+	var thumbBuf bytes.Buffer
+	if format == "png" {
+		// 투명도 유지
+		err = png.Encode(&thumbBuf, thumb)
+		result.ThumbnailContentType = "image/png"
+	} else {
+		err = jpeg.Encode(&thumbBuf, flatten(thumb), &jpeg.Options{Quality: thumbnailJPEGQual})
+		result.ThumbnailContentType = "image/jpeg"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("썸네일 인코딩 실패: %w", err)
+	}
+	result.Thumbnail = thumbBuf.Bytes()
+
+	return result, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// flatten 투명 영역을 흰색 배경으로 채움 (JPEG 썸네일용)
+func flatten(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, image.Point{}, draw.Over)
+	return dst
+}
+
+// resizeToFit 긴 변이 maxSize 이하가 되도록 영역 평균(box filter)으로 축소
+func resizeToFit(src *image.RGBA, maxSize int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if sw <= maxSize && sh <= maxSize {
+		return src
+	}
+
+	dw, dh := maxSize, sh*maxSize/sw
+	if sh > sw {
+		dw, dh = sw*maxSize/sh, maxSize
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0, sy1 := y*sh/dh, (y+1)*sh/dh
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < dw; x++ {
+			sx0, sx1 := x*sw/dw, (x+1)*sw/dw
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := sx0; sx < sx1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+// applyOrientation EXIF 방향 값(1~8)에 맞게 픽셀을 회전/반전
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 좌우 반전
+				dx, dy = w-1-x, y
+			case 3: // 180도 회전
+				dx, dy = w-1-x, h-1-y
+			case 4: // 상하 반전
+				dx, dy = x, h-1-y
+			case 5: // 주대각선 기준 반전
+				dx, dy = y, x
+			case 6: // 시계 방향 90도
+				dx, dy = h-1-y, x
+			case 7: // 부대각선 기준 반전
+				dx, dy = h-1-y, w-1-x
+			case 8: // 반시계 방향 90도
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], src.Pix[y*src.Stride+x*4:y*src.Stride+x*4+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation JPEG APP1(EXIF) 세그먼트에서 방향 태그(0x0112) 값을 읽음 (없으면 1)
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xD9 || marker == 0xDA { // EOI, SOS 이후에는 메타데이터 없음
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
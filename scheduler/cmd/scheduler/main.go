@@ -43,7 +43,7 @@ func main() {
 	jobQueue := queue.New(redisClient)
 
 	// 서비스 초기화
-	signalScheduler := services.NewSignalSchedulerService(db.DB, jobQueue, appLogger)
+	signalScheduler := services.NewSignalSchedulerService(db.DB, jobQueue, redisClient, appLogger)
 
 	// 스케줄러들 시작
 	ctx, cancel := context.WithCancel(context.Background())
@@ -8,30 +8,34 @@ import (
 	"signal-module/pkg/logger"
 	"signal-module/pkg/models"
 	"signal-module/pkg/queue"
+	"signal-module/pkg/redis"
+	"signal-module/pkg/signals"
 
 	"gorm.io/gorm"
 )
 
 type SignalSchedulerService struct {
-	db       *gorm.DB
-	queue    *queue.Queue
-	logger   *logger.Logger
+	db          *gorm.DB
+	queue       *queue.Queue
+	redisClient *redis.Client
+	logger      *logger.Logger
 }
 
-func NewSignalSchedulerService(db *gorm.DB, queue *queue.Queue, logger *logger.Logger) *SignalSchedulerService {
+func NewSignalSchedulerService(db *gorm.DB, queue *queue.Queue, redisClient *redis.Client, logger *logger.Logger) *SignalSchedulerService {
 	return &SignalSchedulerService{
-		db:     db,
-		queue:  queue,
-		logger: logger,
+		db:          db,
+		queue:       queue,
+		redisClient: redisClient,
+		logger:      logger,
 	}
 }
 
-// 만료된 시그널들을 처리
+// 만료된 시그널들을 처리 (정확한 만료 시각 처리는 워커의 expire_signal 작업, 여기서는 놓친 시그널 정리)
 func (s *SignalSchedulerService) ProcessExpiredSignals(ctx context.Context) error {
 	var expiredSignals []models.Signal
 
-	// 만료 시간이 지난 활성 시그널들 조회
-	if err := s.db.Where("status = ? AND expires_at < ?", models.SignalActive, time.Now()).Find(&expiredSignals).Error; err != nil {
+	// 만료 시간이 지난 활성/정원 마감 시그널들 조회
+	if err := s.db.Where("status IN ? AND expires_at < ?", []models.SignalStatus{models.SignalActive, models.SignalFull}, time.Now()).Find(&expiredSignals).Error; err != nil {
 		return fmt.Errorf("만료된 시그널 조회 실패: %w", err)
 	}
 
@@ -43,15 +47,10 @@ func (s *SignalSchedulerService) ProcessExpiredSignals(ctx context.Context) erro
 
 	// 각 시그널을 만료 상태로 변경
 	for _, signal := range expiredSignals {
-		if err := s.db.Model(&signal).Update("status", models.SignalClosed).Error; err != nil {
-			s.logger.Error(fmt.Sprintf("시그널 %d 상태 업데이트 실패", signal.ID), err)
-			continue
+		// 워커의 만료 작업이 먼저 닫았으면 건너뜀
+		if err := signals.CloseExpired(ctx, s.db, s.redisClient, &signal, s.logger); err != nil {
+			s.logger.Error(fmt.Sprintf("시그널 %d 만료 처리 실패", signal.ID), err)
 		}
-
-		// Redis에서 활성 시그널 제거
-		// TODO: Redis 연동 추가
-
-		s.logger.LogSignalExpired(ctx, signal.ID)
 	}
 
 	s.logger.Info(fmt.Sprintf("만료된 시그널 처리 완료: %d개", len(expiredSignals)))
@@ -108,8 +107,8 @@ func (s *SignalSchedulerService) CreateChatRoomsForFullSignals(ctx context.Conte
 func (s *SignalSchedulerService) ScheduleExpiredChatRooms(ctx context.Context) error {
 	var expiredRooms []models.ChatRoom
 
-	// 만료 시간이 지난 활성 채팅방들 조회
-	if err := s.db.Where("status = ? AND expires_at < ?", models.ChatRoomActive, time.Now()).Find(&expiredRooms).Error; err != nil {
+	// 만료 시간이 지난 활성 채팅방들 조회 (WebSocket 서버가 숨긴 채팅방 포함)
+	if err := s.db.Unscoped().Where("status = ? AND expires_at < ?", models.ChatRoomActive, time.Now()).Find(&expiredRooms).Error; err != nil {
 		return fmt.Errorf("만료된 채팅방 조회 실패: %w", err)
 	}
 
@@ -153,42 +152,24 @@ func (s *SignalSchedulerService) UpdateMannerScores(ctx context.Context) error {
 
 		// 최근 30일간의 평가들을 조회하여 새로운 매너 점수 계산
 		var ratings []models.UserRating
-		thirtyDaysAgo := time.Now().AddDate(0, 0, -30)
-		
-		if err := s.db.Where("ratee_id = ? AND created_at > ?", user.ID, thirtyDaysAgo).Find(&ratings).Error; err != nil {
+		since := time.Now().Add(-models.MannerScoreWindow)
+
+		if err := s.db.Where("ratee_id = ? AND created_at > ?", user.ID, since).Find(&ratings).Error; err != nil {
+			continue
+		}
+
+		newScore, noShowCount, ok := models.CalculateMannerScore(ratings)
+		if !ok {
 			continue
 		}
 
-		if len(ratings) > 0 {
-			totalScore := 0
-			noShowCount := 0
-			
-			for _, rating := range ratings {
-				totalScore += rating.Score
-				if rating.IsNoShow {
-					noShowCount++
-				}
-			}
-
-			newScore := float64(totalScore) / float64(len(ratings))
-			
-			// 노쇼에 대한 패널티 적용
-			if noShowCount > 0 {
-				penalty := float64(noShowCount) * 0.5
-				newScore = newScore - penalty
-				if newScore < 1.0 {
-					newScore = 1.0
-				}
-			}
-
-			// 매너 점수 업데이트
-			if err := s.db.Model(user.Profile).Updates(map[string]interface{}{
-				"manner_score":   newScore,
-				"total_ratings":  len(ratings),
-				"no_show_count":  noShowCount,
-			}).Error; err != nil {
-				s.logger.Error(fmt.Sprintf("사용자 %d 매너 점수 업데이트 실패", user.ID), err)
-			}
+		// 매너 점수 업데이트
+		if err := s.db.Model(user.Profile).Updates(map[string]interface{}{
+			"manner_score":  newScore,
+			"total_ratings": len(ratings),
+			"no_show_count": noShowCount,
+		}).Error; err != nil {
+			s.logger.Error(fmt.Sprintf("사용자 %d 매너 점수 업데이트 실패", user.ID), err)
 		}
 	}
 
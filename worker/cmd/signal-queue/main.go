@@ -0,0 +1,218 @@
+// signal-queue 작업 큐 상태 확인과 데드 레터 큐 관리 도구
+//
+//	signal-queue stats
+//	signal-queue list <type> [-offset 0] [-limit 20]
+//	signal-queue inspect <type> <job_id>
+//	signal-queue replay <type> <job_id>|-all
+//	signal-queue purge <type> <job_id>|-all
+//
+// Redis 연결 정보는 서버와 같은 환경변수(.env)를 사용한다.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"signal-module/pkg/config"
+	"signal-module/pkg/queue"
+	"signal-module/pkg/redis"
+)
+
+const usage = `사용법: signal-queue <명령> [옵션]
+
+명령:
+  stats                          작업 타입별 대기/처리 중/예약/데드 레터 개수
+  list <type> [-offset N] [-limit N]
+                                 데드 레터 작업 목록 (최근 실패 순)
+  inspect <type> <job_id>        데드 레터 작업의 페이로드와 실패 기록
+  replay <type> <job_id>|-all    데드 레터 작업을 시도 횟수를 초기화해 다시 실행
+  purge <type> <job_id>|-all     데드 레터 작업 삭제
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+	redisClient, err := redis.New(&cfg.Redis)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Redis 연결 실패: %v\n", err)
+		os.Exit(1)
+	}
+	defer redisClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	jobQueue := queue.New(redisClient)
+	command, args := os.Args[1], os.Args[2:]
+
+	switch command {
+	case "stats":
+		err = runStats(ctx, jobQueue)
+	case "list":
+		err = runList(ctx, jobQueue, args)
+	case "inspect":
+		err = runInspect(ctx, jobQueue, args)
+	case "replay":
+		err = runReplay(ctx, jobQueue, args)
+	case "purge":
+		err = runPurge(ctx, jobQueue, args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		err = fmt.Errorf("알 수 없는 명령: %s\n\n%s", command, usage)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func runStats(ctx context.Context, jobQueue *queue.Queue) error {
+	stats, err := jobQueue.AllQueueStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tPENDING\tPROCESSING\tDELAYED\tDEAD")
+	for _, jobType := range queue.JobTypes {
+		s := stats[jobType]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", jobType, s["pending"], s["processing"], s["delayed"], s["dead"])
+	}
+	return w.Flush()
+}
+
+func runList(ctx context.Context, jobQueue *queue.Queue, args []string) error {
+	jobType, rest, err := parseJobTypeArg(args)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	offset := flags.Int64("offset", 0, "건너뛸 개수")
+	limit := flags.Int64("limit", 20, "최대 개수")
+	if err := flags.Parse(rest); err != nil {
+		return err
+	}
+
+	jobs, total, err := jobQueue.DeadLetters(ctx, jobType, *offset, *limit)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB ID\tATTEMPTS\tFAILED AT\tLAST ERROR")
+	for _, job := range jobs {
+		failedAt := "-"
+		if job.FailedAt != nil {
+			failedAt = job.FailedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", job.ID, job.Attempts, failedAt, firstLine(job.LastError))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s 데드 레터 %d건 중 %d건\n", jobType, total, len(jobs))
+	return nil
+}
+
+func runInspect(ctx context.Context, jobQueue *queue.Queue, args []string) error {
+	jobType, rest, err := parseJobTypeArg(args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("작업 ID가 필요합니다")
+	}
+
+	job, err := jobQueue.DeadLetter(ctx, jobType, rest[0])
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(job)
+}
+
+func runReplay(ctx context.Context, jobQueue *queue.Queue, args []string) error {
+	jobType, rest, err := parseJobTypeArg(args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("작업 ID 또는 -all이 필요합니다")
+	}
+
+	if rest[0] == "-all" {
+		replayed, err := jobQueue.ReplayDeadLetters(ctx, jobType)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s 작업 %d건을 다시 큐에 넣었습니다\n", jobType, replayed)
+		return nil
+	}
+
+	if err := jobQueue.ReplayDeadLetter(ctx, jobType, rest[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s 작업 %s를 다시 큐에 넣었습니다\n", jobType, rest[0])
+	return nil
+}
+
+func runPurge(ctx context.Context, jobQueue *queue.Queue, args []string) error {
+	jobType, rest, err := parseJobTypeArg(args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("작업 ID 또는 -all이 필요합니다")
+	}
+
+	if rest[0] == "-all" {
+		purged, err := jobQueue.PurgeDeadLetters(ctx, jobType)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s 데드 레터 %d건을 삭제했습니다\n", jobType, purged)
+		return nil
+	}
+
+	if err := jobQueue.PurgeDeadLetter(ctx, jobType, rest[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s 작업 %s를 삭제했습니다\n", jobType, rest[0])
+	return nil
+}
+
+func parseJobTypeArg(args []string) (queue.JobType, []string, error) {
+	if len(args) == 0 {
+		return "", nil, errors.New("작업 타입이 필요합니다")
+	}
+	jobType, ok := queue.ParseJobType(args[0])
+	if !ok {
+		return "", nil, fmt.Errorf("알 수 없는 작업 타입: %s", args[0])
+	}
+	return jobType, args[1:], nil
+}
+
+func firstLine(value string) string {
+	if line, _, found := strings.Cut(value, "\n"); found {
+		return line
+	}
+	return value
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"os"
 	"os/signal"
 	"sync"
@@ -12,11 +14,16 @@ import (
 
 	"signal-module/pkg/config"
 	"signal-module/pkg/database"
+	"signal-module/pkg/encryption"
 	"signal-module/pkg/logger"
 	"signal-module/pkg/queue"
 	"signal-module/pkg/redis"
+	"signal-module/pkg/storage"
 )
 
+// jobCancelTimeout 종료 대기 시간이 지나 처리를 취소한 뒤 처리 함수가 끝나기를 기다리는 시간
+const jobCancelTimeout = 5 * time.Second
+
 func main() {
 	cfg := config.LoadConfig()
 
@@ -39,46 +46,108 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	// 첨부파일 저장소 (채팅방 만료 시 파일 삭제)
+	blobStore, err := storage.New(&cfg.Storage)
+	if err != nil {
+		appLogger.Error("첨부파일 저장소 초기화 실패", err)
+		os.Exit(1)
+	}
+
+	// 신고된 채팅방 대화 보관용 암호화 키
+	archiveKey, err := encryption.ChatArchiveKey(&cfg.Chat)
+	if err != nil {
+		appLogger.Error("채팅 보관 키 설정 오류", err)
+		os.Exit(1)
+	}
+
 	// 큐 시스템 초기화
 	jobQueue := queue.New(redisClient)
+	if moved, err := jobQueue.MigrateLegacyFailedJobs(context.Background()); err != nil {
+		appLogger.Warn(fmt.Sprintf("이전 실패 작업 이동 실패: %v", err))
+	} else if moved > 0 {
+		appLogger.Info(fmt.Sprintf("이전 실패 작업 %d건을 타입별 데드 레터 큐로 옮겼습니다", moved))
+	}
 
 	// 서비스 초기화
 	pushService := services.NewPushNotificationService(cfg, appLogger)
 	emailService := services.NewEmailService(appLogger)
-	chatService := services.NewChatCleanupService(db.DB, appLogger)
+	chatService := services.NewChatCleanupService(db.DB, blobStore, archiveKey, cfg.Chat.ArchiveRetention, appLogger)
+	signalExpirationService := services.NewSignalExpirationService(db.DB, redisClient, appLogger)
+	mannerScoreService := services.NewMannerScoreService(db.DB, appLogger)
+	dataCleanupService := services.NewDataCleanupService(db.DB, redisClient, chatService, appLogger)
+
+	// 작업 타입별 처리 함수 등록
+	registry := queue.NewRegistry()
+	queue.Handle(registry, "푸시 알림", queue.Backoff{Base: 30 * time.Second, Max: 10 * time.Minute}, pushService.ProcessPushNotificationJob)
+	queue.Handle(registry, "이메일", queue.Backoff{Base: 1 * time.Minute, Max: 1 * time.Hour}, emailService.ProcessEmailJob)
+	queue.Handle(registry, "채팅방 정리", queue.Backoff{Base: 5 * time.Minute, Max: 2 * time.Hour}, chatService.ProcessChatRoomExpirationJob)
+	queue.Handle(registry, "시그널 만료", queue.Backoff{Base: 10 * time.Second, Max: 5 * time.Minute}, signalExpirationService.ProcessExpireSignalJob)
+	queue.Handle(registry, "매너 점수", queue.Backoff{Base: 30 * time.Second, Max: 30 * time.Minute}, mannerScoreService.ProcessUpdateMannerScoreJob)
+	queue.Handle(registry, "데이터 정리", queue.Backoff{Base: 5 * time.Minute, Max: 1 * time.Hour}, dataCleanupService.ProcessCleanupDataJob)
+
+	// 선언은 되어 있지만 처리할 워커가 없는 작업 타입 (큐에 쌓이기만 함)
+	for _, jobType := range queue.JobTypes {
+		if _, ok := registry.Lookup(jobType); !ok {
+			appLogger.Warn(fmt.Sprintf("%s 작업을 처리할 핸들러가 등록되지 않았습니다", jobType))
+		}
+	}
+
+	// 설정에 오타가 있으면 기본값으로 실행되므로 알림
+	for name := range cfg.Worker.Concurrency {
+		if _, ok := queue.ParseJobType(name); !ok {
+			appLogger.Warn(fmt.Sprintf("WORKER_CONCURRENCY에 알 수 없는 작업 타입: %s", name))
+		}
+	}
+	for name := range cfg.Worker.RateLimits {
+		if _, ok := queue.ParseJobType(name); !ok {
+			appLogger.Warn(fmt.Sprintf("WORKER_RATE_LIMITS에 알 수 없는 작업 타입: %s", name))
+		}
+	}
 
 	// Worker들 시작
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// fetchCtx는 종료 신호를 받으면 바로 취소해 새 작업을 가져오지 않게 하고,
+	// jobCtx는 처리 중인 작업이 끝나기를 기다리다 종료 대기 시간이 지나면 취소한다.
+	fetchCtx, stopFetching := context.WithCancel(context.Background())
+	defer stopFetching()
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
 
 	var wg sync.WaitGroup
 
-	// 푸시 알림 워커
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		runPushNotificationWorker(ctx, jobQueue, pushService, appLogger)
-	}()
+	// 등록된 작업 타입마다 처리 풀 실행 (전체 처리 슬롯은 우선순위에 따라 나눠 씀)
+	slots := newPrioritySemaphore(cfg.Worker.MaxConcurrency)
+	inFlight := newInFlightJobs()
+	for _, handler := range registry.Handlers() {
+		settings := poolSettingsFor(handler.Type, cfg.Worker)
+		pool := newJobPool(jobQueue, handler, settings, slots, inFlight, appLogger)
+		appLogger.Info(fmt.Sprintf("%s 워커: 동시 처리 %d개, 우선순위 %d", handler.Name, settings.Concurrency, settings.Priority))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.run(fetchCtx, jobCtx)
+		}()
+	}
 
-	// 이메일 워커
+	// 보관 기간이 지난 대화 기록 삭제 등 주기적인 데이터 정리 작업 예약
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runEmailWorker(ctx, jobQueue, emailService, appLogger)
+		runDataCleanupScheduler(fetchCtx, jobQueue, appLogger)
 	}()
 
-	// 채팅방 정리 워커
+	// 워커가 멈춰 임대가 만료된 처리 중 작업 회수
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runChatCleanupWorker(ctx, jobQueue, chatService, appLogger)
+		runQueueReaper(fetchCtx, jobQueue, appLogger)
 	}()
 
 	// 지연 작업 처리 워커
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runDelayedJobProcessor(ctx, jobQueue, appLogger)
+		runDelayedJobProcessor(fetchCtx, jobQueue, appLogger)
 	}()
 
 	appLogger.Info("✅ 모든 워커가 시작되었습니다")
@@ -88,89 +157,113 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	appLogger.Info("🛑 Worker 종료 중...")
+	appLogger.Info("🛑 Worker 종료 중... (새 작업 가져오기 중단)")
 
-	cancel()
-	wg.Wait()
+	stopFetching()
+	if released, finished := drainPools(&wg, cfg.Worker.ShutdownTimeout, jobCancelTimeout, cancelJobs, inFlight, jobQueue, appLogger); !finished {
+		appLogger.Warn(fmt.Sprintf("종료 대기 시간(%s)이 지나 처리 중이던 작업 %d건을 큐로 되돌렸습니다", cfg.Worker.ShutdownTimeout, released))
+	}
 
 	appLogger.Info("✅ Worker가 정상적으로 종료되었습니다")
 }
 
-func runPushNotificationWorker(ctx context.Context, jobQueue *queue.Queue, pushService *services.PushNotificationService, appLogger *logger.Logger) {
+// runDataCleanupScheduler 매시간 정리 범위별 작업을 큐에 추가
+// (범위마다 고유 키가 있어 워커 인스턴스가 여러 개여도 한 번만 실행됨)
+func runDataCleanupScheduler(ctx context.Context, jobQueue *queue.Queue, appLogger *logger.Logger) {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	scopes := []string{queue.CleanupChatArchives, queue.CleanupActiveSignals}
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			job, err := jobQueue.Pop(ctx, queue.JobSendPushNotification, 5*time.Second)
-			if err != nil {
-				continue
-			}
-
-			if err := pushService.ProcessPushNotificationJob(ctx, job); err != nil {
-				appLogger.Error("푸시 알림 처리 실패", err)
-				if err := jobQueue.Retry(ctx, job, 30*time.Second); err != nil {
-					appLogger.Error("푸시 알림 재시도 실패", err)
+		case <-ticker.C:
+			for _, scope := range scopes {
+				if err := queue.Enqueue(ctx, jobQueue, queue.CleanupDataPayload{Scope: scope}); err != nil {
+					appLogger.Error(fmt.Sprintf("%s 정리 작업 추가 실패", scope), err)
 				}
 			}
 		}
 	}
 }
 
-func runEmailWorker(ctx context.Context, jobQueue *queue.Queue, emailService *services.EmailService, appLogger *logger.Logger) {
+// runDelayedJobProcessor 1초 남짓마다 실행 시각이 된 지연 작업을 큐로 이동
+// (여러 워커 인스턴스가 같은 순간에 몰리지 않도록 간격에 무작위 지연을 더함)
+func runDelayedJobProcessor(ctx context.Context, jobQueue *queue.Queue, appLogger *logger.Logger) {
+	timer := time.NewTimer(delayedJobInterval())
+	defer timer.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			job, err := jobQueue.Pop(ctx, queue.JobSendEmail, 5*time.Second)
-			if err != nil {
-				continue
-			}
-
-			if err := emailService.ProcessEmailJob(ctx, job); err != nil {
-				appLogger.Error("이메일 처리 실패", err)
-				if err := jobQueue.Retry(ctx, job, 1*time.Minute); err != nil {
-					appLogger.Error("이메일 재시도 실패", err)
-				}
+		case <-timer.C:
+			if err := jobQueue.ProcessDelayedJobs(ctx); err != nil {
+				appLogger.Error("지연 작업 처리 실패", err)
 			}
+			timer.Reset(delayedJobInterval())
 		}
 	}
 }
 
-func runChatCleanupWorker(ctx context.Context, jobQueue *queue.Queue, chatService *services.ChatCleanupService, appLogger *logger.Logger) {
+func delayedJobInterval() time.Duration {
+	return time.Second + time.Duration(rand.Int63n(int64(500*time.Millisecond)))
+}
+
+func runQueueReaper(ctx context.Context, jobQueue *queue.Queue, appLogger *logger.Logger) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			job, err := jobQueue.Pop(ctx, queue.JobExpireChatRoom, 5*time.Second)
+		case <-ticker.C:
+			requeued, err := jobQueue.RequeueAllExpired(ctx)
 			if err != nil {
-				continue
+				appLogger.Error("처리 중 작업 회수 실패", err)
 			}
-
-			if err := chatService.ProcessChatRoomExpirationJob(ctx, job); err != nil {
-				appLogger.Error("채팅방 정리 실패", err)
-				if err := jobQueue.Retry(ctx, job, 5*time.Minute); err != nil {
-					appLogger.Error("채팅방 정리 재시도 실패", err)
-				}
+			if requeued > 0 {
+				appLogger.Warn(fmt.Sprintf("임대가 만료된 작업 %d건을 다시 대기열에 넣었습니다", requeued))
 			}
 		}
 	}
 }
 
-func runDelayedJobProcessor(ctx context.Context, jobQueue *queue.Queue, appLogger *logger.Logger) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+// drainPools 처리 중인 작업이 끝나기를 shutdownTimeout까지 기다림 (모두 끝났으면 finished)
+//
+// 시간이 지나면 처리를 취소하고, 취소에 응답한 처리 함수가 스스로 작업을 되돌리도록 cancelTimeout만큼 더 기다린 뒤
+// 그래도 끝나지 않은 작업을 다른 워커가 이어받도록 큐로 되돌리고 그 개수를 반환한다.
+func drainPools(wg *sync.WaitGroup, shutdownTimeout, cancelTimeout time.Duration, cancelJobs context.CancelFunc, inFlight *inFlightJobs, jobQueue *queue.Queue, appLogger *logger.Logger) (released int, finished bool) {
+	if waitTimeout(wg, shutdownTimeout) {
+		return 0, true
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if err := jobQueue.ProcessDelayedJobs(ctx); err != nil {
-				appLogger.Error("지연 작업 처리 실패", err)
-			}
-		}
+	cancelJobs()
+	if !waitTimeout(wg, cancelTimeout) {
+		appLogger.Warn(fmt.Sprintf("취소 후 %s 안에 끝나지 않은 작업이 있습니다", cancelTimeout))
 	}
-}
\ No newline at end of file
+
+	releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelRelease()
+	return inFlight.releaseAll(releaseCtx, jobQueue, appLogger), false
+}
+
+// waitTimeout wg가 끝나면 true, timeout이 먼저 지나면 false
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
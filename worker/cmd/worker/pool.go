@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"signal-module/pkg/config"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/queue"
+)
+
+// 작업 타입 간 우선순위 (전체 처리 슬롯이 부족하면 높은 쪽부터 처리)
+const (
+	priorityLow = iota
+	priorityNormal
+	priorityHigh
+)
+
+// poolSettings 작업 타입별 처리 설정
+type poolSettings struct {
+	Priority    int
+	Concurrency int     // 동시에 처리하는 작업 수
+	RateLimit   float64 // 초당 최대 처리 개수 (0이면 제한 없음)
+}
+
+// defaultPoolSettings 작업 타입별 기본값 (동시 처리 개수와 처리 속도는 WORKER_CONCURRENCY, WORKER_RATE_LIMITS로 변경)
+var defaultPoolSettings = map[queue.JobType]poolSettings{
+	queue.JobSendPushNotification: {Priority: priorityHigh, Concurrency: 8},
+	queue.JobExpireSignal:         {Priority: priorityHigh, Concurrency: 4},
+	queue.JobSendEmail:            {Priority: priorityNormal, Concurrency: 4},
+	queue.JobUpdateMannerScore:    {Priority: priorityNormal, Concurrency: 2},
+	queue.JobExpireChatRoom:       {Priority: priorityLow, Concurrency: 2},
+	queue.JobCleanupData:          {Priority: priorityLow, Concurrency: 1},
+}
+
+// poolSettingsFor 기본값에 설정 파일의 값을 덮어쓴 jobType 처리 설정
+func poolSettingsFor(jobType queue.JobType, cfg config.WorkerConfig) poolSettings {
+	settings, ok := defaultPoolSettings[jobType]
+	if !ok {
+		settings = poolSettings{Priority: priorityNormal, Concurrency: 1}
+	}
+	if concurrency, ok := cfg.Concurrency[string(jobType)]; ok && concurrency > 0 {
+		settings.Concurrency = concurrency
+	}
+	if rateLimit, ok := cfg.RateLimits[string(jobType)]; ok && rateLimit >= 0 {
+		settings.RateLimit = rateLimit
+	}
+	return settings
+}
+
+// jobPool 작업 타입 하나를 Concurrency개의 고루틴으로 처리
+//
+// 각 고루틴은 작업을 가져와 처리 속도 제한과 전체 처리 슬롯을 기다린 뒤 처리한다.
+// fetchCtx가 취소되면 새 작업을 가져오지 않고, 아직 처리를 시작하지 않은 작업은 큐로 되돌린다.
+// 처리 중인 작업은 jobCtx가 취소될 때까지 계속 실행된다.
+type jobPool struct {
+	queue    *queue.Queue
+	handler  *queue.Handler
+	settings poolSettings
+	slots    *prioritySemaphore
+	limiter  *rateLimiter
+	inFlight *inFlightJobs
+	logger   *logger.Logger
+}
+
+func newJobPool(jobQueue *queue.Queue, handler *queue.Handler, settings poolSettings, slots *prioritySemaphore, inFlight *inFlightJobs, appLogger *logger.Logger) *jobPool {
+	return &jobPool{
+		queue:    jobQueue,
+		handler:  handler,
+		settings: settings,
+		slots:    slots,
+		limiter:  newRateLimiter(settings.RateLimit),
+		inFlight: inFlight,
+		logger:   appLogger,
+	}
+}
+
+func (p *jobPool) run(fetchCtx, jobCtx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.settings.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.fetch(fetchCtx, jobCtx)
+		}()
+	}
+	wg.Wait()
+}
+
+// fetchBackoff Redis 오류로 작업을 가져오지 못했을 때 다시 시도하기까지 대기 시간
+var fetchBackoff = queue.Backoff{Base: 200 * time.Millisecond, Max: 10 * time.Second}
+
+func (p *jobPool) fetch(fetchCtx, jobCtx context.Context) {
+	failures := 0
+	for fetchCtx.Err() == nil {
+		job, err := p.queue.Pop(fetchCtx, p.handler.Type, 5*time.Second)
+		switch {
+		case err == nil:
+			failures = 0
+		case errors.Is(err, queue.ErrNoJob) || fetchCtx.Err() != nil:
+			failures = 0
+			continue
+		case errors.Is(err, queue.ErrInvalidJob):
+			p.logger.Error(p.handler.Name+" 가져온 작업을 읽을 수 없음", err)
+			continue
+		default:
+			// Redis 장애 중에 모든 고루틴이 쉬지 않고 재시도하지 않도록 간격을 늘려 가며 대기
+			failures++
+			delay := fetchBackoff.Delay(failures)
+			p.logger.Error(fmt.Sprintf("%s 작업 가져오기 실패 (%s 뒤 다시 시도)", p.handler.Name, delay.Round(time.Millisecond)), err)
+			sleepContext(fetchCtx, delay)
+			continue
+		}
+
+		p.processJob(fetchCtx, jobCtx, job)
+	}
+}
+
+// sleepContext d만큼 대기 (ctx가 취소되면 바로 반환)
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// processJob 처리하는 동안 작업 임대를 연장하고, 성공하면 완료 처리, 실패하면 재시도 예약
+func (p *jobPool) processJob(fetchCtx, jobCtx context.Context, job *queue.Job) {
+	// 종료 대기 시간이 지나 jobCtx가 취소된 뒤에도 결과는 기록
+	queueCtx := context.WithoutCancel(jobCtx)
+
+	// 이미 처리했거나(재전달) 취소/교체된 작업은 처리하지 않고 완료 처리
+	process, err := p.queue.ShouldProcess(queueCtx, job)
+	if err != nil {
+		p.logger.Warn(fmt.Sprintf("%s 작업 %s 중복 확인 실패: %v", p.handler.Name, job.ID, err))
+		process = true
+	}
+	if !process {
+		p.logger.Info(fmt.Sprintf("%s 작업 %s 건너뜀 (처리 완료 또는 취소됨)", p.handler.Name, job.ID))
+		if err := p.queue.Ack(queueCtx, job); err != nil {
+			p.logger.Error(p.handler.Name+" 완료 처리 실패", err)
+		}
+		return
+	}
+
+	stop := p.queue.KeepAlive(jobCtx, job, func(err error) {
+		p.logger.Warn(fmt.Sprintf("%s %v", p.handler.Name, err))
+	})
+	defer stop()
+
+	// 차례를 기다리는 동안 종료가 시작되면 시도 횟수를 늘리지 않고 큐로 되돌림
+	if err := p.limiter.wait(fetchCtx); err != nil {
+		p.release(queueCtx, job)
+		return
+	}
+	if err := p.slots.acquire(fetchCtx, p.settings.Priority); err != nil {
+		p.release(queueCtx, job)
+		return
+	}
+	defer p.slots.release()
+
+	p.inFlight.add(job)
+	err = p.handler.Process(jobCtx, job)
+	if !p.inFlight.remove(job) {
+		// 종료 대기 시간이 지나 이미 큐로 되돌린 작업
+		return
+	}
+
+	if err != nil && jobCtx.Err() != nil {
+		// 종료 대기 시간이 지나 중단된 작업은 실패로 세지 않고 다른 워커가 이어받도록 되돌림
+		p.release(queueCtx, job)
+		return
+	}
+	if err != nil {
+		p.logger.Error(p.handler.Name+" 처리 실패", err)
+		if err := p.queue.Fail(queueCtx, job, err, p.handler.Backoff); errors.Is(err, queue.ErrLeaseLost) {
+			p.logger.Warn(fmt.Sprintf("%s 작업 %s 임대가 만료되어 다시 전달된 쪽에서 처리합니다", p.handler.Name, job.ID))
+		} else if err != nil {
+			p.logger.Error(p.handler.Name+" 재시도 실패", err)
+		}
+		return
+	}
+
+	if err := p.queue.Ack(queueCtx, job); err != nil {
+		p.logger.Error(p.handler.Name+" 완료 처리 실패", err)
+	}
+}
+
+func (p *jobPool) release(ctx context.Context, job *queue.Job) {
+	if err := p.queue.Release(ctx, job); err != nil {
+		p.logger.Error(p.handler.Name+" 작업 반환 실패", err)
+	}
+}
+
+// inFlightJobs 처리 중인 작업 목록 (종료 대기 시간이 지나면 한꺼번에 큐로 되돌림)
+type inFlightJobs struct {
+	mu   sync.Mutex
+	jobs map[*queue.Job]struct{}
+}
+
+func newInFlightJobs() *inFlightJobs {
+	return &inFlightJobs{jobs: make(map[*queue.Job]struct{})}
+}
+
+func (f *inFlightJobs) add(job *queue.Job) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jobs[job] = struct{}{}
+}
+
+// remove 목록에서 제거 (이미 큐로 되돌린 작업이면 false)
+func (f *inFlightJobs) remove(job *queue.Job) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.jobs[job]; !ok {
+		return false
+	}
+	delete(f.jobs, job)
+	return true
+}
+
+// releaseAll 끝나지 않은 작업을 모두 큐로 되돌리고 개수 반환
+// (이후 처리 함수가 끝나도 결과는 기록하지 않으며, 완료된 작업이면 다음 처리 때 ShouldProcess가 걸러냄)
+func (f *inFlightJobs) releaseAll(ctx context.Context, jobQueue *queue.Queue, appLogger *logger.Logger) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	released := 0
+	for job := range f.jobs {
+		if err := jobQueue.Release(ctx, job); err != nil {
+			appLogger.Error(fmt.Sprintf("%s 작업 반환 실패", job.Type), err)
+		} else {
+			released++
+		}
+		delete(f.jobs, job)
+	}
+	return released
+}
+
+// prioritySemaphore 모든 작업 타입이 나눠 쓰는 처리 슬롯
+// (기다리는 작업이 있으면 우선순위가 높은 쪽, 같으면 먼저 기다린 쪽에 슬롯을 넘김)
+type prioritySemaphore struct {
+	mu      sync.Mutex
+	free    int
+	waiters []*slotWaiter
+}
+
+type slotWaiter struct {
+	priority int
+	ready    chan struct{}
+}
+
+// newPrioritySemaphore size가 0 이하이면 제한 없음 (nil)
+func newPrioritySemaphore(size int) *prioritySemaphore {
+	if size <= 0 {
+		return nil
+	}
+	return &prioritySemaphore{free: size}
+}
+
+func (s *prioritySemaphore) acquire(ctx context.Context, priority int) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.free > 0 {
+		s.free--
+		s.mu.Unlock()
+		return nil
+	}
+
+	waiter := &slotWaiter{priority: priority, ready: make(chan struct{})}
+	position := len(s.waiters)
+	for i, w := range s.waiters {
+		if w.priority < priority {
+			position = i
+			break
+		}
+	}
+	s.waiters = append(s.waiters, nil)
+	copy(s.waiters[position+1:], s.waiters[position:])
+	s.waiters[position] = waiter
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for i, w := range s.waiters {
+			if w == waiter {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				s.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		s.mu.Unlock()
+
+		// 취소와 동시에 슬롯을 넘겨받은 경우 다음 대기자에게 반납
+		s.release()
+		return ctx.Err()
+	}
+}
+
+func (s *prioritySemaphore) release() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.waiters) > 0 {
+		waiter := s.waiters[0]
+		s.waiters = s.waiters[1:]
+		close(waiter.ready)
+		return
+	}
+	s.free++
+}
+
+// rateLimiter 작업 시작 간격을 일정하게 유지 (워커 인스턴스마다 따로 적용)
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter perSecond가 0 이하이면 제한 없음 (nil)
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait 다음 작업을 시작할 수 있을 때까지 대기
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"signal-module/pkg/config"
+	"signal-module/pkg/logger"
+	"signal-module/pkg/queue"
+	"signal-module/pkg/redis"
+)
+
+func TestPrioritySemaphoreUnlimited(t *testing.T) {
+	slots := newPrioritySemaphore(0)
+	if slots != nil {
+		t.Fatal("크기 0이면 제한이 없어야 합니다")
+	}
+	for i := 0; i < 100; i++ {
+		if err := slots.acquire(context.Background(), priorityLow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slots.release()
+}
+
+func TestPrioritySemaphoreOrder(t *testing.T) {
+	slots := newPrioritySemaphore(1)
+	ctx := context.Background()
+	if err := slots.acquire(ctx, priorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	// 슬롯이 없는 동안 낮은, 보통, 높은 우선순위 순서로 대기 (같은 우선순위는 먼저 기다린 쪽부터)
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	waiters := []struct {
+		name     string
+		priority int
+	}{
+		{"low", priorityLow},
+		{"normal-1", priorityNormal},
+		{"high", priorityHigh},
+		{"normal-2", priorityNormal},
+	}
+	for i, w := range waiters {
+		wg.Add(1)
+		go func(name string, priority int) {
+			defer wg.Done()
+			if err := slots.acquire(ctx, priority); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			slots.release()
+		}(w.name, w.priority)
+		waitForWaiters(t, slots, i+1)
+	}
+
+	slots.release()
+	wg.Wait()
+
+	want := []string{"high", "normal-1", "normal-2", "low"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("슬롯을 받은 순서: %v (기대: %v)", order, want)
+		}
+	}
+	if slots.free != 1 {
+		t.Fatalf("모두 반납한 뒤 남은 슬롯: %d", slots.free)
+	}
+}
+
+func TestPrioritySemaphoreCancel(t *testing.T) {
+	slots := newPrioritySemaphore(1)
+	if err := slots.acquire(context.Background(), priorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- slots.acquire(ctx, priorityHigh) }()
+	waitForWaiters(t, slots, 1)
+	cancel()
+
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("취소된 대기: %v", err)
+	}
+	if len(slots.waiters) != 0 {
+		t.Fatalf("취소 후 남은 대기자: %d", len(slots.waiters))
+	}
+
+	// 취소한 대기자에게 슬롯이 넘어가지 않음
+	slots.release()
+	if slots.free != 1 {
+		t.Fatalf("반납 후 남은 슬롯: %d", slots.free)
+	}
+}
+
+// waitForWaiters 대기자가 count명이 될 때까지 대기
+func waitForWaiters(t *testing.T, slots *prioritySemaphore, count int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		slots.mu.Lock()
+		waiting := len(slots.waiters)
+		slots.mu.Unlock()
+		if waiting >= count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("대기자가 %d명이 되지 않았습니다", count)
+}
+
+func TestRateLimiter(t *testing.T) {
+	if limiter := newRateLimiter(0); limiter != nil {
+		t.Fatal("0이면 제한이 없어야 합니다")
+	}
+
+	// 초당 20개: 첫 작업은 바로, 이후 50ms 간격
+	limiter := newRateLimiter(20)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Fatalf("작업 5개 시작에 %s (최소 200ms)", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = newRateLimiter(0.1)
+	limiter.wait(ctx)
+	if err := limiter.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("취소된 대기: %v", err)
+	}
+}
+
+func TestInFlightJobsReleaseAll(t *testing.T) {
+	inFlight := newInFlightJobs()
+	var jobs []*queue.Job
+	for i := 0; i < 3; i++ {
+		job, err := queue.NewJob(queue.EmailPayload{To: "user@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		inFlight.add(job)
+		jobs = append(jobs, job)
+	}
+	if !inFlight.remove(jobs[0]) {
+		t.Fatal("처리 중인 작업을 제거하지 못했습니다")
+	}
+
+	// Pop으로 가져온 작업이 아니면 Release는 Redis에 접근하지 않음
+	if released := inFlight.releaseAll(context.Background(), queue.New(nil), logger.New("worker-test")); released != 2 {
+		t.Fatalf("되돌린 작업 %d건", released)
+	}
+
+	// 되돌린 작업은 처리 함수가 끝나도 결과를 기록하지 않음
+	if inFlight.remove(jobs[1]) || inFlight.remove(jobs[2]) {
+		t.Fatal("되돌린 작업이 목록에 남아 있습니다")
+	}
+}
+
+// 종료 테스트는 로컬 Redis의 테스트 전용 DB(REDIS_TEST_DB, 기본 14)를 비우고 실행한다.
+
+// newTestQueue 로컬 Redis 테스트 DB의 큐 (연결할 수 없으면 테스트 건너뜀)
+func newTestQueue(t *testing.T) *queue.Queue {
+	t.Helper()
+
+	cfg := &config.RedisConfig{Host: "localhost", Port: "6379", DB: 14}
+	if host := os.Getenv("REDIS_HOST"); host != "" {
+		cfg.Host = host
+	}
+	if port := os.Getenv("REDIS_PORT"); port != "" {
+		cfg.Port = port
+	}
+	if db, err := strconv.Atoi(os.Getenv("REDIS_TEST_DB")); err == nil {
+		cfg.DB = db
+	}
+
+	rdb, err := redis.New(cfg)
+	if err != nil {
+		t.Skipf("Redis를 사용할 수 없어 건너뜁니다: %v", err)
+	}
+	flush := func() {
+		if err := rdb.GetClient().FlushDB(context.Background()).Err(); err != nil {
+			t.Fatalf("테스트 DB 비우기 실패: %v", err)
+		}
+	}
+	flush()
+	t.Cleanup(func() {
+		flush()
+		rdb.Close()
+	})
+	return queue.New(rdb)
+}
+
+// drainTest 이메일 작업 하나를 처리 중인 풀 (process는 처리 함수)
+type drainTest struct {
+	queue     *queue.Queue
+	inFlight  *inFlightJobs
+	wg        sync.WaitGroup
+	stopFetch context.CancelFunc
+	cancelJob context.CancelFunc
+	started   chan struct{}
+	logger    *logger.Logger
+}
+
+func startDrainTest(t *testing.T, process func(ctx context.Context) error) *drainTest {
+	t.Helper()
+
+	d := &drainTest{
+		queue:    newTestQueue(t),
+		inFlight: newInFlightJobs(),
+		started:  make(chan struct{}),
+		logger:   logger.New("worker-test"),
+	}
+	if err := queue.Enqueue(context.Background(), d.queue, queue.EmailPayload{To: "user@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := queue.NewRegistry()
+	queue.Handle(registry, "이메일", queue.Backoff{}, func(ctx context.Context, payload queue.EmailPayload) error {
+		close(d.started)
+		return process(ctx)
+	})
+	handler, _ := registry.Lookup(queue.JobSendEmail)
+	pool := newJobPool(d.queue, handler, poolSettings{Priority: priorityNormal, Concurrency: 1}, nil, d.inFlight, d.logger)
+
+	fetchCtx, stopFetch := context.WithCancel(context.Background())
+	jobCtx, cancelJob := context.WithCancel(context.Background())
+	d.stopFetch, d.cancelJob = stopFetch, cancelJob
+	t.Cleanup(cancelJob)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		pool.run(fetchCtx, jobCtx)
+	}()
+
+	select {
+	case <-d.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("작업 처리가 시작되지 않았습니다")
+	}
+	d.stopFetch()
+	return d
+}
+
+func (d *drainTest) drain(shutdownTimeout, cancelTimeout time.Duration) (int, bool) {
+	return drainPools(&d.wg, shutdownTimeout, cancelTimeout, d.cancelJob, d.inFlight, d.queue, d.logger)
+}
+
+func (d *drainTest) stats(t *testing.T) map[string]int64 {
+	t.Helper()
+
+	stats, err := d.queue.GetQueueStats(context.Background(), queue.JobSendEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
+
+func TestDrainWaitsForRunningJob(t *testing.T) {
+	d := startDrainTest(t, func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+
+	if released, finished := d.drain(5*time.Second, time.Second); !finished || released != 0 {
+		t.Fatalf("종료 대기: %d건 되돌림, 완료 %v", released, finished)
+	}
+	if stats := d.stats(t); stats["pending"] != 0 || stats["processing"] != 0 || stats["delayed"] != 0 {
+		t.Fatalf("처리를 마친 뒤 큐 상태: %v", stats)
+	}
+}
+
+func TestDrainCancelledJobReturnsItself(t *testing.T) {
+	d := startDrainTest(t, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	// 취소에 응답한 처리 함수는 실패로 세지 않고 스스로 큐로 되돌림
+	if released, finished := d.drain(50*time.Millisecond, 5*time.Second); finished || released != 0 {
+		t.Fatalf("종료 대기: %d건 되돌림, 완료 %v", released, finished)
+	}
+	if stats := d.stats(t); stats["pending"] != 1 || stats["processing"] != 0 || stats["delayed"] != 0 {
+		t.Fatalf("취소 후 큐 상태: %v", stats)
+	}
+}
+
+func TestDrainReleasesStuckJob(t *testing.T) {
+	unblock := make(chan struct{})
+	d := startDrainTest(t, func(ctx context.Context) error {
+		<-unblock
+		return errors.New("늦게 끝난 처리")
+	})
+
+	// 취소에도 끝나지 않는 작업은 종료하면서 큐로 되돌림
+	if released, finished := d.drain(50*time.Millisecond, 50*time.Millisecond); finished || released != 1 {
+		t.Fatalf("종료 대기: %d건 되돌림, 완료 %v", released, finished)
+	}
+	if stats := d.stats(t); stats["pending"] != 1 || stats["processing"] != 0 {
+		t.Fatalf("되돌린 뒤 큐 상태: %v", stats)
+	}
+
+	// 나중에 처리 함수가 끝나도 재시도를 예약하지 않음
+	close(unblock)
+	d.wg.Wait()
+	if stats := d.stats(t); stats["pending"] != 1 || stats["delayed"] != 0 || stats["dead"] != 0 {
+		t.Fatalf("처리 함수가 끝난 뒤 큐 상태: %v", stats)
+	}
+}
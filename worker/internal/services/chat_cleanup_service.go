@@ -2,41 +2,45 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"signal-module/pkg/encryption"
 	"signal-module/pkg/logger"
 	"signal-module/pkg/models"
 	"signal-module/pkg/queue"
+	"signal-module/pkg/storage"
 
 	"gorm.io/gorm"
 )
 
 type ChatCleanupService struct {
-	db     *gorm.DB
-	logger *logger.Logger
+	db               *gorm.DB
+	blobStore        storage.BlobStore
+	archiveKey       []byte
+	archiveRetention time.Duration
+	logger           *logger.Logger
 }
 
-func NewChatCleanupService(db *gorm.DB, logger *logger.Logger) *ChatCleanupService {
+func NewChatCleanupService(db *gorm.DB, blobStore storage.BlobStore, archiveKey []byte, archiveRetention time.Duration, logger *logger.Logger) *ChatCleanupService {
 	return &ChatCleanupService{
-		db:     db,
-		logger: logger,
+		db:               db,
+		blobStore:        blobStore,
+		archiveKey:       archiveKey,
+		archiveRetention: archiveRetention,
+		logger:           logger,
 	}
 }
 
-func (s *ChatCleanupService) ProcessChatRoomExpirationJob(ctx context.Context, job *queue.Job) error {
-	chatRoomID, ok := job.Payload["chat_room_id"].(float64)
-	if !ok {
-		return fmt.Errorf("잘못된 chat_room_id 형식")
-	}
-
-	roomID := uint(chatRoomID)
+func (s *ChatCleanupService) ProcessChatRoomExpirationJob(ctx context.Context, payload queue.ExpireChatRoomPayload) error {
+	roomID := payload.ChatRoomID
 
 	s.logger.Info(fmt.Sprintf("채팅방 만료 처리 시작: %d", roomID))
 
-	// 채팅방 조회
+	// 채팅방 조회 (WebSocket 서버가 만료 시점에 먼저 숨겼을 수 있음)
 	var chatRoom models.ChatRoom
-	if err := s.db.First(&chatRoom, roomID).Error; err != nil {
+	if err := s.db.Unscoped().First(&chatRoom, roomID).Error; err != nil {
 		return fmt.Errorf("채팅방 조회 실패: %w", err)
 	}
 
@@ -52,36 +56,181 @@ func (s *ChatCleanupService) ProcessChatRoomExpirationJob(ctx context.Context, j
 		return nil
 	}
 
+	// 첨부파일 원본은 커밋 후 삭제하기 위해 미리 조회
+	var attachments []models.ChatAttachment
+	if err := s.db.Where("chat_room_id = ?", roomID).Find(&attachments).Error; err != nil {
+		return fmt.Errorf("첨부파일 조회 실패: %w", err)
+	}
+
+	// 신고가 접수된 채팅방만 대화를 보관
+	reportIDs, err := s.findReports(&chatRoom)
+	if err != nil {
+		return err
+	}
+
 	// 트랜잭션으로 채팅방과 메시지 정리
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
 		// 1. 채팅방 상태를 만료로 변경
-		if err := tx.Model(&chatRoom).Update("status", models.ChatRoomExpired).Error; err != nil {
+		if err := tx.Unscoped().Model(&chatRoom).Update("status", models.ChatRoomExpired).Error; err != nil {
 			return fmt.Errorf("채팅방 상태 업데이트 실패: %w", err)
 		}
 
-		// 2. 모든 메시지 소프트 삭제
-		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatMessage{}).Error; err != nil {
+		// 2. 신고된 채팅방은 대화를 암호화해 보관한 뒤 모든 메시지 완전 삭제
+		if len(reportIDs) > 0 {
+			if err := s.archiveTranscript(tx, &chatRoom, reportIDs); err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Where("chat_room_id = ?", roomID).Delete(&models.ChatMessage{}).Error; err != nil {
 			return fmt.Errorf("채팅 메시지 삭제 실패: %w", err)
 		}
 
-		// 3. 채팅방 소프트 삭제
-		if err := tx.Delete(&chatRoom).Error; err != nil {
-			return fmt.Errorf("채팅방 삭제 실패: %w", err)
+		// 3. 읽음 상태, 알림 설정 및 채팅 제한 삭제
+		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatReadState{}).Error; err != nil {
+			return fmt.Errorf("읽음 상태 삭제 실패: %w", err)
+		}
+		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatRoomMute{}).Error; err != nil {
+			return fmt.Errorf("채팅방 알림 설정 삭제 실패: %w", err)
+		}
+		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatParticipantMute{}).Error; err != nil {
+			return fmt.Errorf("채팅 제한 삭제 실패: %w", err)
+		}
+		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatExtendVote{}).Error; err != nil {
+			return fmt.Errorf("연장 투표 삭제 실패: %w", err)
+		}
+
+		// 4. 투표 삭제
+		polls := tx.Model(&models.ChatPoll{}).Select("id").Where("chat_room_id = ?", roomID)
+		if err := tx.Where("poll_id IN (?)", polls).Delete(&models.ChatPollVote{}).Error; err != nil {
+			return fmt.Errorf("투표 기록 삭제 실패: %w", err)
+		}
+		if err := tx.Where("poll_id IN (?)", polls).Delete(&models.ChatPollOption{}).Error; err != nil {
+			return fmt.Errorf("투표 선택지 삭제 실패: %w", err)
+		}
+		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatPoll{}).Error; err != nil {
+			return fmt.Errorf("투표 삭제 실패: %w", err)
+		}
+
+		// 5. 첨부파일 정보 삭제
+		if err := tx.Where("chat_room_id = ?", roomID).Delete(&models.ChatAttachment{}).Error; err != nil {
+			return fmt.Errorf("첨부파일 삭제 실패: %w", err)
+		}
+
+		// 6. 채팅방 소프트 삭제 (사용자에게 숨김)
+		if !chatRoom.DeletedAt.Valid {
+			if err := tx.Delete(&chatRoom).Error; err != nil {
+				return fmt.Errorf("채팅방 삭제 실패: %w", err)
+			}
 		}
 
 		s.logger.LogChatRoomExpired(ctx, roomID)
-		s.logger.Info(fmt.Sprintf("채팅방 만료 처리 완료: %d", roomID))
+		s.logger.Info(fmt.Sprintf("채팅방 만료 처리 완료: %d (보관: %t)", roomID, len(reportIDs) > 0))
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteAttachmentBlobs(ctx, attachments)
+	return nil
+}
+
+// findReports 채팅방의 시그널 또는 메시지를 대상으로 한 신고
+func (s *ChatCleanupService) findReports(chatRoom *models.ChatRoom) ([]uint, error) {
+	messages := s.db.Unscoped().Model(&models.ChatMessage{}).Select("id").Where("chat_room_id = ?", chatRoom.ID)
+
+	var reportIDs []uint
+	if err := s.db.Model(&models.ReportUser{}).
+		Where("signal_id = ? OR chat_message_id IN (?)", chatRoom.SignalID, messages).
+		Pluck("id", &reportIDs).Error; err != nil {
+		return nil, fmt.Errorf("채팅방 신고 조회 실패: %w", err)
+	}
+	return reportIDs, nil
+}
+
+// archiveTranscript 삭제된 메시지를 포함한 전체 대화를 암호화해 보관 기간 동안 저장
+func (s *ChatCleanupService) archiveTranscript(tx *gorm.DB, chatRoom *models.ChatRoom, reportIDs []uint) error {
+	var messages []models.ChatMessage
+	if err := tx.Unscoped().
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Attachment").
+		Preload("Poll").
+		Where("chat_room_id = ?", chatRoom.ID).
+		Order("seq ASC, id ASC").
+		Find(&messages).Error; err != nil {
+		return fmt.Errorf("보관할 메시지 조회 실패: %w", err)
+	}
+
+	transcript := &models.ChatTranscript{
+		ChatRoomID:  chatRoom.ID,
+		SignalID:    chatRoom.SignalID,
+		Name:        chatRoom.Name,
+		GeneratedAt: time.Now(),
+		ReportIDs:   reportIDs,
+		Messages:    make([]models.ChatTranscriptMessage, 0, len(messages)),
+	}
+	for i := range messages {
+		transcript.Messages = append(transcript.Messages, messages[i].ToTranscript())
+	}
+
+	plaintext, err := json.Marshal(transcript)
+	if err != nil {
+		return fmt.Errorf("대화 기록 직렬화 실패: %w", err)
+	}
+	sealed, err := encryption.Encrypt(s.archiveKey, plaintext)
+	if err != nil {
+		return fmt.Errorf("대화 기록 암호화 실패: %w", err)
+	}
+
+	archive := &models.ChatArchive{
+		ChatRoomID:     chatRoom.ID,
+		SignalID:       chatRoom.SignalID,
+		MessageCount:   len(messages),
+		KeyFingerprint: encryption.KeyFingerprint(s.archiveKey),
+		Sealed:         sealed,
+		RetainUntil:    time.Now().Add(s.archiveRetention),
+	}
+	if err := tx.Create(archive).Error; err != nil {
+		return fmt.Errorf("대화 기록 보관 실패: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("채팅방 %d 대화 보관: 메시지 %d개, 신고 %d건, %s까지", chatRoom.ID, len(messages), len(reportIDs), archive.RetainUntil.Format("2006-01-02")))
+	return nil
+}
+
+// PurgeExpiredArchives 보관 기간이 지난 대화 기록 삭제
+func (s *ChatCleanupService) PurgeExpiredArchives(ctx context.Context) error {
+	result := s.db.WithContext(ctx).Where("retain_until < ?", time.Now()).Delete(&models.ChatArchive{})
+	if result.Error != nil {
+		return fmt.Errorf("보관 기간이 지난 대화 기록 삭제 실패: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		s.logger.Info(fmt.Sprintf("보관 기간이 지난 대화 기록 %d건 삭제", result.RowsAffected))
+	}
+	return nil
+}
+
+// deleteAttachmentBlobs 저장소의 원본과 썸네일 삭제 (실패해도 만료 처리는 완료된 것으로 봄)
+func (s *ChatCleanupService) deleteAttachmentBlobs(ctx context.Context, attachments []models.ChatAttachment) {
+	for _, attachment := range attachments {
+		for _, key := range []string{attachment.ObjectKey, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := s.blobStore.Delete(ctx, key); err != nil {
+				s.logger.Error(fmt.Sprintf("첨부파일 %d 저장소 삭제 실패 (%s)", attachment.ID, key), err)
+			}
+		}
+	}
 }
 
 // 만료된 채팅방들을 일괄 정리하는 배치 작업
 func (s *ChatCleanupService) CleanupExpiredChatRooms(ctx context.Context) error {
 	var expiredRooms []models.ChatRoom
 
-	// 만료 시간이 지난 활성 채팅방들 조회
-	if err := s.db.Where("status = ? AND expires_at < ?", models.ChatRoomActive, time.Now()).Find(&expiredRooms).Error; err != nil {
+	// 만료 시간이 지난 활성 채팅방들 조회 (WebSocket 서버가 숨긴 채팅방 포함)
+	if err := s.db.Unscoped().Where("status = ? AND expires_at < ?", models.ChatRoomActive, time.Now()).Find(&expiredRooms).Error; err != nil {
 		return fmt.Errorf("만료된 채팅방 조회 실패: %w", err)
 	}
 
@@ -93,14 +242,7 @@ func (s *ChatCleanupService) CleanupExpiredChatRooms(ctx context.Context) error
 
 	// 각 채팅방별로 정리 작업 수행
 	for _, room := range expiredRooms {
-		job := &queue.Job{
-			Type: queue.JobExpireChatRoom,
-			Payload: map[string]interface{}{
-				"chat_room_id": float64(room.ID),
-			},
-		}
-
-		if err := s.ProcessChatRoomExpirationJob(ctx, job); err != nil {
+		if err := s.ProcessChatRoomExpirationJob(ctx, queue.ExpireChatRoomPayload{ChatRoomID: room.ID}); err != nil {
 			s.logger.Error(fmt.Sprintf("채팅방 %d 정리 실패", room.ID), err)
 			continue
 		}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/queue"
+	"signal-module/pkg/redis"
+
+	"gorm.io/gorm"
+)
+
+// DataCleanupService 주기적으로 예약되는 데이터 정리 작업 (범위별로 한 번에 하나만 실행)
+type DataCleanupService struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+	chatService *ChatCleanupService
+	logger      *logger.Logger
+}
+
+func NewDataCleanupService(db *gorm.DB, redisClient *redis.Client, chatService *ChatCleanupService, logger *logger.Logger) *DataCleanupService {
+	return &DataCleanupService{
+		db:          db,
+		redisClient: redisClient,
+		chatService: chatService,
+		logger:      logger,
+	}
+}
+
+func (s *DataCleanupService) ProcessCleanupDataJob(ctx context.Context, payload queue.CleanupDataPayload) error {
+	switch payload.Scope {
+	case queue.CleanupChatArchives:
+		return s.chatService.PurgeExpiredArchives(ctx)
+	case queue.CleanupActiveSignals:
+		return s.cleanupActiveSignals(ctx)
+	default:
+		return fmt.Errorf("알 수 없는 정리 범위: %s", payload.Scope)
+	}
+}
+
+// cleanupActiveSignals 종료/취소/삭제되었는데 지도 인덱스에 남은 시그널 제거
+// (상태 변경 후 Redis 반영이 실패한 경우)
+func (s *DataCleanupService) cleanupActiveSignals(ctx context.Context) error {
+	ids, err := s.redisClient.ActiveSignalIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("활성 시그널 목록 조회 실패: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var activeIDs []uint
+	if err := s.db.WithContext(ctx).Model(&models.Signal{}).
+		Where("id IN ? AND status IN ?", ids, []models.SignalStatus{models.SignalActive, models.SignalFull}).
+		Pluck("id", &activeIDs).Error; err != nil {
+		return fmt.Errorf("시그널 상태 조회 실패: %w", err)
+	}
+
+	active := make(map[uint]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+
+	removed := 0
+	for _, id := range ids {
+		if active[id] {
+			continue
+		}
+		if err := s.redisClient.RemoveActiveSignal(ctx, id); err != nil {
+			return fmt.Errorf("시그널 %d 제거 실패: %w", id, err)
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.logger.Info(fmt.Sprintf("지도 인덱스에 남은 시그널 %d개 정리", removed))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/queue"
+
+	"gorm.io/gorm"
+)
+
+// MannerScoreService 평가를 받은 사용자 한 명의 매너 점수 재계산
+// (스케줄러의 전체 재계산과 같은 기준, 평가 직후 바로 반영)
+type MannerScoreService struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+func NewMannerScoreService(db *gorm.DB, logger *logger.Logger) *MannerScoreService {
+	return &MannerScoreService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (s *MannerScoreService) ProcessUpdateMannerScoreJob(ctx context.Context, payload queue.UpdateMannerScorePayload) error {
+	var ratings []models.UserRating
+	since := time.Now().Add(-models.MannerScoreWindow)
+	if err := s.db.WithContext(ctx).Where("ratee_id = ? AND created_at > ?", payload.UserID, since).Find(&ratings).Error; err != nil {
+		return fmt.Errorf("평가 조회 실패: %w", err)
+	}
+
+	score, noShowCount, ok := models.CalculateMannerScore(ratings)
+	if !ok {
+		return nil
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.UserProfile{}).Where("user_id = ?", payload.UserID).Updates(map[string]interface{}{
+		"manner_score":  score,
+		"total_ratings": len(ratings),
+		"no_show_count": noShowCount,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("매너 점수 업데이트 실패: %w", result.Error)
+	}
+
+	s.logger.Info(fmt.Sprintf("사용자 %d 매너 점수 재계산: %.2f (평가 %d건, 노쇼 %d회)", payload.UserID, score, len(ratings), noShowCount))
+	return nil
+}
@@ -21,43 +21,22 @@ func NewPushNotificationService(config *config.Config, logger *logger.Logger) *P
 	}
 }
 
-func (s *PushNotificationService) ProcessPushNotificationJob(ctx context.Context, job *queue.Job) error {
-	userIDs, ok := job.Payload["user_ids"].([]interface{})
-	if !ok {
-		return fmt.Errorf("잘못된 user_ids 형식")
+func (s *PushNotificationService) ProcessPushNotificationJob(ctx context.Context, payload queue.PushNotificationPayload) error {
+	if len(payload.UserIDs) == 0 {
+		return nil
 	}
 
-	title, ok := job.Payload["title"].(string)
-	if !ok {
-		return fmt.Errorf("잘못된 title 형식")
-	}
-
-	body, ok := job.Payload["body"].(string)
-	if !ok {
-		return fmt.Errorf("잘못된 body 형식")
-	}
-
-	dataPayload, _ := job.Payload["data"].(map[string]string)
-
-	// 사용자 ID 변환
-	targetUserIDs := make([]uint, len(userIDs))
-	for i, userID := range userIDs {
-		if id, ok := userID.(float64); ok {
-			targetUserIDs[i] = uint(id)
-		}
-	}
-
-	s.logger.Info(fmt.Sprintf("푸시 알림 발송 시작: %d명의 사용자", len(targetUserIDs)))
+	s.logger.Info(fmt.Sprintf("푸시 알림 발송 시작: %d명의 사용자", len(payload.UserIDs)))
 
 	// 실제 푸시 알림 발송 로직
-	for _, userID := range targetUserIDs {
-		if err := s.sendPushToUser(ctx, userID, title, body, dataPayload); err != nil {
+	for _, userID := range payload.UserIDs {
+		if err := s.sendPushToUser(ctx, userID, payload.Title, payload.Body, payload.Data); err != nil {
 			s.logger.Error(fmt.Sprintf("사용자 %d 푸시 알림 발송 실패", userID), err)
 			continue
 		}
 	}
 
-	s.logger.LogPushNotificationSent(ctx, targetUserIDs, title)
+	s.logger.LogPushNotificationSent(ctx, payload.UserIDs, payload.Title)
 	return nil
 }
 
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"signal-module/pkg/logger"
+	"signal-module/pkg/models"
+	"signal-module/pkg/queue"
+	"signal-module/pkg/redis"
+	"signal-module/pkg/signals"
+
+	"gorm.io/gorm"
+)
+
+// SignalExpirationService 시그널 생성 시 예약된 만료 작업을 만료 시각에 맞춰 처리
+// (스케줄러의 주기적 만료 처리는 놓친 시그널을 위한 안전장치)
+type SignalExpirationService struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+	logger      *logger.Logger
+}
+
+func NewSignalExpirationService(db *gorm.DB, redisClient *redis.Client, logger *logger.Logger) *SignalExpirationService {
+	return &SignalExpirationService{
+		db:          db,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+func (s *SignalExpirationService) ProcessExpireSignalJob(ctx context.Context, payload queue.ExpireSignalPayload) error {
+	var signal models.Signal
+	if err := s.db.WithContext(ctx).First(&signal, payload.SignalID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			s.logger.Info(fmt.Sprintf("삭제된 시그널의 만료 작업: %d", payload.SignalID))
+			return nil
+		}
+		return fmt.Errorf("시그널 조회 실패: %w", err)
+	}
+
+	// 이미 종료/취소되었거나 일정이 바뀌어 만료 시각이 미뤄진 시그널
+	if signal.Status != models.SignalActive && signal.Status != models.SignalFull {
+		return nil
+	}
+	if time.Now().Before(signal.ExpiresAt) {
+		s.logger.Info(fmt.Sprintf("아직 만료되지 않은 시그널: %d", signal.ID))
+		return nil
+	}
+
+	// 스케줄러가 동시에 닫는 경우 한쪽만 이벤트를 발행
+	return signals.CloseExpired(ctx, s.db, s.redisClient, &signal, s.logger)
+}